@@ -18,6 +18,7 @@ import (
 	"github.com/kenmobility/git-api-service/internal/repository/postgres"
 	"github.com/kenmobility/git-api-service/internal/usecases"
 	"github.com/kenmobility/git-api-service/pkg/message"
+	"github.com/kenmobility/git-api-service/pkg/secrets"
 	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
 )
@@ -51,14 +52,45 @@ func main() {
 	// Initialize various layers
 	commitRepository := postgres.NewPostgresGitCommitRepository(db)
 	repoMetadataRepository := postgres.NewPostgresGitRepoMetadataRepository(db)
+	httpValidatorRepository := postgres.NewPostgresHTTPValidatorRepository(db)
+	branchRepository := postgres.NewPostgresBranchRepository(db)
+	releaseRepository := postgres.NewPostgresReleaseRepository(db)
+	pullRequestRepository := postgres.NewPostgresPullRequestRepository(db)
+	webhookDeliveryRepository := postgres.NewPostgresWebhookDeliveryRepository(db)
+	repoSnapshotRepository := postgres.NewPostgresRepoSnapshotRepository(db)
+	jobRepository := postgres.NewPostgresJobRepository(db)
+	repoLeaseRepository := postgres.NewPostgresRepoLeaseRepository(db)
+
+	var credentialsCipher *secrets.Cipher
+	if len(config.CredentialsEncryptionKey) > 0 {
+		credentialsCipher, err = secrets.NewCipher(config.CredentialsEncryptionKey)
+		if err != nil {
+			log.Fatal().Msgf("failed to set up credentials encryption: %v, (%v)", err.Error(), err.Error())
+		}
+	}
+	credentialRepository := postgres.NewPostgresCredentialRepository(db, credentialsCipher)
 
-	gitClient := git.NewGitHubClient(config.GitHubApiBaseURL, config.GitHubToken, config.FetchInterval)
+	gitClients, err := git.NewProviderRegistry(*config, httpValidatorRepository, credentialRepository)
+	if err != nil {
+		log.Fatal().Msgf("failed to set up git providers: %v, (%v)", err.Error(), err.Error())
+	}
 
-	gitCommitUsecase := usecases.NewManageGitCommitUsecase(commitRepository, repoMetadataRepository)
-	gitRepositoryUsecase := usecases.NewGitRepositoryUsecase(repoMetadataRepository, commitRepository, gitClient, *config)
+	gitCommitUsecase := usecases.NewManageGitCommitUsecase(commitRepository, repoMetadataRepository, pullRequestRepository)
+	gitReleaseUsecase := usecases.NewManageReleaseUsecase(releaseRepository, repoMetadataRepository)
+	gitPullRequestUsecase := usecases.NewManagePullRequestUsecase(pullRequestRepository, repoMetadataRepository)
+	webhookUsecase := usecases.NewWebhookUsecase(repoMetadataRepository, commitRepository, branchRepository, webhookDeliveryRepository, jobRepository, *config)
+	gitRepositoryUsecase := usecases.NewGitRepositoryUsecase(repoMetadataRepository, commitRepository, branchRepository, releaseRepository, pullRequestRepository,
+		repoSnapshotRepository, credentialRepository, jobRepository, repoLeaseRepository, gitClients, *config)
+	credentialUsecase := usecases.NewManageCredentialUsecase(credentialRepository)
+	jobUsecase := usecases.NewManageJobUsecase(jobRepository)
 
 	commitHandler := handlers.NewCommitHandler(gitCommitUsecase)
 	repositoryHandler := handlers.NewRepositoryHandler(gitRepositoryUsecase)
+	releaseHandler := handlers.NewReleaseHandler(gitReleaseUsecase)
+	pullRequestHandler := handlers.NewPullRequestHandler(gitPullRequestUsecase)
+	webhookHandler := handlers.NewWebhookHandler(webhookUsecase)
+	credentialHandler := handlers.NewCredentialHandler(credentialUsecase)
+	jobHandler := handlers.NewJobHandler(jobUsecase)
 
 	//seed default repo
 	err = seedDefaultRepository(config, gitRepositoryUsecase)
@@ -71,6 +103,11 @@ func main() {
 	// register routes
 	routes.CommitRoutes(ginEngine, commitHandler)
 	routes.RepositoryRoutes(ginEngine, repositoryHandler)
+	routes.ReleaseRoutes(ginEngine, releaseHandler)
+	routes.PullRequestRoutes(ginEngine, pullRequestHandler)
+	routes.WebhookRoutes(ginEngine, webhookHandler)
+	routes.CredentialRoutes(ginEngine, credentialHandler)
+	routes.JobRoutes(ginEngine, jobHandler)
 
 	server := &http.Server{
 		Addr:    fmt.Sprintf("%s:%s", config.Address, config.Port),
@@ -81,18 +118,41 @@ func main() {
 	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
 	defer stop()
 
-	// Resume repo commits fetching for all saved repositories
-	go gitRepositoryUsecase.ResumeFetching(ctx)
+	// Run the queued indexing and monitoring jobs on a bounded pool of workers, the workers of all the instances
+	// share the queue and each repository is synced by a single job at a time
+	jobsDone := make(chan struct{})
+	go func() {
+		gitRepositoryUsecase.ProcessJobs(ctx)
+		close(jobsDone)
+	}()
+
+	// The scheduler runs on the single instance elected leader, it moves to another instance when the leader stops
+	leaderElector := database.NewLeaderElector(db, database.SchedulerLockKey, config.LeaderElectionInterval)
+	go leaderElector.Run(ctx, func(ctx context.Context) {
+		// Queue the monitoring of all saved repositories, and the indexing of the ones not fully indexed
+		if err := gitRepositoryUsecase.ResumeFetching(ctx); err != nil {
+			log.Err(err).Msg("error resuming the fetching of the saved repositories")
+		}
+
+		// Fetch the change statistics and files of indexed commits in the background
+		enrichmentDone := make(chan struct{})
+		go func() {
+			gitRepositoryUsecase.EnrichCommits(ctx)
+			close(enrichmentDone)
+		}()
+
+		// Refresh repository metadata and keep a snapshot of it on each refresh
+		gitRepositoryUsecase.RefreshMetadata(ctx)
+		<-enrichmentDone
+	})
 
 	go func() {
 		for {
 			select {
 			case <-ctx.Done():
 				log.Warn().Msg("Program is shutting down...")
-				// Call method to set isFetching to false in DB
-				if err := repoMetadataRepository.UpdateFetchingStateForAllRepos(context.Background(), false); err != nil {
-					log.Err(err).Msgf("Error updating isFetching to false: %v", err)
-				}
+				// wait for the workers to release their jobs, which resume on the next start
+				<-jobsDone
 				os.Exit(0)
 			default:
 				time.Sleep(5 * time.Second)
@@ -108,7 +168,7 @@ func main() {
 
 // seedDefaultRepository seeds a default repository to database
 func seedDefaultRepository(config *config.Config, repositoryUsecase usecases.GitRepositoryUsecase) error {
-	repo, err := repositoryUsecase.StartIndexing(context.Background(), config.DefaultRepository)
+	repo, err := repositoryUsecase.StartIndexing(context.Background(), config.DefaultRepository, nil, "", "")
 	if err != nil && err != message.ErrNoRecordFound {
 		return err
 	}
@@ -1,32 +1,75 @@
 package config
 
 import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/url"
 	"os"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/joho/godotenv"
 	"github.com/kenmobility/git-api-service/pkg/helpers"
+	"github.com/kenmobility/git-api-service/pkg/secrets"
 	"github.com/rs/zerolog/log"
 	"gopkg.in/go-playground/validator.v9"
 )
 
 type Config struct {
-	AppEnv                string
-	GitHubToken           string
-	DatabaseHost          string `validate:"required"`
-	DatabasePort          string `validate:"required"`
-	DatabaseUser          string `validate:"required"`
-	DatabasePassword      string `validate:"required"`
-	DatabaseName          string `validate:"required"`
-	FetchInterval         time.Duration
-	GitCommitFetchPerPage int
-	GitHubApiBaseURL      string
-	DefaultStartDate      time.Time
-	DefaultEndDate        time.Time
-	DefaultRepository     string `validate:"required"`
-	Address               string
-	Port                  string
+	AppEnv                  string
+	GitHubTokens            []string
+	GitHubAppID             int64
+	GitHubAppPrivateKey     []byte
+	DatabaseHost            string `validate:"required"`
+	DatabasePort            string `validate:"required"`
+	DatabaseUser            string `validate:"required"`
+	DatabasePassword        string `validate:"required"`
+	DatabaseName            string `validate:"required"`
+	FetchInterval           time.Duration
+	EnrichmentInterval      time.Duration
+	MetadataRefreshInterval time.Duration
+	GitCommitFetchPerPage   int
+	GitHubApiBaseURL        string
+	GitHubHost              string
+	GitHubFetchMode         string `validate:"oneof=rest graphql"`
+	GitHubGraphQLURL        string
+	GitHubWebhookSecret     string
+	// CredentialsEncryptionKey encrypts the credentials of repositories at rest, they are rejected when it is empty
+	CredentialsEncryptionKey []byte
+	GitLabToken              string
+	GitLabApiBaseURL         string
+	BitbucketUsername        string
+	BitbucketAppPassword     string
+	BitbucketApiBaseURL      string
+	BitbucketHost            string
+	GiteaInstances           []GiteaInstance
+	DefaultStartDate         time.Time
+	DefaultEndDate           time.Time
+	DefaultRepository        string `validate:"required"`
+	Address                  string
+	Port                     string
+	// JobWorkers is how many queued jobs, eg indexing and monitoring repositories, run at the same time
+	JobWorkers int
+	// JobPollInterval is how often idle workers look for a job to claim
+	JobPollInterval time.Duration
+	// JobVisibilityTimeout is how long a claimed job stays hidden from other workers without a heartbeat
+	JobVisibilityTimeout time.Duration
+	// JobMaxAttempts is how many times a failing job runs before it is dead-lettered
+	JobMaxAttempts int
+	// LeaderElectionInterval is how often a replica campaigns for the scheduler leadership and its leader checks it still holds it
+	LeaderElectionInterval time.Duration
+	// RepoLeaseTTL is how long the lease of a replica on a repository lasts without a heartbeat
+	RepoLeaseTTL time.Duration
+	// LocalRepositoriesRoot is the directory file:// repositories must be under, they are rejected when it is empty
+	LocalRepositoriesRoot string
+}
+
+// GiteaInstance holds the API base url and token of a self-hosted Gitea or Forgejo instance
+type GiteaInstance struct {
+	ApiBaseURL string
+	Token      string
 }
 
 func LoadConfig(path string) (*Config, error) {
@@ -54,6 +97,52 @@ func LoadConfig(path string) (*Config, error) {
 		return nil, err
 	}
 
+	enrichmentInterval := helpers.Getenv("ENRICHMENT_INTERVAL", "1m")
+
+	enrichmentIntervalDuration, err := time.ParseDuration(enrichmentInterval)
+	if err != nil || enrichmentIntervalDuration <= 0 {
+		log.Error().Msgf("Invalid ENRICHMENT_INTERVAL :[%s] env format: %v", enrichmentInterval, err)
+		return nil, fmt.Errorf("invalid ENRICHMENT_INTERVAL [%s]", enrichmentInterval)
+	}
+
+	metadataRefreshInterval := helpers.Getenv("METADATA_REFRESH_INTERVAL", "6h")
+
+	metadataRefreshIntervalDuration, err := time.ParseDuration(metadataRefreshInterval)
+	if err != nil || metadataRefreshIntervalDuration <= 0 {
+		log.Error().Msgf("Invalid METADATA_REFRESH_INTERVAL :[%s] env format: %v", metadataRefreshInterval, err)
+		return nil, fmt.Errorf("invalid METADATA_REFRESH_INTERVAL [%s]", metadataRefreshInterval)
+	}
+
+	jobWorkers, err := positiveInt("JOB_WORKERS", "4")
+	if err != nil {
+		return nil, err
+	}
+
+	jobMaxAttempts, err := positiveInt("JOB_MAX_ATTEMPTS", "5")
+	if err != nil {
+		return nil, err
+	}
+
+	jobPollInterval, err := positiveDuration("JOB_POLL_INTERVAL", "5s")
+	if err != nil {
+		return nil, err
+	}
+
+	jobVisibilityTimeout, err := positiveDuration("JOB_VISIBILITY_TIMEOUT", "5m")
+	if err != nil {
+		return nil, err
+	}
+
+	leaderElectionInterval, err := positiveDuration("LEADER_ELECTION_INTERVAL", "10s")
+	if err != nil {
+		return nil, err
+	}
+
+	repoLeaseTTL, err := positiveDuration("REPO_LEASE_TTL", "2m")
+	if err != nil {
+		return nil, err
+	}
+
 	var sDate time.Time
 	var eDate time.Time
 
@@ -86,22 +175,63 @@ func LoadConfig(path string) (*Config, error) {
 		}
 	}
 
+	giteaInstances, err := parseGiteaInstances(os.Getenv("GITEA_INSTANCES"))
+	if err != nil {
+		log.Error().Msgf("Invalid GITEA_INSTANCES env format: %v", err)
+		return nil, err
+	}
+
+	githubAppID, githubAppPrivateKey, err := loadGitHubApp(os.Getenv("GITHUB_APP_ID"), os.Getenv("GITHUB_APP_PRIVATE_KEY_PATH"))
+	if err != nil {
+		log.Error().Msgf("Invalid GitHub App env: %v", err)
+		return nil, err
+	}
+
+	credentialsEncryptionKey, err := parseEncryptionKey(os.Getenv("CREDENTIALS_ENCRYPTION_KEY"))
+	if err != nil {
+		log.Error().Msgf("Invalid CREDENTIALS_ENCRYPTION_KEY env: %v", err)
+		return nil, err
+	}
+
 	configVar := Config{
-		AppEnv:                helpers.Getenv("APP_ENV", "local"),
-		GitHubToken:           os.Getenv("GIT_HUB_TOKEN"),
-		DatabaseHost:          os.Getenv("DATABASE_HOST"),
-		DatabasePort:          os.Getenv("DATABASE_PORT"),
-		DatabaseUser:          os.Getenv("DATABASE_USER"),
-		DatabaseName:          os.Getenv("DATABASE_NAME"),
-		DatabasePassword:      os.Getenv("DATABASE_PASSWORD"),
-		FetchInterval:         intervalDuration,
-		DefaultStartDate:      sDate,
-		DefaultEndDate:        eDate,
-		GitCommitFetchPerPage: commitPerPage,
-		GitHubApiBaseURL:      os.Getenv("GITHUB_API_BASE_URL"),
-		Address:               helpers.Getenv("ADDRESS", "0.0.0.0"),
-		Port:                  helpers.Getenv("PORT", "8080"),
-		DefaultRepository:     helpers.Getenv("DEFAULT_REPOSITORY", "chromium/chromium"),
+		AppEnv:                   helpers.Getenv("APP_ENV", "local"),
+		GitHubTokens:             parseList(os.Getenv("GIT_HUB_TOKEN")),
+		GitHubAppID:              githubAppID,
+		GitHubAppPrivateKey:      githubAppPrivateKey,
+		DatabaseHost:             os.Getenv("DATABASE_HOST"),
+		DatabasePort:             os.Getenv("DATABASE_PORT"),
+		DatabaseUser:             os.Getenv("DATABASE_USER"),
+		DatabaseName:             os.Getenv("DATABASE_NAME"),
+		DatabasePassword:         os.Getenv("DATABASE_PASSWORD"),
+		FetchInterval:            intervalDuration,
+		EnrichmentInterval:       enrichmentIntervalDuration,
+		MetadataRefreshInterval:  metadataRefreshIntervalDuration,
+		DefaultStartDate:         sDate,
+		DefaultEndDate:           eDate,
+		JobWorkers:               jobWorkers,
+		JobPollInterval:          jobPollInterval,
+		JobVisibilityTimeout:     jobVisibilityTimeout,
+		JobMaxAttempts:           jobMaxAttempts,
+		LeaderElectionInterval:   leaderElectionInterval,
+		RepoLeaseTTL:             repoLeaseTTL,
+		LocalRepositoriesRoot:    os.Getenv("LOCAL_REPOSITORIES_ROOT"),
+		GitCommitFetchPerPage:    commitPerPage,
+		GitHubApiBaseURL:         os.Getenv("GITHUB_API_BASE_URL"),
+		GitHubHost:               helpers.Getenv("GITHUB_HOST", "github.com"),
+		GitHubFetchMode:          helpers.Getenv("GITHUB_FETCH_MODE", "rest"),
+		GitHubGraphQLURL:         helpers.Getenv("GITHUB_GRAPHQL_URL", "https://api.github.com/graphql"),
+		GitHubWebhookSecret:      os.Getenv("GITHUB_WEBHOOK_SECRET"),
+		CredentialsEncryptionKey: credentialsEncryptionKey,
+		GitLabToken:              os.Getenv("GITLAB_TOKEN"),
+		GitLabApiBaseURL:         helpers.Getenv("GITLAB_API_BASE_URL", "https://gitlab.com/api/v4"),
+		BitbucketUsername:        os.Getenv("BITBUCKET_USERNAME"),
+		BitbucketAppPassword:     os.Getenv("BITBUCKET_APP_PASSWORD"),
+		BitbucketApiBaseURL:      helpers.Getenv("BITBUCKET_API_BASE_URL", "https://api.bitbucket.org/2.0"),
+		BitbucketHost:            helpers.Getenv("BITBUCKET_HOST", "bitbucket.org"),
+		GiteaInstances:           giteaInstances,
+		Address:                  helpers.Getenv("ADDRESS", "0.0.0.0"),
+		Port:                     helpers.Getenv("PORT", "8080"),
+		DefaultRepository:        helpers.Getenv("DEFAULT_REPOSITORY", "chromium/chromium"),
 	}
 
 	validate := validator.New()
@@ -113,3 +243,103 @@ func LoadConfig(path string) (*Config, error) {
 
 	return &configVar, nil
 }
+
+// loadGitHubApp parses the GitHub App id and reads its private key file, both are required to
+// authenticate as the app and none of them to authenticate with tokens
+func loadGitHubApp(appID, privateKeyPath string) (int64, []byte, error) {
+	if appID == "" && privateKeyPath == "" {
+		return 0, nil, nil
+	}
+
+	if appID == "" || privateKeyPath == "" {
+		return 0, nil, errors.New("GITHUB_APP_ID and GITHUB_APP_PRIVATE_KEY_PATH must be set together")
+	}
+
+	id, err := strconv.ParseInt(appID, 10, 64)
+	if err != nil || id <= 0 {
+		return 0, nil, fmt.Errorf("invalid GITHUB_APP_ID [%s]", appID)
+	}
+
+	privateKey, err := os.ReadFile(privateKeyPath)
+	if err != nil {
+		return 0, nil, err
+	}
+	return id, privateKey, nil
+}
+
+// positiveInt parses the env variable as an integer greater than zero, the fallback is used when it is not set
+func positiveInt(key, fallback string) (int, error) {
+	value := helpers.Getenv(key, fallback)
+
+	n, err := strconv.Atoi(value)
+	if err != nil || n <= 0 {
+		log.Error().Msgf("Invalid %s :[%s] env format: %v", key, value, err)
+		return 0, fmt.Errorf("invalid %s [%s]", key, value)
+	}
+	return n, nil
+}
+
+// positiveDuration parses the env variable as a duration greater than zero, the fallback is used when it is not set
+func positiveDuration(key, fallback string) (time.Duration, error) {
+	value := helpers.Getenv(key, fallback)
+
+	d, err := time.ParseDuration(value)
+	if err != nil || d <= 0 {
+		log.Error().Msgf("Invalid %s :[%s] env format: %v", key, value, err)
+		return 0, fmt.Errorf("invalid %s [%s]", key, value)
+	}
+	return d, nil
+}
+
+// parseEncryptionKey decodes a base64 encoded AES-256 key, eg generated with openssl rand -base64 32
+func parseEncryptionKey(value string) ([]byte, error) {
+	if value == "" {
+		return nil, nil
+	}
+
+	key, err := base64.StdEncoding.DecodeString(value)
+	if err != nil {
+		return nil, errors.New("CREDENTIALS_ENCRYPTION_KEY must be base64 encoded")
+	}
+	if len(key) != secrets.KeySize {
+		return nil, fmt.Errorf("CREDENTIALS_ENCRYPTION_KEY must be %d bytes long, got %d", secrets.KeySize, len(key))
+	}
+	return key, nil
+}
+
+// parseList parses a comma separated list, skipping blank entries
+func parseList(value string) []string {
+	var entries []string
+	for _, entry := range strings.Split(value, ",") {
+		if entry = strings.TrimSpace(entry); entry != "" {
+			entries = append(entries, entry)
+		}
+	}
+	return entries
+}
+
+// parseGiteaInstances parses a comma separated list of gitea instances, each given as
+// {apiBaseURL}={token} or just {apiBaseURL} for instances serving public repositories
+func parseGiteaInstances(value string) ([]GiteaInstance, error) {
+	var instances []GiteaInstance
+
+	for _, entry := range strings.Split(value, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		instance := GiteaInstance{ApiBaseURL: entry}
+		if i := strings.LastIndex(entry, "="); i >= 0 {
+			instance.ApiBaseURL, instance.Token = entry[:i], entry[i+1:]
+		}
+
+		u, err := url.Parse(instance.ApiBaseURL)
+		if err != nil || u.Scheme == "" || u.Host == "" {
+			return nil, fmt.Errorf("invalid gitea api base url [%s]", instance.ApiBaseURL)
+		}
+
+		instances = append(instances, instance)
+	}
+	return instances, nil
+}
@@ -2,6 +2,7 @@ package config_test
 
 import (
 	"os"
+	"path/filepath"
 	"testing"
 	"time"
 
@@ -25,7 +26,7 @@ func TestLoadConfigValid(t *testing.T) {
 	// Set up environment variables for a valid configuration
 	envs := map[string]string{
 		"APP_ENV":                   "test",
-		"GIT_HUB_TOKEN":             "test_token",
+		"GIT_HUB_TOKEN":             "test_token, second_token",
 		"DATABASE_HOST":             "localhost",
 		"DATABASE_PORT":             "5432",
 		"DATABASE_USER":             "test_user",
@@ -39,6 +40,7 @@ func TestLoadConfigValid(t *testing.T) {
 		"DEFAULT_REPOSITORY":        "example/repo",
 		"ADDRESS":                   "127.0.0.1",
 		"PORT":                      "8080",
+		"GITEA_INSTANCES":           "https://gitea.example.com/api/v1=gitea_token, https://codeberg.org/api/v1",
 	}
 
 	setupEnv(envs)
@@ -46,7 +48,7 @@ func TestLoadConfigValid(t *testing.T) {
 		"APP_ENV", "GIT_HUB_TOKEN", "DATABASE_HOST", "DATABASE_PORT", "DATABASE_USER",
 		"DATABASE_PASSWORD", "DATABASE_NAME", "FETCH_INTERVAL", "DEFAULT_START_DATE",
 		"DEFAULT_END_DATE", "GIT_COMMIT_FETCH_PER_PAGE", "GITHUB_API_BASE_URL",
-		"DEFAULT_REPOSITORY", "ADDRESS", "PORT",
+		"DEFAULT_REPOSITORY", "ADDRESS", "PORT", "GITEA_INSTANCES",
 	})
 
 	// Load the config
@@ -56,7 +58,7 @@ func TestLoadConfigValid(t *testing.T) {
 
 	// Check if all values are correctly loaded
 	assert.Equal(t, "test", cfg.AppEnv)
-	assert.Equal(t, "test_token", cfg.GitHubToken)
+	assert.Equal(t, []string{"test_token", "second_token"}, cfg.GitHubTokens)
 	assert.Equal(t, "localhost", cfg.DatabaseHost)
 	assert.Equal(t, "5432", cfg.DatabasePort)
 	assert.Equal(t, "test_user", cfg.DatabaseUser)
@@ -68,6 +70,10 @@ func TestLoadConfigValid(t *testing.T) {
 	assert.Equal(t, "127.0.0.1", cfg.Address)
 	assert.Equal(t, "8080", cfg.Port)
 	assert.Equal(t, "example/repo", cfg.DefaultRepository)
+	assert.Equal(t, []config.GiteaInstance{
+		{ApiBaseURL: "https://gitea.example.com/api/v1", Token: "gitea_token"},
+		{ApiBaseURL: "https://codeberg.org/api/v1"},
+	}, cfg.GiteaInstances)
 
 	// Check start and end dates
 	expectedStartDate, _ := time.Parse(time.RFC3339, "2023-01-01T00:00:00Z")
@@ -130,4 +136,104 @@ func TestLoadConfigDefaultValues(t *testing.T) {
 	// Check if default values are applied
 	assert.Equal(t, time.Hour, cfg.FetchInterval)
 	assert.Equal(t, "chromium/chromium", cfg.DefaultRepository)
+	assert.Equal(t, "https://gitlab.com/api/v4", cfg.GitLabApiBaseURL)
+	assert.Equal(t, "https://api.bitbucket.org/2.0", cfg.BitbucketApiBaseURL)
+	assert.Equal(t, "rest", cfg.GitHubFetchMode)
+	assert.Equal(t, 4, cfg.JobWorkers)
+	assert.Equal(t, 5, cfg.JobMaxAttempts)
+	assert.Equal(t, 5*time.Second, cfg.JobPollInterval)
+	assert.Equal(t, 5*time.Minute, cfg.JobVisibilityTimeout)
+	assert.Equal(t, 10*time.Second, cfg.LeaderElectionInterval)
+	assert.Equal(t, 2*time.Minute, cfg.RepoLeaseTTL)
+}
+
+func TestLoadConfigInvalidJobWorkers(t *testing.T) {
+	envs := map[string]string{
+		"APP_ENV":           "test",
+		"DATABASE_HOST":     "localhost",
+		"DATABASE_PORT":     "5432",
+		"DATABASE_USER":     "test_user",
+		"DATABASE_PASSWORD": "test_password",
+		"DATABASE_NAME":     "test_db",
+		"JOB_WORKERS":       "0",
+	}
+	setupEnv(envs)
+	defer clearEnv([]string{"APP_ENV", "DATABASE_HOST", "DATABASE_PORT", "DATABASE_USER", "DATABASE_PASSWORD", "DATABASE_NAME", "JOB_WORKERS"})
+
+	cfg, err := config.LoadConfig("")
+	assert.Error(t, err)
+	assert.Nil(t, cfg)
+}
+
+func TestLoadConfigInvalidGitHubFetchMode(t *testing.T) {
+	envs := map[string]string{
+		"APP_ENV":           "test",
+		"DATABASE_HOST":     "localhost",
+		"DATABASE_PORT":     "5432",
+		"DATABASE_USER":     "test_user",
+		"DATABASE_PASSWORD": "test_password",
+		"DATABASE_NAME":     "test_db",
+		"GITHUB_FETCH_MODE": "soap",
+	}
+	setupEnv(envs)
+	defer clearEnv([]string{"APP_ENV", "DATABASE_HOST", "DATABASE_PORT", "DATABASE_USER", "DATABASE_PASSWORD", "DATABASE_NAME", "GITHUB_FETCH_MODE"})
+
+	cfg, err := config.LoadConfig("")
+	assert.Error(t, err)
+	assert.Nil(t, cfg)
+}
+
+func TestLoadConfigGitHubApp(t *testing.T) {
+	keyPath := filepath.Join(t.TempDir(), "app.pem")
+	assert.NoError(t, os.WriteFile(keyPath, []byte("private key"), 0600))
+
+	envs := map[string]string{
+		"APP_ENV":                     "test",
+		"DATABASE_HOST":               "localhost",
+		"DATABASE_PORT":               "5432",
+		"DATABASE_USER":               "test_user",
+		"DATABASE_PASSWORD":           "test_password",
+		"DATABASE_NAME":               "test_db",
+		"GITHUB_APP_ID":               "1234",
+		"GITHUB_APP_PRIVATE_KEY_PATH": keyPath,
+	}
+	setupEnv(envs)
+	defer clearEnv([]string{"APP_ENV", "DATABASE_HOST", "DATABASE_PORT", "DATABASE_USER", "DATABASE_PASSWORD", "DATABASE_NAME", "GITHUB_APP_ID", "GITHUB_APP_PRIVATE_KEY_PATH"})
+
+	cfg, err := config.LoadConfig("")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1234), cfg.GitHubAppID)
+	assert.Equal(t, []byte("private key"), cfg.GitHubAppPrivateKey)
+
+	// the app id without its private key is rejected
+	os.Unsetenv("GITHUB_APP_PRIVATE_KEY_PATH")
+
+	cfg, err = config.LoadConfig("")
+	assert.Error(t, err)
+	assert.Nil(t, cfg)
+}
+
+func TestLoadConfigCredentialsEncryptionKey(t *testing.T) {
+	envs := map[string]string{
+		"APP_ENV":                    "test",
+		"DATABASE_HOST":              "localhost",
+		"DATABASE_PORT":              "5432",
+		"DATABASE_USER":              "test_user",
+		"DATABASE_PASSWORD":          "test_password",
+		"DATABASE_NAME":              "test_db",
+		"CREDENTIALS_ENCRYPTION_KEY": "AQEBAQEBAQEBAQEBAQEBAQEBAQEBAQEBAQEBAQEBAQE=",
+	}
+	setupEnv(envs)
+	defer clearEnv([]string{"APP_ENV", "DATABASE_HOST", "DATABASE_PORT", "DATABASE_USER", "DATABASE_PASSWORD", "DATABASE_NAME", "CREDENTIALS_ENCRYPTION_KEY"})
+
+	cfg, err := config.LoadConfig("")
+	assert.NoError(t, err)
+	assert.Len(t, cfg.CredentialsEncryptionKey, 32)
+
+	// keys of another size than AES-256 ones are rejected
+	os.Setenv("CREDENTIALS_ENCRYPTION_KEY", "c2hvcnQ=")
+
+	cfg, err = config.LoadConfig("")
+	assert.Error(t, err)
+	assert.Nil(t, cfg)
 }
@@ -0,0 +1,117 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"gorm.io/gorm"
+)
+
+const (
+	// SchedulerLockKey is the advisory lock held by the replica leading the scheduler
+	SchedulerLockKey int64 = 7_310_002
+	// migrationLockKey is the advisory lock serializing the migrations of replicas starting together
+	migrationLockKey int64 = 7_310_001
+)
+
+// LeaderElector elects a single leader among the replicas of the service with a Postgres session advisory lock.
+// The lock is held on a dedicated connection, leadership is lost along with the connection
+type LeaderElector struct {
+	db       *gorm.DB
+	lockKey  int64
+	interval time.Duration
+}
+
+func NewLeaderElector(db *gorm.DB, lockKey int64, interval time.Duration) *LeaderElector {
+	return &LeaderElector{
+		db:       db,
+		lockKey:  lockKey,
+		interval: interval,
+	}
+}
+
+// Run campaigns for the leadership every interval until the context is cancelled. While leading, lead is called
+// with a context cancelled once the leadership is lost, Run waits for it to return before campaigning again
+func (l *LeaderElector) Run(ctx context.Context, lead func(ctx context.Context)) {
+	ticker := time.NewTicker(l.interval)
+	defer ticker.Stop()
+
+	for {
+		conn, err := l.acquire(ctx)
+		if err != nil && ctx.Err() == nil {
+			log.Err(err).Msgf("error campaigning for leadership of lock %d", l.lockKey)
+		}
+
+		if conn != nil {
+			log.Info().Msgf("acquired leadership of lock %d", l.lockKey)
+			l.holdLeadership(ctx, conn, lead)
+			log.Warn().Msgf("lost leadership of lock %d", l.lockKey)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// acquire tries to take the lock on a new connection, which is returned while the lock is held on it
+func (l *LeaderElector) acquire(ctx context.Context) (*sql.Conn, error) {
+	sqlDB, err := l.db.DB()
+	if err != nil {
+		return nil, err
+	}
+
+	conn, err := sqlDB.Conn(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var acquired bool
+	if err := conn.QueryRowContext(ctx, "SELECT pg_try_advisory_lock($1)", l.lockKey).Scan(&acquired); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	if !acquired {
+		conn.Close()
+		return nil, nil
+	}
+	return conn, nil
+}
+
+// holdLeadership runs lead while the connection holding the lock answers, then releases the lock and the connection
+func (l *LeaderElector) holdLeadership(ctx context.Context, conn *sql.Conn, lead func(ctx context.Context)) {
+	leaderCtx, stopLeading := context.WithCancel(ctx)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		lead(leaderCtx)
+	}()
+
+	ticker := time.NewTicker(l.interval)
+	defer ticker.Stop()
+
+	for leaderCtx.Err() == nil {
+		select {
+		case <-leaderCtx.Done():
+		case <-done:
+			stopLeading()
+		case <-ticker.C:
+			if err := conn.PingContext(leaderCtx); err != nil && leaderCtx.Err() == nil {
+				log.Err(err).Msgf("connection holding lock %d was lost", l.lockKey)
+				stopLeading()
+			}
+		}
+	}
+	stopLeading()
+	<-done
+
+	// the lock is released with the session when the connection is already broken
+	if _, err := conn.ExecContext(context.Background(), "SELECT pg_advisory_unlock($1)", l.lockKey); err != nil {
+		log.Err(err).Msgf("error releasing lock %d", l.lockKey)
+	}
+	conn.Close()
+}
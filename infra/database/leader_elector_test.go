@@ -0,0 +1,178 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// lockServer is a database/sql driver answering the advisory lock queries of the leader elector
+type lockServer struct {
+	mu sync.Mutex
+	// heldElsewhere makes pg_try_advisory_lock fail as another replica holds the lock
+	heldElsewhere bool
+	// pingErr is returned by the pings of the connections
+	pingErr error
+	locks   int
+	unlocks int
+}
+
+func (s *lockServer) Connect(context.Context) (driver.Conn, error) { return &lockConn{server: s}, nil }
+func (s *lockServer) Driver() driver.Driver                        { return nil }
+
+func (s *lockServer) counts() (locks, unlocks int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.locks, s.unlocks
+}
+
+type lockConn struct {
+	server *lockServer
+}
+
+func (c *lockConn) Prepare(string) (driver.Stmt, error) { return nil, errors.New("not supported") }
+func (c *lockConn) Begin() (driver.Tx, error)           { return nil, errors.New("not supported") }
+
+func (c *lockConn) Close() error { return nil }
+
+func (c *lockConn) Ping(context.Context) error {
+	c.server.mu.Lock()
+	defer c.server.mu.Unlock()
+	return c.server.pingErr
+}
+
+func (c *lockConn) QueryContext(_ context.Context, query string, _ []driver.NamedValue) (driver.Rows, error) {
+	if !strings.Contains(query, "pg_try_advisory_lock") {
+		return nil, errors.New("unexpected query " + query)
+	}
+
+	c.server.mu.Lock()
+	defer c.server.mu.Unlock()
+	if !c.server.heldElsewhere {
+		c.server.locks++
+	}
+	return &boolRows{value: !c.server.heldElsewhere}, nil
+}
+
+func (c *lockConn) ExecContext(_ context.Context, query string, _ []driver.NamedValue) (driver.Result, error) {
+	if !strings.Contains(query, "pg_advisory_unlock") {
+		return nil, errors.New("unexpected query " + query)
+	}
+
+	c.server.mu.Lock()
+	defer c.server.mu.Unlock()
+	c.server.unlocks++
+	return driver.RowsAffected(1), nil
+}
+
+type boolRows struct {
+	value bool
+	read  bool
+}
+
+func (r *boolRows) Columns() []string { return []string{"acquired"} }
+func (r *boolRows) Close() error      { return nil }
+
+func (r *boolRows) Next(dest []driver.Value) error {
+	if r.read {
+		return io.EOF
+	}
+	r.read = true
+	dest[0] = r.value
+	return nil
+}
+
+func newTestElector(t *testing.T, server *lockServer) *LeaderElector {
+	sqlDB := sql.OpenDB(server)
+	t.Cleanup(func() { sqlDB.Close() })
+
+	db, err := gorm.Open(postgres.New(postgres.Config{Conn: sqlDB}), &gorm.Config{
+		DisableAutomaticPing: true,
+		Logger:               logger.Discard,
+	})
+	require.NoError(t, err)
+
+	return NewLeaderElector(db, SchedulerLockKey, 10*time.Millisecond)
+}
+
+func TestLeaderElectorLeadsUntilCancelled(t *testing.T) {
+	server := &lockServer{}
+	elector := newTestElector(t, server)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	leading := make(chan struct{})
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		elector.Run(ctx, func(leaderCtx context.Context) {
+			close(leading)
+			<-leaderCtx.Done()
+		})
+	}()
+
+	<-leading
+	cancel()
+	<-stopped
+
+	// the lock is released along with its connection once the leadership ends
+	locks, unlocks := server.counts()
+	require.Equal(t, 1, locks)
+	require.Equal(t, 1, unlocks)
+}
+
+func TestLeaderElectorDoesNotLeadWhileLockIsHeldElsewhere(t *testing.T) {
+	server := &lockServer{heldElsewhere: true}
+	elector := newTestElector(t, server)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	elector.Run(ctx, func(context.Context) {
+		t.Error("lead was called while the lock is held by another replica")
+	})
+
+	locks, unlocks := server.counts()
+	require.Zero(t, locks)
+	require.Zero(t, unlocks)
+}
+
+func TestLeaderElectorStopsLeadingOnLostConnection(t *testing.T) {
+	server := &lockServer{pingErr: errors.New("connection reset")}
+	elector := newTestElector(t, server)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	lost := make(chan struct{}, 1)
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		elector.Run(ctx, func(leaderCtx context.Context) {
+			<-leaderCtx.Done()
+			select {
+			case lost <- struct{}{}:
+			default:
+			}
+		})
+	}()
+
+	// the leadership ends while the elector still runs, as the connection holding the lock stopped answering
+	<-lost
+	require.NoError(t, ctx.Err())
+
+	cancel()
+	<-stopped
+
+	locks, unlocks := server.counts()
+	require.GreaterOrEqual(t, locks, 1)
+	require.Equal(t, locks, unlocks)
+}
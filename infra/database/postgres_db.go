@@ -47,8 +47,99 @@ func (p *PostgresDatabase) ConnectDb() (*gorm.DB, error) {
 	return p.db, nil
 }
 
-// Migrate does db schema migration for PostgreSQL
+// Migrate does db schema migration for PostgreSQL, replicas starting together migrate one after the other
 func (p *PostgresDatabase) Migrate() error {
+	return p.db.Connection(func(conn *gorm.DB) error {
+		if err := conn.Exec("SELECT pg_advisory_lock(?)", migrationLockKey).Error; err != nil {
+			return err
+		}
+		defer conn.Exec("SELECT pg_advisory_unlock(?)", migrationLockKey)
+
+		return migrate(conn)
+	})
+}
+
+func migrate(db *gorm.DB) error {
+	if err := dropGlobalUniqueness(db); err != nil {
+		return err
+	}
+
 	// Migrate the schema for PostgreSQL
-	return p.db.AutoMigrate(&postgreSQL.Repository{}, &postgreSQL.Commit{})
+	if err := db.AutoMigrate(&postgreSQL.Repository{}, &postgreSQL.Commit{}, &postgreSQL.CommitFile{}, &postgreSQL.HTTPValidator{},
+		&postgreSQL.Branch{}, &postgreSQL.CommitBranch{}, &postgreSQL.Tag{}, &postgreSQL.Release{},
+		&postgreSQL.PullRequest{}, &postgreSQL.PullRequestCommit{}, &postgreSQL.WebhookDelivery{},
+		&postgreSQL.RepoSnapshot{}, &postgreSQL.Credential{}, &postgreSQL.HistoryRewrite{}, &postgreSQL.Job{},
+		&postgreSQL.RepoLease{}); err != nil {
+		return err
+	}
+
+	if err := backfillRepositoryHosts(db); err != nil {
+		return err
+	}
+	if err := backfillCommitFileRepositories(db); err != nil {
+		return err
+	}
+	return dropStaleValidators(db)
+}
+
+// dropStaleValidators deletes the cache validators stored under their full url, whose until parameter moved on every
+// request so that they were never matched again
+func dropStaleValidators(db *gorm.DB) error {
+	return db.Exec(`DELETE FROM http_validators WHERE endpoint LIKE '%until=%'`).Error
+}
+
+// dropGlobalUniqueness drops the constraints making repository names and commit ids unique across all the hosts,
+// which AutoMigrate keeps although they are now unique per host and per repository. The unique index on commit ids
+// backs the foreign key of the commit files, which is recreated by AutoMigrate on the repository and the commit id
+func dropGlobalUniqueness(db *gorm.DB) error {
+	var uniqueCommitIDs bool
+	err := db.Raw(`SELECT EXISTS (SELECT 1 FROM pg_indexes WHERE indexname = 'idx_commits_commit_id' AND indexdef LIKE 'CREATE UNIQUE INDEX%')`).
+		Scan(&uniqueCommitIDs).Error
+	if err != nil {
+		return err
+	}
+
+	statements := []string{
+		`ALTER TABLE IF EXISTS repositories DROP CONSTRAINT IF EXISTS uni_repositories_name`,
+		`ALTER TABLE IF EXISTS repositories DROP CONSTRAINT IF EXISTS repositories_name_key`,
+	}
+	if uniqueCommitIDs {
+		statements = append(statements,
+			`ALTER TABLE IF EXISTS commit_files DROP CONSTRAINT IF EXISTS fk_commits_files`,
+			`DROP INDEX IF EXISTS idx_commits_commit_id`,
+		)
+	}
+
+	for _, statement := range statements {
+		if err := db.Exec(statement).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// backfillCommitFileRepositories sets the repository of the commit files stored before they were recorded, commit
+// ids were unique across the repositories then so each file has a single commit
+func backfillCommitFileRepositories(db *gorm.DB) error {
+	return db.Exec(`UPDATE commit_files SET repository_host = commits.repository_host, repository_name = commits.repository_name
+		FROM commits WHERE commits.commit_id = commit_files.commit_sha AND commit_files.repository_name IS NULL`).Error
+}
+
+// backfillRepositoryHosts sets the provider and host of repositories and commits stored before
+// they were recorded, those were all indexed from GitHub or, for file:// names, from disk. The columns
+// were added without a default so the rows stored before hold NULL
+func backfillRepositoryHosts(db *gorm.DB) error {
+	statements := []string{
+		`UPDATE repositories SET provider = 'local', host = 'localhost' WHERE (provider IS NULL OR provider = '') AND name LIKE 'file://%'`,
+		`UPDATE repositories SET provider = 'github', host = 'github.com' WHERE provider IS NULL OR provider = ''`,
+		`UPDATE commits SET repository_host = CASE WHEN repository_name LIKE 'file://%' THEN 'localhost' ELSE 'github.com' END
+			WHERE repository_host IS NULL OR repository_host = ''`,
+	}
+
+	for _, statement := range statements {
+		if err := db.Exec(statement).Error; err != nil {
+			return err
+		}
+	}
+	return nil
 }
@@ -0,0 +1,303 @@
+package git
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/kenmobility/git-api-service/internal/domain"
+	"github.com/kenmobility/git-api-service/pkg/client"
+	"github.com/kenmobility/git-api-service/pkg/message"
+	"github.com/rs/zerolog/log"
+)
+
+type BitbucketClient struct {
+	baseURL     string
+	username    string
+	appPassword string
+	client      *client.RestClient
+
+	// Bitbucket paginates with opaque 'next' urls rather than page numbers
+	nextPages *pageCursors
+}
+
+func (b *BitbucketClient) getHeaders() map[string]string {
+	if len(b.appPassword) == 0 {
+		return map[string]string{}
+	}
+
+	// app passwords authenticate with the account username, access tokens are sent as bearer tokens
+	if len(b.username) == 0 {
+		return map[string]string{
+			"Authorization": fmt.Sprintf("Bearer %s", b.appPassword),
+		}
+	}
+	credentials := base64.StdEncoding.EncodeToString([]byte(b.username + ":" + b.appPassword))
+	return map[string]string{
+		"Authorization": fmt.Sprintf("Basic %s", credentials),
+	}
+}
+
+func NewBitbucketClient(baseUrl string, username string, appPassword string) GitManagerClient {
+	client := client.NewRestClient()
+
+	bc := BitbucketClient{
+		baseURL:     baseUrl,
+		username:    username,
+		appPassword: appPassword,
+		client:      client,
+		nextPages:   newPageCursors(),
+	}
+	return &bc
+}
+
+// WithToken returns a client authenticating with the token, a repository, project or workspace access token
+func (b *BitbucketClient) WithToken(token string) GitManagerClient {
+	return NewBitbucketClient(b.baseURL, "", token)
+}
+
+func (b *BitbucketClient) FetchRepoMetadata(ctx context.Context, repositoryName string) (*domain.RepoMetadata, error) {
+	endpoint := fmt.Sprintf("%s/repositories/%s", b.baseURL, repositoryName)
+
+	resp, err := b.client.Get(ctx, endpoint, client.WithHeaders(b.getHeaders()))
+	if err != nil {
+		return nil, requestError(err)
+	}
+
+	if resp.StatusCode == http.StatusTooManyRequests {
+		log.Error().Msgf("failed to fetch bitbucket repository; status code: %v, body: %v", resp.StatusCode, resp.Body)
+		return nil, message.ErrRateLimitExceeded
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		log.Error().Msgf("failed to fetch bitbucket repository; status code: %v, body: %v", resp.StatusCode, resp.Body)
+		return nil, repoMetadataError(resp.StatusCode)
+	}
+
+	var repository BitbucketRepositoryResponse
+
+	if err := json.Unmarshal([]byte(resp.Body), &repository); err != nil {
+		log.Error().Msgf("marshal error, [%v]", err)
+		return nil, errors.New("could not unmarshal bitbucket repository response")
+	}
+
+	repoMetadata := &domain.RepoMetadata{
+		Name:          repository.FullName,
+		Description:   repository.Description,
+		URL:           repository.Links.HTML.Href,
+		Language:      repository.Language,
+		ForksCount:    b.fetchCollectionSize(ctx, endpoint+"/forks"),
+		WatchersCount: b.fetchCollectionSize(ctx, endpoint+"/watchers"),
+		DefaultBranch: repository.MainBranch.Name,
+		Status:        domain.RepoStatusActive,
+	}
+
+	return repoMetadata, nil
+}
+
+// fetchCollectionSize returns the total size of a paginated collection, Bitbucket does not
+// include forks or watchers counts on the repository resource itself
+func (b *BitbucketClient) fetchCollectionSize(ctx context.Context, endpoint string) int {
+	resp, err := b.client.Get(ctx, endpoint, client.WithQuery(map[string]string{"pagelen": "1"}), client.WithHeaders(b.getHeaders()))
+	if err != nil || resp.StatusCode != http.StatusOK {
+		return 0
+	}
+
+	var collection BitbucketCollectionResponse
+	if err := json.Unmarshal([]byte(resp.Body), &collection); err != nil {
+		return 0
+	}
+	return collection.Size
+}
+
+func (b *BitbucketClient) FetchCommits(ctx context.Context, repo domain.RepoMetadata, since time.Time, until time.Time, lastFetchedCommit string, page, perPage int) ([]domain.Commit, bool, error) {
+	firstPage := fmt.Sprintf("%s/repositories/%s/commits", b.baseURL, repo.Name)
+	if lastFetchedCommit != "" {
+		firstPage = fmt.Sprintf("%s/%s", firstPage, lastFetchedCommit)
+	}
+	firstPage = client.AddQueryParameters(firstPage, map[string]string{"pagelen": fmt.Sprint(perPage)})
+
+	endpoint, err := b.pageURL(ctx, firstPage, page)
+	if err != nil {
+		return nil, false, err
+	}
+
+	if endpoint == "" {
+		// the requested page is beyond the last page
+		return nil, false, nil
+	}
+
+	commitsRes, err := b.fetchCommitsPage(ctx, endpoint)
+	if err != nil {
+		return nil, false, err
+	}
+	b.nextPages.storeNext(firstPage, page, commitsRes.Next)
+
+	var cc []domain.Commit
+	reachedSince := false
+	for _, cr := range commitsRes.Values {
+		// the commits endpoint has no date filter, so the window is applied here; history is
+		// listed newest first, hence the first commit older than since ends the window
+		if lastFetchedCommit == "" {
+			if cr.Date.Before(since) {
+				reachedSince = true
+				continue
+			}
+			if cr.Date.After(until) {
+				continue
+			}
+		}
+
+		name, email := parseBitbucketAuthor(cr.Author)
+
+		// bitbucket only serves the author of a commit
+		commit := domain.Commit{
+			CommitID:       cr.Hash,
+			Message:        cr.Message,
+			Author:         name,
+			AuthorEmail:    email,
+			AuthorLogin:    cr.Author.User.Nickname,
+			Date:           cr.Date,
+			URL:            cr.Links.HTML.Href,
+			RepositoryName: repo.Name,
+			RepositoryHost: repo.Host,
+		}
+		for _, parent := range cr.Parents {
+			commit.ParentSHAs = append(commit.ParentSHAs, parent.Hash)
+		}
+		commit.IsMerge = len(commit.ParentSHAs) > 1
+
+		cc = append(cc, commit)
+	}
+
+	morePages := commitsRes.Next != "" && !reachedSince
+
+	return cc, morePages, nil
+}
+
+func (b *BitbucketClient) fetchCommitsPage(ctx context.Context, endpoint string) (*BitbucketCommitsResponse, error) {
+	response, err := b.client.Get(ctx, endpoint, client.WithHeaders(b.getHeaders()))
+	if err != nil {
+		log.Error().Msgf("error fetching bitbucket commits: %v", err)
+		return nil, requestError(err)
+	}
+
+	if response.StatusCode == http.StatusTooManyRequests {
+		log.Error().Msgf("failed to fetch bitbucket commits; status code: %v, body: %v", response.StatusCode, response.Body)
+		return nil, message.ErrRateLimitExceeded
+	}
+
+	if response.StatusCode != http.StatusOK {
+		log.Error().Msgf("failed to fetch bitbucket commits; status code: %v, body: %v", response.StatusCode, response.Body)
+		return nil, fmt.Errorf("failed to fetch commits; status code: %v, body: %v", response.StatusCode, response.Body)
+	}
+
+	var commitsRes BitbucketCommitsResponse
+
+	if err := json.Unmarshal([]byte(response.Body), &commitsRes); err != nil {
+		log.Err(err).Msgf("marshal error, [%v]", err)
+		return nil, errors.New("could not unmarshal bitbucket commits response")
+	}
+
+	return &commitsRes, nil
+}
+
+// pageURL resolves the url of the requested page, walking the 'next' links from the first page
+// when the page was not reached before, eg after a restart
+func (b *BitbucketClient) pageURL(ctx context.Context, firstPage string, page int) (string, error) {
+	if page <= 1 {
+		return firstPage, nil
+	}
+
+	endpoint, ok := b.nextPages.get(firstPage, page)
+	if ok {
+		return endpoint, nil
+	}
+
+	endpoint = firstPage
+	for p := 1; p < page; p++ {
+		if ctx.Err() != nil {
+			return "", message.ErrContextCancelled
+		}
+
+		commitsRes, err := b.fetchCommitsPage(ctx, endpoint)
+		if err != nil {
+			return "", err
+		}
+		b.nextPages.storeNext(firstPage, p, commitsRes.Next)
+
+		if commitsRes.Next == "" {
+			return "", nil
+		}
+		endpoint = commitsRes.Next
+	}
+	return endpoint, nil
+}
+
+// FetchTags lists the tags of the repository with the commit they point to, following the 'next' links
+func (b *BitbucketClient) FetchTags(ctx context.Context, repo domain.RepoMetadata) ([]domain.Tag, error) {
+	var tags []domain.Tag
+
+	endpoint := fmt.Sprintf("%s/repositories/%s/refs/tags?pagelen=100", b.baseURL, repo.Name)
+	for endpoint != "" {
+		response, err := b.client.Get(ctx, endpoint, client.WithHeaders(b.getHeaders()))
+		if err != nil {
+			log.Error().Msgf("error fetching bitbucket tags: %v", err)
+			return nil, requestError(err)
+		}
+
+		if response.StatusCode == http.StatusTooManyRequests {
+			log.Error().Msgf("failed to fetch bitbucket tags; status code: %v, body: %v", response.StatusCode, response.Body)
+			return nil, message.ErrRateLimitExceeded
+		}
+
+		if response.StatusCode != http.StatusOK {
+			log.Error().Msgf("failed to fetch bitbucket tags; status code: %v, body: %v", response.StatusCode, response.Body)
+			return nil, fmt.Errorf("failed to fetch tags; status code: %v, body: %v", response.StatusCode, response.Body)
+		}
+
+		var tagsRes BitbucketTagsResponse
+
+		if err := json.Unmarshal([]byte(response.Body), &tagsRes); err != nil {
+			log.Err(err).Msgf("marshal error, [%v]", err)
+			return nil, errors.New("could not unmarshal bitbucket tags response")
+		}
+
+		for _, tr := range tagsRes.Values {
+			tags = append(tags, domain.Tag{Name: tr.Name, TargetSHA: tr.Target.Hash})
+		}
+		endpoint = tagsRes.Next
+	}
+
+	return tags, nil
+}
+
+// FetchReleases returns no releases, Bitbucket has none
+func (b *BitbucketClient) FetchReleases(ctx context.Context, repo domain.RepoMetadata) ([]domain.Release, error) {
+	return nil, nil
+}
+
+// parseBitbucketAuthor splits the raw author string, eg "Jane Doe <jane@example.com>", into
+// the author name and email. The linked Bitbucket user's display name is used when raw has no name
+func parseBitbucketAuthor(author BitbucketAuthor) (string, string) {
+	raw := strings.TrimSpace(author.Raw)
+
+	name, email := raw, ""
+	if start := strings.LastIndex(raw, "<"); start >= 0 && strings.HasSuffix(raw, ">") {
+		name = strings.TrimSpace(raw[:start])
+		email = raw[start+1 : len(raw)-1]
+	}
+
+	if name == "" {
+		name = author.User.DisplayName
+	}
+	if name == "" {
+		name = email
+	}
+	return name, email
+}
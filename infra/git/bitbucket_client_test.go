@@ -0,0 +1,111 @@
+package git_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/kenmobility/git-api-service/infra/git"
+	"github.com/kenmobility/git-api-service/internal/domain"
+	"github.com/stretchr/testify/require"
+)
+
+func newBitbucketTestServer(t *testing.T) *httptest.Server {
+	var server *httptest.Server
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("/repositories/sample/repo", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"full_name": "sample/repo", "description": "A sample repository", "language": "go",
+			"links": {"html": {"href": "https://bitbucket.org/sample/repo"}}}`))
+	})
+	mux.HandleFunc("/repositories/sample/repo/forks", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"size": 4, "values": []}`))
+	})
+	mux.HandleFunc("/repositories/sample/repo/watchers", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"size": 7, "values": []}`))
+	})
+	mux.HandleFunc("/repositories/sample/repo/commits", func(w http.ResponseWriter, r *http.Request) {
+		// the page token is opaque, clients must follow the next url rather than build it
+		switch r.URL.Query().Get("page") {
+		case "":
+			fmt.Fprintf(w, `{"values": [{"hash": "abc123", "message": "Second commit", "date": "2024-01-03T10:00:00Z",
+				"author": {"raw": "John Doe <john@example.com>"}}], "next": "%s/repositories/sample/repo/commits?pagelen=1&page=x9"}`, server.URL)
+		case "x9":
+			w.Write([]byte(`{"values": [{"hash": "def456", "message": "Initial commit", "date": "2024-01-02T10:00:00Z",
+				"author": {"raw": "<jane@example.com>", "user": {"display_name": "Jane Doe"}}}]}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+
+	server = httptest.NewServer(mux)
+	return server
+}
+
+func TestBitbucketFetchRepoMetadata(t *testing.T) {
+	server := newBitbucketTestServer(t)
+	defer server.Close()
+
+	gitClient := git.NewBitbucketClient(server.URL, "", "")
+
+	metadata, err := gitClient.FetchRepoMetadata(context.Background(), "sample/repo")
+	require.NoError(t, err)
+	require.Equal(t, "sample/repo", metadata.Name)
+	require.Equal(t, "https://bitbucket.org/sample/repo", metadata.URL)
+	require.Equal(t, 4, metadata.ForksCount)
+	require.Equal(t, 7, metadata.WatchersCount)
+}
+
+func TestBitbucketFetchCommitsFollowsNextURL(t *testing.T) {
+	server := newBitbucketTestServer(t)
+	defer server.Close()
+
+	repo := domain.RepoMetadata{Name: "sample/repo"}
+	since := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	until := time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)
+
+	gitClient := git.NewBitbucketClient(server.URL, "", "")
+
+	commits, morePages, err := gitClient.FetchCommits(context.Background(), repo, since, until, "", 1, 1)
+	require.NoError(t, err)
+	require.True(t, morePages)
+	require.Len(t, commits, 1)
+	require.Equal(t, "abc123", commits[0].CommitID)
+	require.Equal(t, "John Doe", commits[0].Author)
+
+	commits, morePages, err = gitClient.FetchCommits(context.Background(), repo, since, until, "", 2, 1)
+	require.NoError(t, err)
+	require.False(t, morePages)
+	require.Len(t, commits, 1)
+	require.Equal(t, "def456", commits[0].CommitID)
+	require.Equal(t, "Jane Doe", commits[0].Author)
+
+	// a fresh client has not seen page 1, so it walks the next urls to reach page 2
+	commits, _, err = git.NewBitbucketClient(server.URL, "", "").FetchCommits(context.Background(), repo, since, until, "", 2, 1)
+	require.NoError(t, err)
+	require.Equal(t, "def456", commits[0].CommitID)
+}
+
+func TestBitbucketFetchCommitsStopsAtSince(t *testing.T) {
+	server := newBitbucketTestServer(t)
+	defer server.Close()
+
+	repo := domain.RepoMetadata{Name: "sample/repo"}
+	since := time.Date(2024, 1, 3, 0, 0, 0, 0, time.UTC)
+	until := time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)
+
+	gitClient := git.NewBitbucketClient(server.URL, "", "")
+
+	commits, morePages, err := gitClient.FetchCommits(context.Background(), repo, since, until, "", 1, 1)
+	require.NoError(t, err)
+	require.True(t, morePages)
+	require.Len(t, commits, 1)
+
+	commits, morePages, err = gitClient.FetchCommits(context.Background(), repo, since, until, "", 2, 1)
+	require.NoError(t, err)
+	require.False(t, morePages)
+	require.Empty(t, commits)
+}
@@ -0,0 +1,67 @@
+package git
+
+import "time"
+
+type (
+	BitbucketCommitsResponse struct {
+		Values  []BitbucketCommitResponse `json:"values"`
+		PageLen int                       `json:"pagelen"`
+		Next    string                    `json:"next"`
+	}
+
+	BitbucketCommitResponse struct {
+		Hash    string          `json:"hash"`
+		Message string          `json:"message"`
+		Date    time.Time       `json:"date"`
+		Author  BitbucketAuthor `json:"author"`
+		Links   BitbucketLinks  `json:"links"`
+		Parents []struct {
+			Hash string `json:"hash"`
+		} `json:"parents"`
+	}
+
+	BitbucketAuthor struct {
+		Raw  string `json:"raw"`
+		User struct {
+			DisplayName string `json:"display_name"`
+			Nickname    string `json:"nickname"`
+		} `json:"user"`
+	}
+
+	BitbucketLinks struct {
+		HTML struct {
+			Href string `json:"href"`
+		} `json:"html"`
+	}
+)
+
+type (
+	BitbucketRepositoryResponse struct {
+		UUID        string         `json:"uuid"`
+		FullName    string         `json:"full_name"`
+		Description string         `json:"description"`
+		Language    string         `json:"language"`
+		IsPrivate   bool           `json:"is_private"`
+		Links       BitbucketLinks `json:"links"`
+		MainBranch  struct {
+			Name string `json:"name"`
+		} `json:"mainbranch"`
+	}
+
+	// BitbucketCollectionResponse holds the size of a paginated collection, eg forks or watchers
+	BitbucketCollectionResponse struct {
+		Size int `json:"size"`
+	}
+)
+
+type (
+	BitbucketTagsResponse struct {
+		Values []struct {
+			Name   string `json:"name"`
+			Target struct {
+				Hash string `json:"hash"`
+			} `json:"target"`
+		} `json:"values"`
+		Next string `json:"next"`
+	}
+)
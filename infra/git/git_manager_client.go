@@ -2,12 +2,91 @@ package git
 
 import (
 	"context"
+	"net/http"
 	"time"
 
 	"github.com/kenmobility/git-api-service/internal/domain"
+	"github.com/kenmobility/git-api-service/pkg/message"
 )
 
 type GitManagerClient interface {
+	// FetchRepoMetadata follows the redirects of renamed or transferred repositories, returning their current name,
+	// and fails with message.ErrRepositoryNotFound or message.ErrRepositoryInaccessible for repositories gone from the host
 	FetchRepoMetadata(ctx context.Context, repositoryName string) (*domain.RepoMetadata, error)
 	FetchCommits(ctx context.Context, repo domain.RepoMetadata, since time.Time, until time.Time, lastFetchedCommit string, page, perPage int) ([]domain.Commit, bool, error)
+	// FetchTags lists all the tags of the repository
+	FetchTags(ctx context.Context, repo domain.RepoMetadata) ([]domain.Tag, error)
+	// FetchReleases lists all the releases of the repository, hosts without releases return none
+	FetchReleases(ctx context.Context, repo domain.RepoMetadata) ([]domain.Release, error)
+}
+
+// CommitPage is a page of a commit listing
+type CommitPage struct {
+	Commits []domain.Commit
+	// Cursor resumes the listing after the last commit of the page
+	Cursor    string
+	MorePages bool
+	// TotalPages is the number of pages of the listing, 0 when the provider does not tell
+	TotalPages int
+	// Validator holds the cache validators of a first page fetched conditionally, they are saved through a
+	// ValidatorSaver once the walk of the listing succeeded
+	Validator *domain.HTTPValidator
+}
+
+// ValidatorSaver is implemented by clients sending conditional requests. A 304 tells the first page did not change
+// since its validators were saved, so they are only saved once the walk of the listing succeeded: a listing whose walk
+// failed partway is fetched in full again
+type ValidatorSaver interface {
+	SaveValidator(ctx context.Context, validator domain.HTTPValidator) error
+}
+
+// CursorCommitFetcher is implemented by clients that page through history with cursors, such as GraphQL end cursors
+// or the next links of a REST listing, rather than rebuilding the url of a page from its number
+type CursorCommitFetcher interface {
+	FetchCommitsAfter(ctx context.Context, repo domain.RepoMetadata, since time.Time, until time.Time, lastFetchedCommit string, cursor string, perPage int) (*CommitPage, error)
+}
+
+// CommitDetailFetcher is implemented by clients able to fetch the change statistics and the files changed by a commit
+type CommitDetailFetcher interface {
+	FetchCommit(ctx context.Context, repo domain.RepoMetadata, commitID string) (*domain.Commit, error)
+}
+
+// BranchCommitFetcher is implemented by clients able to list the branches of a repository and to page through
+// the history of one of them, like FetchCommitsAfter does for the default branch
+type BranchCommitFetcher interface {
+	FetchBranches(ctx context.Context, repo domain.RepoMetadata) ([]domain.Branch, error)
+	FetchBranchCommits(ctx context.Context, repo domain.RepoMetadata, branch string, since time.Time, until time.Time, cursor string, perPage int) (*CommitPage, error)
+}
+
+// PullRequestFetcher is implemented by clients able to list the pull requests of a repository and the commits
+// each of them brought in
+type PullRequestFetcher interface {
+	// FetchPullRequests lists the pull requests updated since the time, all of them when it is zero
+	FetchPullRequests(ctx context.Context, repo domain.RepoMetadata, since time.Time) ([]domain.PullRequest, error)
+	FetchPullRequestCommits(ctx context.Context, repo domain.RepoMetadata, number int) ([]string, error)
+}
+
+// TokenScopedClient is implemented by clients able to authenticate the requests of a repository with a token of its own
+type TokenScopedClient interface {
+	// WithToken returns a client of the same host authenticating with the token
+	WithToken(token string) GitManagerClient
+}
+
+// repoMetadataError maps the status of a failed repository request, telling repositories gone from the host apart
+func repoMetadataError(statusCode int) error {
+	switch statusCode {
+	case http.StatusNotFound, http.StatusGone:
+		return message.ErrRepositoryNotFound
+	case http.StatusForbidden, http.StatusUnavailableForLegalReasons:
+		return message.ErrRepositoryInaccessible
+	}
+	return message.ErrRepoMetaDataNotFetched
+}
+
+// repoStatus returns the status of a repository served by its host
+func repoStatus(archived bool) string {
+	if archived {
+		return domain.RepoStatusArchived
+	}
+	return domain.RepoStatusActive
 }
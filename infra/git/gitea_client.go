@@ -0,0 +1,238 @@
+package git
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/kenmobility/git-api-service/internal/domain"
+	"github.com/kenmobility/git-api-service/pkg/client"
+	"github.com/kenmobility/git-api-service/pkg/message"
+	"github.com/rs/zerolog/log"
+)
+
+// GiteaClient fetches repositories from a Gitea or Forgejo instance, both serve the same v1 API
+type GiteaClient struct {
+	baseURL string
+	token   string
+	client  *client.RestClient
+}
+
+func (g *GiteaClient) getHeaders() map[string]string {
+	if len(g.token) == 0 {
+		return map[string]string{}
+	}
+	return map[string]string{
+		"Content-Type":  "application/json",
+		"Authorization": fmt.Sprintf("token %s", g.token),
+	}
+}
+
+// NewGiteaClient returns a client for the instance served at baseUrl, eg https://gitea.example.com/api/v1
+func NewGiteaClient(baseUrl string, token string) GitManagerClient {
+	client := client.NewRestClient()
+
+	gc := GiteaClient{
+		baseURL: strings.TrimSuffix(baseUrl, "/"),
+		token:   token,
+		client:  client,
+	}
+	return &gc
+}
+
+// WithToken returns a client authenticating with the token
+func (g *GiteaClient) WithToken(token string) GitManagerClient {
+	return NewGiteaClient(g.baseURL, token)
+}
+
+func (g *GiteaClient) FetchRepoMetadata(ctx context.Context, repositoryName string) (*domain.RepoMetadata, error) {
+	endpoint := fmt.Sprintf("%s/repos/%s", g.baseURL, repositoryName)
+
+	resp, err := g.client.Get(ctx, endpoint, client.WithHeaders(g.getHeaders()))
+	if err != nil {
+		return nil, requestError(err)
+	}
+
+	if resp.StatusCode == http.StatusTooManyRequests {
+		log.Error().Msgf("failed to fetch gitea repository; status code: %v, body: %v", resp.StatusCode, resp.Body)
+		return nil, message.ErrRateLimitExceeded
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		log.Error().Msgf("failed to fetch gitea repository; status code: %v, body: %v", resp.StatusCode, resp.Body)
+		return nil, repoMetadataError(resp.StatusCode)
+	}
+
+	var giteaRepoResponse GiteaRepoMetadataResponse
+
+	if err := json.Unmarshal([]byte(resp.Body), &giteaRepoResponse); err != nil {
+		log.Error().Msgf("marshal error, [%v]", err)
+		return nil, errors.New("could not unmarshal gitea repository response")
+	}
+
+	repoMetadata := &domain.RepoMetadata{
+		Name:            giteaRepoResponse.FullName,
+		Description:     giteaRepoResponse.Description,
+		URL:             giteaRepoResponse.HtmlUrl,
+		Language:        giteaRepoResponse.Language,
+		ForksCount:      giteaRepoResponse.ForksCount,
+		StarsCount:      giteaRepoResponse.StarsCount,
+		OpenIssuesCount: giteaRepoResponse.OpenIssuesCount,
+		WatchersCount:   giteaRepoResponse.WatchersCount,
+		DefaultBranch:   giteaRepoResponse.DefaultBranch,
+		Status:          repoStatus(giteaRepoResponse.Archived),
+	}
+
+	return repoMetadata, nil
+}
+
+func (g *GiteaClient) FetchCommits(ctx context.Context, repo domain.RepoMetadata, since time.Time, until time.Time, lastFetchedCommit string, page, perPage int) ([]domain.Commit, bool, error) {
+	endpoint := fmt.Sprintf("%s/repos/%s/commits", g.baseURL, repo.Name)
+
+	// stat, verification and files are computed per commit by the forge, none of them is stored
+	queryParams := map[string]string{
+		"limit":        strconv.Itoa(perPage),
+		"page":         strconv.Itoa(page),
+		"stat":         "false",
+		"verification": "false",
+		"files":        "false",
+	}
+
+	if lastFetchedCommit != "" {
+		queryParams["sha"] = lastFetchedCommit
+	} else {
+		queryParams["since"] = since.Format(time.RFC3339)
+		queryParams["until"] = until.Format(time.RFC3339)
+	}
+
+	response, err := g.client.Get(ctx, endpoint, client.WithQuery(queryParams), client.WithHeaders(g.getHeaders()))
+	if err != nil {
+		log.Error().Msgf("error fetching gitea commits: %v", err)
+		return nil, false, requestError(err)
+	}
+
+	if response.StatusCode == http.StatusTooManyRequests {
+		log.Error().Msgf("failed to fetch gitea commits; status code: %v, body: %v", response.StatusCode, response.Body)
+		return nil, false, message.ErrRateLimitExceeded
+	}
+
+	if response.StatusCode != http.StatusOK {
+		log.Error().Msgf("failed to fetch gitea commits; status code: %v, body: %v", response.StatusCode, response.Body)
+		return nil, false, fmt.Errorf("failed to fetch commits; status code: %v, body: %v", response.StatusCode, response.Body)
+	}
+
+	var commitRes []GithubCommitResponse
+
+	if err := json.Unmarshal([]byte(response.Body), &commitRes); err != nil {
+		log.Err(err).Msgf("marshal error, [%v]", err)
+		return nil, false, errors.New("could not unmarshal gitea commits response")
+	}
+
+	var cc []domain.Commit
+	for _, cr := range commitRes {
+		cc = append(cc, githubCommit(cr, repo))
+	}
+
+	return cc, g.hasNextPage(response), nil
+}
+
+// FetchTags lists the tags of the repository with the commit they point to
+func (g *GiteaClient) FetchTags(ctx context.Context, repo domain.RepoMetadata) ([]domain.Tag, error) {
+	var tags []domain.Tag
+
+	endpoint := fmt.Sprintf("%s/repos/%s/tags", g.baseURL, repo.Name)
+	err := g.fetchListing(ctx, endpoint, "tags", func(body string) error {
+		var tagRes []GitHubTagResponse
+		if err := json.Unmarshal([]byte(body), &tagRes); err != nil {
+			return err
+		}
+
+		for _, tr := range tagRes {
+			tags = append(tags, domain.Tag{Name: tr.Name, TargetSHA: tr.Commit.SHA})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return tags, nil
+}
+
+// FetchReleases lists the releases of the repository, the commit of a release is resolved from the tags
+func (g *GiteaClient) FetchReleases(ctx context.Context, repo domain.RepoMetadata) ([]domain.Release, error) {
+	var releases []domain.Release
+
+	endpoint := fmt.Sprintf("%s/repos/%s/releases", g.baseURL, repo.Name)
+	err := g.fetchListing(ctx, endpoint, "releases", func(body string) error {
+		var releaseRes []GitHubReleaseResponse
+		if err := json.Unmarshal([]byte(body), &releaseRes); err != nil {
+			return err
+		}
+
+		for _, rr := range releaseRes {
+			releases = append(releases, githubRelease(rr))
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return releases, nil
+}
+
+// fetchListing fetches every page of the listing at the endpoint and hands the body of each page to decode
+func (g *GiteaClient) fetchListing(ctx context.Context, endpoint string, resource string, decode func(body string) error) error {
+	for page := 1; ; page++ {
+		queryParams := map[string]string{
+			"limit": "50",
+			"page":  strconv.Itoa(page),
+		}
+
+		response, err := g.client.Get(ctx, endpoint, client.WithQuery(queryParams), client.WithHeaders(g.getHeaders()))
+		if err != nil {
+			log.Error().Msgf("error fetching gitea %s: %v", resource, err)
+			return requestError(err)
+		}
+
+		if response.StatusCode == http.StatusTooManyRequests {
+			log.Error().Msgf("failed to fetch gitea %s; status code: %v, body: %v", resource, response.StatusCode, response.Body)
+			return message.ErrRateLimitExceeded
+		}
+
+		if response.StatusCode != http.StatusOK {
+			log.Error().Msgf("failed to fetch gitea %s; status code: %v, body: %v", resource, response.StatusCode, response.Body)
+			return fmt.Errorf("failed to fetch %s; status code: %v, body: %v", resource, response.StatusCode, response.Body)
+		}
+
+		if err := decode(response.Body); err != nil {
+			log.Err(err).Msgf("marshal error, [%v]", err)
+			return fmt.Errorf("could not unmarshal gitea %s response", resource)
+		}
+
+		if !g.hasNextPage(response) {
+			return nil
+		}
+	}
+}
+
+// hasNextPage checks the X-HasMore header, falling back to the 'next' link for older instances
+func (g *GiteaClient) hasNextPage(resp *client.Response) bool {
+	hasMore := resp.Headers["X-Hasmore"]
+	if len(hasMore) > 0 {
+		return hasMore[0] == "true"
+	}
+
+	linkHeader := resp.Headers["Link"]
+	if len(linkHeader) > 0 {
+		_, hasNext := parseLinkHeader(linkHeader[0])["next"]
+		return hasNext
+	}
+	return false
+}
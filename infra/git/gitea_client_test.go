@@ -0,0 +1,69 @@
+package git_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/kenmobility/git-api-service/infra/git"
+	"github.com/kenmobility/git-api-service/internal/domain"
+	"github.com/kenmobility/git-api-service/pkg/message"
+	"github.com/stretchr/testify/require"
+)
+
+func newGiteaTestServer(t *testing.T) *httptest.Server {
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("/api/v1/repos/sample/repo", func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "token test_token", r.Header.Get("Authorization"))
+		w.Write([]byte(`{"full_name": "sample/repo", "description": "A sample repository", "html_url": "https://gitea.example.com/sample/repo",
+			"language": "Go", "stars_count": 5, "forks_count": 1, "watchers_count": 2, "open_issues_count": 3}`))
+	})
+	mux.HandleFunc("/api/v1/repos/limited/repo", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Ratelimit-Remaining", "0")
+		w.WriteHeader(http.StatusTooManyRequests)
+	})
+	mux.HandleFunc("/api/v1/repos/sample/repo/commits", func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "50", r.URL.Query().Get("limit"))
+		w.Header().Set("X-HasMore", "true")
+		w.Write([]byte(`[{"sha": "abc123", "html_url": "https://gitea.example.com/sample/repo/commit/abc123",
+			"commit": {"message": "Initial commit", "author": {"name": "john", "email": "john@example.com", "date": "2024-01-02T15:04:05Z"}}}]`))
+	})
+
+	return httptest.NewServer(mux)
+}
+
+func TestGiteaFetchRepoMetadata(t *testing.T) {
+	server := newGiteaTestServer(t)
+	defer server.Close()
+
+	gitClient := git.NewGiteaClient(server.URL+"/api/v1/", "test_token")
+
+	metadata, err := gitClient.FetchRepoMetadata(context.Background(), "sample/repo")
+	require.NoError(t, err)
+	require.Equal(t, "sample/repo", metadata.Name)
+	require.Equal(t, 5, metadata.StarsCount)
+	require.Equal(t, 2, metadata.WatchersCount)
+
+	_, err = gitClient.FetchRepoMetadata(context.Background(), "unknown/repo")
+	require.Equal(t, message.ErrRepositoryNotFound, err)
+
+	_, err = gitClient.FetchRepoMetadata(context.Background(), "limited/repo")
+	require.Equal(t, message.ErrRateLimitExceeded, err)
+}
+
+func TestGiteaFetchCommits(t *testing.T) {
+	server := newGiteaTestServer(t)
+	defer server.Close()
+
+	gitClient := git.NewGiteaClient(server.URL+"/api/v1", "test_token")
+
+	commits, morePages, err := gitClient.FetchCommits(context.Background(), domain.RepoMetadata{Name: "sample/repo"}, time.Now().AddDate(0, -1, 0), time.Now(), "", 1, 50)
+	require.NoError(t, err)
+	require.True(t, morePages)
+	require.Len(t, commits, 1)
+	require.Equal(t, "abc123", commits[0].CommitID)
+	require.Equal(t, "john", commits[0].Author)
+}
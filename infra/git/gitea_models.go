@@ -0,0 +1,21 @@
+package git
+
+type (
+	// GiteaRepoMetadataResponse is the repository resource served by Gitea and Forgejo, commits,
+	// tags and releases are served in the same shape as GitHub's and are decoded into the GitHub responses
+	GiteaRepoMetadataResponse struct {
+		ID              int    `json:"id"`
+		Name            string `json:"name"`
+		FullName        string `json:"full_name"`
+		Description     string `json:"description"`
+		HtmlUrl         string `json:"html_url"`
+		DefaultBranch   string `json:"default_branch"`
+		Language        string `json:"language"`
+		StarsCount      int    `json:"stars_count"`
+		ForksCount      int    `json:"forks_count"`
+		WatchersCount   int    `json:"watchers_count"`
+		OpenIssuesCount int    `json:"open_issues_count"`
+		Private         bool   `json:"private"`
+		Archived        bool   `json:"archived"`
+	}
+)
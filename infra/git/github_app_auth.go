@@ -0,0 +1,242 @@
+package git
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/kenmobility/git-api-service/pkg/client"
+	"github.com/kenmobility/git-api-service/pkg/message"
+	"github.com/rs/zerolog/log"
+)
+
+// installation tokens are valid for an hour, they are refreshed a few minutes before they expire
+const installationTokenRefreshMargin = 5 * time.Minute
+
+// errInstallationGone is returned when the token of an installation cannot be created because the app was
+// uninstalled, or reinstalled under a new installation
+var errInstallationGone = errors.New("github app installation is gone")
+
+type installationToken struct {
+	token     string
+	expiresAt time.Time
+}
+
+// GitHubAppAuth authenticates as a GitHub App installation, each repository is resolved to the installation
+// of the app on it and requests for the repository are sent with a token of that installation
+type GitHubAppAuth struct {
+	baseURL    string
+	appID      int64
+	privateKey *rsa.PrivateKey
+	client     *client.RestClient
+
+	mu            sync.Mutex
+	installations map[string]int64
+	tokens        map[int64]installationToken
+}
+
+// NewGitHubAppAuth returns the authentication of the app with the PEM encoded RSA private key
+// generated for it, baseUrl is the REST api base url
+func NewGitHubAppAuth(baseUrl string, appID int64, privateKeyPEM []byte) (*GitHubAppAuth, error) {
+	privateKey, err := parseRSAPrivateKey(privateKeyPEM)
+	if err != nil {
+		return nil, err
+	}
+
+	return &GitHubAppAuth{
+		baseURL:       baseUrl,
+		appID:         appID,
+		privateKey:    privateKey,
+		client:        client.NewRestClient(),
+		installations: make(map[string]int64),
+		tokens:        make(map[int64]installationToken),
+	}, nil
+}
+
+// parseRSAPrivateKey parses a PKCS#1 key, as generated by GitHub, or a PKCS#8 one
+func parseRSAPrivateKey(privateKeyPEM []byte) (*rsa.PrivateKey, error) {
+	block, _ := pem.Decode(privateKeyPEM)
+	if block == nil {
+		return nil, errors.New("github app private key is not PEM encoded")
+	}
+
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("invalid github app private key: %v", err)
+	}
+
+	rsaKey, ok := key.(*rsa.PrivateKey)
+	if !ok {
+		return nil, errors.New("github app private key is not an RSA key")
+	}
+	return rsaKey, nil
+}
+
+// jwt returns a RS256 signed JWT authenticating as the app, the issue time is backdated to allow for clock drift
+func (a *GitHubAppAuth) jwt(now time.Time) (string, error) {
+	header, err := json.Marshal(map[string]string{"alg": "RS256", "typ": "JWT"})
+	if err != nil {
+		return "", err
+	}
+
+	claims, err := json.Marshal(map[string]any{
+		"iat": now.Add(-time.Minute).Unix(),
+		"exp": now.Add(9 * time.Minute).Unix(),
+		"iss": strconv.FormatInt(a.appID, 10),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	unsigned := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(claims)
+
+	digest := sha256.Sum256([]byte(unsigned))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, a.privateKey, crypto.SHA256, digest[:])
+	if err != nil {
+		return "", err
+	}
+
+	return unsigned + "." + base64.RawURLEncoding.EncodeToString(signature), nil
+}
+
+func (a *GitHubAppAuth) appHeaders() (map[string]string, error) {
+	jwt, err := a.jwt(time.Now())
+	if err != nil {
+		log.Err(err).Msgf("error signing github app jwt: %v", err)
+		return nil, err
+	}
+
+	return map[string]string{
+		"Accept":        "application/vnd.github+json",
+		"Authorization": fmt.Sprintf("Bearer %s", jwt),
+	}, nil
+}
+
+// Token returns an installation token of the installation of the app on the repository
+func (a *GitHubAppAuth) Token(ctx context.Context, repositoryName string) (string, error) {
+	installationID, err := a.installationID(ctx, repositoryName)
+	if err != nil {
+		return "", err
+	}
+
+	a.mu.Lock()
+	cached, ok := a.tokens[installationID]
+	a.mu.Unlock()
+
+	if ok && time.Until(cached.expiresAt) > installationTokenRefreshMargin {
+		return cached.token, nil
+	}
+
+	token, err := a.createInstallationToken(ctx, installationID)
+	if err == errInstallationGone {
+		// the cached installation is stale, the installation of the app on the repository is resolved again
+		a.forgetInstallation(installationID)
+
+		installationID, err = a.installationID(ctx, repositoryName)
+		if err != nil {
+			return "", err
+		}
+		token, err = a.createInstallationToken(ctx, installationID)
+	}
+	if err == errInstallationGone {
+		return "", message.ErrGitHubAppNotInstalled
+	}
+	if err != nil {
+		return "", err
+	}
+
+	a.mu.Lock()
+	a.tokens[installationID] = token
+	a.mu.Unlock()
+
+	return token.token, nil
+}
+
+// installationID resolves the installation of the app on the repository
+func (a *GitHubAppAuth) installationID(ctx context.Context, repositoryName string) (int64, error) {
+	a.mu.Lock()
+	id, ok := a.installations[repositoryName]
+	a.mu.Unlock()
+
+	if ok {
+		return id, nil
+	}
+
+	headers, err := a.appHeaders()
+	if err != nil {
+		return 0, err
+	}
+
+	endpoint := fmt.Sprintf("%s/repos/%s/installation", a.baseURL, repositoryName)
+
+	installation, resp, err := client.DoJSON[GitHubAppInstallationResponse](ctx, a.client, client.Get, endpoint, client.WithHeaders(headers))
+	if err != nil {
+		if resp != nil && resp.StatusCode == http.StatusNotFound {
+			log.Error().Msgf("github app is not installed on repo %s; status code: %v, body: %v", repositoryName, resp.StatusCode, resp.Body)
+			return 0, message.ErrGitHubAppNotInstalled
+		}
+
+		log.Error().Msgf("failed to fetch github app installation: %v", err)
+		return 0, requestError(err)
+	}
+
+	a.mu.Lock()
+	a.installations[repositoryName] = installation.ID
+	a.mu.Unlock()
+
+	return installation.ID, nil
+}
+
+// forgetInstallation drops the installation from the cache, along with the repositories resolved to it and its token
+func (a *GitHubAppAuth) forgetInstallation(installationID int64) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	for repositoryName, id := range a.installations {
+		if id == installationID {
+			delete(a.installations, repositoryName)
+		}
+	}
+	delete(a.tokens, installationID)
+}
+
+// createInstallationToken exchanges the app JWT for a token of the installation
+func (a *GitHubAppAuth) createInstallationToken(ctx context.Context, installationID int64) (installationToken, error) {
+	headers, err := a.appHeaders()
+	if err != nil {
+		return installationToken{}, err
+	}
+
+	endpoint := fmt.Sprintf("%s/app/installations/%d/access_tokens", a.baseURL, installationID)
+
+	tokenResponse, resp, err := client.DoJSON[GitHubAppInstallationTokenResponse](ctx, a.client, client.Post, endpoint, client.WithHeaders(headers))
+	if err != nil {
+		if resp != nil && (resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusUnauthorized) {
+			log.Warn().Msgf("github app installation %d is gone; status code: %v, body: %v", installationID, resp.StatusCode, resp.Body)
+			return installationToken{}, errInstallationGone
+		}
+
+		log.Error().Msgf("failed to create github app installation token: %v", err)
+		return installationToken{}, requestError(err)
+	}
+
+	log.Info().Msgf("created github app installation token for installation %d, expires at %v", installationID, tokenResponse.ExpiresAt)
+
+	return installationToken{token: tokenResponse.Token, expiresAt: tokenResponse.ExpiresAt}, nil
+}
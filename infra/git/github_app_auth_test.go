@@ -0,0 +1,174 @@
+package git_test
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kenmobility/git-api-service/infra/git"
+	"github.com/kenmobility/git-api-service/pkg/message"
+	"github.com/stretchr/testify/require"
+)
+
+// newGitHubAppTestServer fakes the installation endpoints of the GitHub api, installation tokens
+// expire after tokenTTL
+func newGitHubAppTestServer(t *testing.T, publicKey *rsa.PublicKey, tokenTTL time.Duration, tokensCreated *int) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/repos/sample/repo/installation" || r.URL.Path == "/repos/sample/missing/installation":
+			requireAppJWT(t, publicKey, r)
+			if strings.Contains(r.URL.Path, "missing") {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			w.Write([]byte(`{"id": 42, "account": {"login": "sample"}}`))
+
+		case r.URL.Path == "/app/installations/42/access_tokens":
+			require.Equal(t, http.MethodPost, r.Method)
+			requireAppJWT(t, publicKey, r)
+			*tokensCreated++
+			w.WriteHeader(http.StatusCreated)
+			fmt.Fprintf(w, `{"token": "installation-token-%d", "expires_at": %q}`, *tokensCreated, time.Now().Add(tokenTTL).Format(time.RFC3339))
+
+		case r.URL.Path == "/repos/sample/repo":
+			require.Equal(t, fmt.Sprintf("Bearer installation-token-%d", *tokensCreated), r.Header.Get("Authorization"))
+			w.Write([]byte(`{"full_name": "sample/repo", "html_url": "https://github.com/sample/repo", "language": "Go"}`))
+
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+}
+
+// requireAppJWT verifies the request is authenticated with a RS256 JWT of app 1234
+func requireAppJWT(t *testing.T, publicKey *rsa.PublicKey, r *http.Request) {
+	jwt, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	require.True(t, ok)
+
+	parts := strings.Split(jwt, ".")
+	require.Len(t, parts, 3)
+
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	require.NoError(t, err)
+
+	digest := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+	require.NoError(t, rsa.VerifyPKCS1v15(publicKey, crypto.SHA256, digest[:], signature))
+
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	require.NoError(t, err)
+
+	var claims struct {
+		Iss string `json:"iss"`
+		Iat int64  `json:"iat"`
+		Exp int64  `json:"exp"`
+	}
+	require.NoError(t, json.Unmarshal(payload, &claims))
+	require.Equal(t, "1234", claims.Iss)
+	require.Less(t, claims.Iat, time.Now().Unix())
+	require.Greater(t, claims.Exp, time.Now().Unix())
+}
+
+func newGitHubAppPrivateKey(t *testing.T) (*rsa.PrivateKey, []byte) {
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	return privateKey, pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(privateKey)})
+}
+
+func TestGitHubAppClientFetchRepoMetadata(t *testing.T) {
+	privateKey, privateKeyPEM := newGitHubAppPrivateKey(t)
+
+	tokensCreated := 0
+	server := newGitHubAppTestServer(t, &privateKey.PublicKey, time.Hour, &tokensCreated)
+	defer server.Close()
+
+	appAuth, err := git.NewGitHubAppAuth(server.URL, 1234, privateKeyPEM)
+	require.NoError(t, err)
+
+	gitClient := git.NewGitHubAppClient(server.URL, appAuth, time.Hour, nil)
+
+	for i := 0; i < 2; i++ {
+		metadata, err := gitClient.FetchRepoMetadata(context.Background(), "sample/repo")
+		require.NoError(t, err)
+		require.Equal(t, "sample/repo", metadata.Name)
+	}
+
+	// the installation token is reused until it is about to expire
+	require.Equal(t, 1, tokensCreated)
+
+	_, err = gitClient.FetchRepoMetadata(context.Background(), "sample/missing")
+	require.Equal(t, message.ErrGitHubAppNotInstalled, err)
+}
+
+func TestGitHubAppAuthRefreshesExpiringToken(t *testing.T) {
+	privateKey, privateKeyPEM := newGitHubAppPrivateKey(t)
+
+	tokensCreated := 0
+	server := newGitHubAppTestServer(t, &privateKey.PublicKey, 2*time.Minute, &tokensCreated)
+	defer server.Close()
+
+	appAuth, err := git.NewGitHubAppAuth(server.URL, 1234, privateKeyPEM)
+	require.NoError(t, err)
+
+	token, err := appAuth.Token(context.Background(), "sample/repo")
+	require.NoError(t, err)
+	require.Equal(t, "installation-token-1", token)
+
+	// the token expires within the refresh margin so a new one is created
+	token, err = appAuth.Token(context.Background(), "sample/repo")
+	require.NoError(t, err)
+	require.Equal(t, "installation-token-2", token)
+}
+
+func TestGitHubAppAuthResolvesReinstalledApp(t *testing.T) {
+	privateKey, privateKeyPEM := newGitHubAppPrivateKey(t)
+
+	installationID := 42
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requireAppJWT(t, &privateKey.PublicKey, r)
+
+		switch r.URL.Path {
+		case "/repos/sample/repo/installation":
+			fmt.Fprintf(w, `{"id": %d, "account": {"login": "sample"}}`, installationID)
+
+		case fmt.Sprintf("/app/installations/%d/access_tokens", installationID):
+			w.WriteHeader(http.StatusCreated)
+			fmt.Fprintf(w, `{"token": "installation-token-%d", "expires_at": %q}`, installationID, time.Now().Add(2*time.Minute).Format(time.RFC3339))
+
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	appAuth, err := git.NewGitHubAppAuth(server.URL, 1234, privateKeyPEM)
+	require.NoError(t, err)
+
+	token, err := appAuth.Token(context.Background(), "sample/repo")
+	require.NoError(t, err)
+	require.Equal(t, "installation-token-42", token)
+
+	// the app is reinstalled, tokens of the cached installation are no longer created
+	installationID = 43
+
+	token, err = appAuth.Token(context.Background(), "sample/repo")
+	require.NoError(t, err)
+	require.Equal(t, "installation-token-43", token)
+}
+
+func TestNewGitHubAppAuthInvalidKey(t *testing.T) {
+	_, err := git.NewGitHubAppAuth("https://api.github.com", 1234, []byte("not a key"))
+	require.Error(t, err)
+}
@@ -6,69 +6,159 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"net/url"
 	"strconv"
 	"strings"
 	"time"
 
 	"github.com/kenmobility/git-api-service/internal/domain"
+	"github.com/kenmobility/git-api-service/internal/repository"
 	"github.com/kenmobility/git-api-service/pkg/client"
 	"github.com/kenmobility/git-api-service/pkg/message"
 	"github.com/rs/zerolog/log"
 )
 
 type GitHubClient struct {
-	baseURL         string
-	token           string
-	fetchInterval   time.Duration
-	client          *client.RestClient
-	rateLimitFields rateLimitFields
+	baseURL       string
+	rateLimit     *RateLimitGovernor
+	fetchInterval time.Duration
+	client        *client.RestClient
+	validators    repository.HTTPValidatorRepository
+	cursors       *pageCursors
+	appAuth       *GitHubAppAuth
 }
 
-type rateLimitFields struct {
-	rateLimitLimit     int
-	rateLimitRemaining int
-	rateLimitReset     int
-}
-
-func (g *GitHubClient) getHeaders() map[string]string {
-	if len(g.token) == 0 {
+func (g *GitHubClient) getHeaders(token string) map[string]string {
+	if len(token) == 0 {
 		return map[string]string{}
 	}
 	return map[string]string{
 		"Content-Type":  "application/json",
-		"Authorization": fmt.Sprintf("Bearer %s", g.token),
+		"Authorization": fmt.Sprintf("Bearer %s", token),
 	}
 }
 
-func NewGitHubClient(baseUrl string, token string, fetchInterval time.Duration) GitManagerClient {
+// NewGitHubClient returns a REST backed client rotating requests over tokens, commit listings are requested
+// conditionally with the validators kept in validators, which can be nil to always download them
+func NewGitHubClient(baseUrl string, tokens []string, fetchInterval time.Duration, validators repository.HTTPValidatorRepository) GitManagerClient {
 	client := client.NewRestClient()
 
 	gc := GitHubClient{
 		baseURL:       baseUrl,
-		token:         token,
+		rateLimit:     NewRateLimitGovernor(tokens),
 		fetchInterval: fetchInterval,
 		client:        client,
+		validators:    validators,
+		cursors:       newPageCursors(),
 	}
 	ts := GitManagerClient(&gc)
 	return ts
 }
 
+// NewGitHubAppClient returns a REST backed client sending the requests of each repository with a token of
+// the installation of the app on it
+func NewGitHubAppClient(baseUrl string, appAuth *GitHubAppAuth, fetchInterval time.Duration, validators repository.HTTPValidatorRepository) GitManagerClient {
+	gc := GitHubClient{
+		baseURL:       baseUrl,
+		rateLimit:     NewRateLimitGovernor(nil),
+		fetchInterval: fetchInterval,
+		client:        client.NewRestClient(),
+		validators:    validators,
+		cursors:       newPageCursors(),
+		appAuth:       appAuth,
+	}
+	return &gc
+}
+
+// WithToken returns a REST backed client sending every request with the token, even when the client authenticates as an app
+func (g *GitHubClient) WithToken(token string) GitManagerClient {
+	return NewGitHubClient(g.baseURL, []string{token}, g.fetchInterval, g.validators)
+}
+
+// get sends the request of the repository with its installation token when authenticated as an app, otherwise
+// with the token having the most budget left, a request rejected because its
+// token ran out of budget is sent again with the next token, or once the earliest token was reset
+func (g *GitHubClient) get(ctx context.Context, repositoryName, endpoint string, headers map[string]string) (*client.Response, error) {
+	if g.appAuth != nil {
+		installationID, err := g.appAuth.installationID(ctx, repositoryName)
+		if err != nil {
+			return nil, err
+		}
+
+		// each installation has its own budget
+		budgetKey := fmt.Sprintf("installation-%d", installationID)
+		if err := g.rateLimit.Wait(ctx, budgetKey); err != nil {
+			return nil, err
+		}
+
+		token, err := g.appAuth.Token(ctx, repositoryName)
+		if err != nil {
+			return nil, err
+		}
+
+		requestHeaders := g.getHeaders(token)
+		for key, value := range headers {
+			requestHeaders[key] = value
+		}
+
+		resp, err := g.client.Get(ctx, endpoint, client.WithHeaders(requestHeaders))
+		if err != nil {
+			return nil, requestError(err)
+		}
+
+		g.rateLimit.Update(budgetKey, resp)
+		return resp, nil
+	}
+
+	var resp *client.Response
+
+	for attempt := 0; attempt <= g.rateLimit.size(); attempt++ {
+		token, err := g.rateLimit.Acquire(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		requestHeaders := g.getHeaders(token)
+		for key, value := range headers {
+			requestHeaders[key] = value
+		}
+
+		resp, err = g.client.Get(ctx, endpoint, client.WithHeaders(requestHeaders))
+		if err != nil {
+			return nil, requestError(err)
+		}
+
+		g.rateLimit.Update(token, resp)
+
+		if !client.IsPrimaryRateLimit(resp) {
+			return resp, nil
+		}
+	}
+	return resp, nil
+}
+
+// RateLimitStatus returns the budget left over the tokens of the client
+func (g *GitHubClient) RateLimitStatus() domain.RateLimitStatus {
+	return g.rateLimit.Status()
+}
+
 func (g *GitHubClient) FetchRepoMetadata(ctx context.Context, repositoryName string) (*domain.RepoMetadata, error) {
 	endpoint := fmt.Sprintf("%s/repos/%s", g.baseURL, repositoryName)
 
-	resp, err := g.client.Get(endpoint)
+	resp, err := g.get(ctx, repositoryName, endpoint, map[string]string{})
 	if err != nil {
 		return nil, err
 	}
 
-	if resp.StatusCode == http.StatusForbidden {
+	// a 403 without an exhausted budget is a permission error
+	if client.IsPrimaryRateLimit(resp) {
 		log.Error().Msgf("failed to fetch repository meta data; status code: %v, body: %v", resp.StatusCode, resp.Body)
 		return nil, message.ErrRateLimitExceeded
 	}
 
 	if resp.StatusCode != http.StatusOK {
 		log.Error().Msgf("failed to fetch repository meta data; status code: %v, body: %v", resp.StatusCode, resp.Body)
-		return nil, message.ErrRepoMetaDataNotFetched
+		return nil, repoMetadataError(resp.StatusCode)
 	}
 
 	var gitHubRepoResponse GitHubRepoMetadataResponse
@@ -87,120 +177,531 @@ func (g *GitHubClient) FetchRepoMetadata(ctx context.Context, repositoryName str
 		StarsCount:      gitHubRepoResponse.StargazersCount,
 		OpenIssuesCount: gitHubRepoResponse.OpenIssues,
 		WatchersCount:   gitHubRepoResponse.WatchersCount,
+		DefaultBranch:   gitHubRepoResponse.DefaultBranch,
+		Status:          repoStatus(gitHubRepoResponse.Archived),
 	}
 
 	return repoMetadata, nil
 }
 
+// FetchCommits serves page numbers by following the next links of the pages reached before, the url of a
+// page that was not reached, eg after a restart, is built from its number
 func (g *GitHubClient) FetchCommits(ctx context.Context, repo domain.RepoMetadata, since time.Time, until time.Time, lastFetchedCommit string, page, perPage int) ([]domain.Commit, bool, error) {
-	var endpoint string
+	listing := g.commitsURL(repo, since, until, lastFetchedCommit, perPage)
 
-	if lastFetchedCommit != "" {
-		endpoint = fmt.Sprintf("%s/repos/%s/commits?sha=%s&per_page=%d&page=%d", g.baseURL, repo.Name, lastFetchedCommit, perPage, page)
+	endpoint, ok := listing, page <= 1
+	if !ok {
+		endpoint, ok = g.cursors.get(listing, page)
+	}
+	if !ok {
+		endpoint = fmt.Sprintf("%s&page=%d", listing, page)
+	}
+
+	commitPage, err := g.fetchCommitPage(ctx, repo, endpoint, page <= 1 && lastFetchedCommit == "")
+	if err != nil {
+		return nil, false, err
+	}
+
+	// callers paging with page numbers do not tell when their walk succeeded, the validators are only saved
+	// when the first page is the whole listing
+	if commitPage.Validator != nil && !commitPage.MorePages {
+		if err := g.SaveValidator(ctx, *commitPage.Validator); err != nil {
+			log.Err(err).Msgf("error saving cache validators of %s", endpoint)
+		}
+	}
+
+	if commitPage.MorePages {
+		g.cursors.storeNext(listing, page, commitPage.Cursor)
 	} else {
-		endpoint = fmt.Sprintf("%s/repos/%s/commits?since=%s&until=%s&per_page=%d&page=%d", g.baseURL, repo.Name, since.Format(time.RFC3339), until.Format(time.RFC3339), perPage, page)
+		g.cursors.storeNext(listing, page, "")
+	}
+
+	return commitPage.Commits, commitPage.MorePages, nil
+}
+
+// FetchCommitsAfter fetches the page at the cursor, which is the exact next link of the previous page,
+// or the first page of the listing when it is empty
+func (g *GitHubClient) FetchCommitsAfter(ctx context.Context, repo domain.RepoMetadata, since time.Time, until time.Time, lastFetchedCommit string, cursor string, perPage int) (*CommitPage, error) {
+	endpoint := cursor
+	if endpoint == "" {
+		endpoint = g.commitsURL(repo, since, until, lastFetchedCommit, perPage)
+	}
+
+	return g.fetchCommitPage(ctx, repo, endpoint, cursor == "" && lastFetchedCommit == "")
+}
+
+// FetchBranches lists the branches of the repository with their head commit, following the next links
+// of the listing
+func (g *GitHubClient) FetchBranches(ctx context.Context, repo domain.RepoMetadata) ([]domain.Branch, error) {
+	var branches []domain.Branch
+
+	endpoint := fmt.Sprintf("%s/repos/%s/branches?per_page=100", g.baseURL, repo.Name)
+	err := g.fetchListing(ctx, repo, endpoint, "branches", func(body string) error {
+		var branchRes []GitHubBranchResponse
+		if err := json.Unmarshal([]byte(body), &branchRes); err != nil {
+			return err
+		}
+
+		for _, br := range branchRes {
+			branches = append(branches, domain.Branch{Name: br.Name, HeadSHA: br.Commit.SHA})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	response, err := g.client.Get(endpoint, map[string]string{}, g.getHeaders())
+	return branches, nil
+}
+
+// FetchTags lists the tags of the repository with the commit they point to, following the next links
+// of the listing
+func (g *GitHubClient) FetchTags(ctx context.Context, repo domain.RepoMetadata) ([]domain.Tag, error) {
+	var tags []domain.Tag
+
+	endpoint := fmt.Sprintf("%s/repos/%s/tags?per_page=100", g.baseURL, repo.Name)
+	err := g.fetchListing(ctx, repo, endpoint, "tags", func(body string) error {
+		var tagRes []GitHubTagResponse
+		if err := json.Unmarshal([]byte(body), &tagRes); err != nil {
+			return err
+		}
+
+		for _, tr := range tagRes {
+			tags = append(tags, domain.Tag{Name: tr.Name, TargetSHA: tr.Commit.SHA})
+		}
+		return nil
+	})
 	if err != nil {
-		log.Error().Msgf("error fetching commits: %v", err)
+		return nil, err
+	}
 
-		return nil, false, err
+	return tags, nil
+}
+
+// FetchReleases lists the releases of the repository, drafts are only listed to tokens with push access.
+// GitHub does not serve the commit of a release, it is resolved from the tags of the repository
+func (g *GitHubClient) FetchReleases(ctx context.Context, repo domain.RepoMetadata) ([]domain.Release, error) {
+	var releases []domain.Release
+
+	endpoint := fmt.Sprintf("%s/repos/%s/releases?per_page=100", g.baseURL, repo.Name)
+	err := g.fetchListing(ctx, repo, endpoint, "releases", func(body string) error {
+		var releaseRes []GitHubReleaseResponse
+		if err := json.Unmarshal([]byte(body), &releaseRes); err != nil {
+			return err
+		}
+
+		for _, rr := range releaseRes {
+			releases = append(releases, githubRelease(rr))
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	if response.StatusCode == http.StatusForbidden {
-		log.Error().Msgf("failed to fetch repository meta data; status code: %v, body: %v", response.StatusCode, response.Body)
-		return nil, false, message.ErrRateLimitExceeded
+	return releases, nil
+}
+
+// FetchPullRequests lists the pull requests of the repository most recently updated first, paging stops at the
+// first pull request last updated before since
+func (g *GitHubClient) FetchPullRequests(ctx context.Context, repo domain.RepoMetadata, since time.Time) ([]domain.PullRequest, error) {
+	var pullRequests []domain.PullRequest
+
+	endpoint := fmt.Sprintf("%s/repos/%s/pulls?state=all&sort=updated&direction=desc&per_page=100", g.baseURL, repo.Name)
+	err := g.fetchListing(ctx, repo, endpoint, "pull requests", func(body string) error {
+		var pullRes []GitHubPullRequestResponse
+		if err := json.Unmarshal([]byte(body), &pullRes); err != nil {
+			return err
+		}
+
+		for _, pr := range pullRes {
+			if pr.UpdatedAt.Before(since) {
+				return errListingComplete
+			}
+			pullRequests = append(pullRequests, githubPullRequest(pr))
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return pullRequests, nil
+}
+
+// FetchPullRequestCommits lists the ids of the commits of the pull request, GitHub lists up to 250 commits
+// of a pull request
+func (g *GitHubClient) FetchPullRequestCommits(ctx context.Context, repo domain.RepoMetadata, number int) ([]string, error) {
+	var commitIDs []string
+
+	endpoint := fmt.Sprintf("%s/repos/%s/pulls/%d/commits?per_page=100", g.baseURL, repo.Name, number)
+	err := g.fetchListing(ctx, repo, endpoint, "pull request commits", func(body string) error {
+		var commitRes []GithubCommitResponse
+		if err := json.Unmarshal([]byte(body), &commitRes); err != nil {
+			return err
+		}
+
+		for _, cr := range commitRes {
+			commitIDs = append(commitIDs, cr.SHA)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	g.updateRateLimitHeaders(response)
+	return commitIDs, nil
+}
 
-	if g.rateLimitFields.rateLimitRemaining == 0 {
-		waitTime := time.Until(time.Unix(int64(g.rateLimitFields.rateLimitReset), 0))
-		log.Info().Msgf("Rate limit exceeded. Waiting for %v until reset...", waitTime)
-		time.Sleep(waitTime)
+// githubPullRequest maps a pull request of the GitHub pulls listing
+func githubPullRequest(pr GitHubPullRequestResponse) domain.PullRequest {
+	pullRequest := domain.PullRequest{
+		Number:    pr.Number,
+		Title:     pr.Title,
+		State:     pr.State,
+		URL:       pr.HtmlURL,
+		BaseRef:   pr.Base.Ref,
+		HeadRef:   pr.Head.Ref,
+		CreatedAt: pr.CreatedAt,
+		UpdatedAt: pr.UpdatedAt,
+	}
+	if pr.User != nil {
+		pullRequest.Author = pr.User.Login
+	}
+	if pr.MergedAt != nil {
+		pullRequest.State = domain.PullRequestMerged
+		pullRequest.MergedAt = *pr.MergedAt
+		pullRequest.MergeCommitSHA = pr.MergeCommitSHA
+	}
+	if pr.ClosedAt != nil {
+		pullRequest.ClosedAt = *pr.ClosedAt
+	}
+	return pullRequest
+}
+
+// githubRelease maps a release of the GitHub releases listing, which Gitea serves as well
+func githubRelease(rr GitHubReleaseResponse) domain.Release {
+	release := domain.Release{
+		TagName:    rr.TagName,
+		Title:      rr.Name,
+		Body:       rr.Body,
+		Draft:      rr.Draft,
+		Prerelease: rr.Prerelease,
+	}
+	if rr.PublishedAt != nil {
+		release.PublishedAt = *rr.PublishedAt
+	}
+	return release
+}
+
+// errListingComplete is returned by the decode function of fetchListing to stop before the last page
+var errListingComplete = errors.New("listing complete")
+
+// fetchListing fetches every page of the listing at the endpoint, following the next links, and hands the
+// body of each page to decode
+func (g *GitHubClient) fetchListing(ctx context.Context, repo domain.RepoMetadata, endpoint string, resource string, decode func(body string) error) error {
+	for endpoint != "" {
+		response, err := g.get(ctx, repo.Name, endpoint, map[string]string{})
+		if err != nil {
+			log.Error().Msgf("error fetching %s: %v", resource, err)
+			return err
+		}
+
+		if client.IsPrimaryRateLimit(response) {
+			log.Error().Msgf("failed to fetch %s; status code: %v, body: %v", resource, response.StatusCode, response.Body)
+			return message.ErrRateLimitExceeded
+		}
+
+		if response.StatusCode != http.StatusOK {
+			log.Error().Msgf("failed to fetch %s; status code: %v, body: %v", resource, response.StatusCode, response.Body)
+			return fmt.Errorf("failed to fetch %s; status code: %v, body: %v", resource, response.StatusCode, response.Body)
+		}
+
+		if err := decode(response.Body); err != nil {
+			if err == errListingComplete {
+				return nil
+			}
+			log.Err(err).Msgf("marshal error, [%v]", err)
+			return fmt.Errorf("could not unmarshal %s response", resource)
+		}
+
+		endpoint = ""
+		if linkHeader := response.Headers["Link"]; len(linkHeader) > 0 {
+			endpoint = parseLinkHeader(linkHeader[0])["next"]
+		}
+	}
+	return nil
+}
+
+// FetchBranchCommits fetches the page of the history of the branch at the cursor, which is the exact next
+// link of the previous page, or the first page when it is empty
+func (g *GitHubClient) FetchBranchCommits(ctx context.Context, repo domain.RepoMetadata, branch string, since time.Time, until time.Time, cursor string, perPage int) (*CommitPage, error) {
+	endpoint := cursor
+	if endpoint == "" {
+		endpoint = fmt.Sprintf("%s/repos/%s/commits?sha=%s&since=%s&until=%s&per_page=%d", g.baseURL, repo.Name, url.QueryEscape(branch),
+			since.Format(time.RFC3339), until.Format(time.RFC3339), perPage)
+	}
+
+	return g.fetchCommitPage(ctx, repo, endpoint, cursor == "")
+}
+
+// commitsURL returns the url of the first page of the commit listing
+func (g *GitHubClient) commitsURL(repo domain.RepoMetadata, since time.Time, until time.Time, lastFetchedCommit string, perPage int) string {
+	if lastFetchedCommit != "" {
+		return fmt.Sprintf("%s/repos/%s/commits?sha=%s&per_page=%d", g.baseURL, repo.Name, lastFetchedCommit, perPage)
+	}
+	return fmt.Sprintf("%s/repos/%s/commits?since=%s&until=%s&per_page=%d", g.baseURL, repo.Name, since.Format(time.RFC3339), until.Format(time.RFC3339), perPage)
+}
+
+// fetchCommitPage fetches the commits at the endpoint, the cursor of the page is its next link and the
+// number of pages is read from its last link. Conditional requests are only sent for the first page of the
+// listing of a branch head, which monitoring fetches again every round: a 304 tells the listing did not change
+// so there are no further pages to walk, and listings resumed from a commit are not fetched twice. The validators
+// of the page are returned with it, the caller saves them once it walked the listing
+func (g *GitHubClient) fetchCommitPage(ctx context.Context, repo domain.RepoMetadata, endpoint string, conditional bool) (*CommitPage, error) {
+	headers := map[string]string{}
+	if conditional {
+		headers = g.conditionalHeaders(ctx, endpoint)
+	}
+
+	response, err := g.get(ctx, repo.Name, endpoint, headers)
+	if err != nil {
+		log.Error().Msgf("error fetching commits: %v", err)
+
+		return nil, err
+	}
+
+	// the listing did not change since it was last fetched, a 304 does not count against the rate limit
+	if response.StatusCode == http.StatusNotModified {
+		log.Info().Msgf("commits of repo %s not modified since last fetch", repo.Name)
+		return &CommitPage{}, nil
+	}
+
+	if client.IsPrimaryRateLimit(response) {
+		log.Error().Msgf("failed to fetch commits; status code: %v, body: %v", response.StatusCode, response.Body)
+		return nil, message.ErrRateLimitExceeded
 	}
 
 	if response.StatusCode != http.StatusOK {
 		log.Error().Msgf("failed to fetch commits; status code: %v, body: %v", response.StatusCode, response.Body)
-		return nil, false, fmt.Errorf("failed to fetch commits; status code: %v, body: %v", response.StatusCode, response.Body)
+		return nil, fmt.Errorf("failed to fetch commits; status code: %v, body: %v", response.StatusCode, response.Body)
 	}
 
 	var commitRes []GithubCommitResponse
 
 	if err := json.Unmarshal([]byte(response.Body), &commitRes); err != nil {
 		log.Err(err).Msgf("marshal error, [%v]", err)
-		return nil, false, errors.New("could not unmarshal commits response")
+		return nil, errors.New("could not unmarshal commits response")
 	}
 
 	var cc []domain.Commit
 	for _, cr := range commitRes {
-		commit := domain.Commit{
-			CommitID:       cr.SHA,
-			Message:        cr.Commit.Message,
-			Author:         cr.Commit.Author.Name,
-			Date:           cr.Commit.Author.Date,
-			URL:            cr.HtmlURL,
-			RepositoryName: repo.Name,
-		}
+		cc = append(cc, githubCommit(cr, repo))
+	}
+
+	links := map[string]string{}
+	if linkHeader := response.Headers["Link"]; len(linkHeader) > 0 {
+		links = parseLinkHeader(linkHeader[0])
+	}
 
-		cc = append(cc, commit)
+	commitPage := &CommitPage{Commits: cc}
+	commitPage.Cursor, commitPage.MorePages = links["next"]
+	if conditional {
+		commitPage.Validator = httpValidator(endpoint, response)
 	}
 
-	// Determine if more pages exist
-	morePages := false
-	linkHeader := response.Headers["Link"]
-	if len(linkHeader) > 0 {
-		morePages = g.hasNextPage(linkHeader[0])
+	if last, ok := links["last"]; ok {
+		commitPage.TotalPages = pageNumber(last)
+	} else if !commitPage.MorePages {
+		// the last page has no last link
+		commitPage.TotalPages = pageNumber(endpoint)
 	}
 
-	return cc, morePages, nil
+	return commitPage, nil
 }
 
-// hasNextPage checks if there is a 'next' link in the Link header
-func (g *GitHubClient) hasNextPage(linkHeader string) bool {
-	links := g.parseLinkHeader(linkHeader)
-	_, hasNext := links["next"]
-	return hasNext
+// FetchCommit fetches the commit with its change statistics and the files it changed, GitHub lists up to
+// 300 files of a commit
+func (g *GitHubClient) FetchCommit(ctx context.Context, repo domain.RepoMetadata, commitID string) (*domain.Commit, error) {
+	endpoint := fmt.Sprintf("%s/repos/%s/commits/%s", g.baseURL, repo.Name, commitID)
+
+	response, err := g.get(ctx, repo.Name, endpoint, map[string]string{})
+	if err != nil {
+		log.Error().Msgf("error fetching commit %s: %v", commitID, err)
+		return nil, err
+	}
+
+	if client.IsPrimaryRateLimit(response) {
+		log.Error().Msgf("failed to fetch commit; status code: %v, body: %v", response.StatusCode, response.Body)
+		return nil, message.ErrRateLimitExceeded
+	}
+
+	// an unknown sha is answered with a 422
+	if response.StatusCode == http.StatusNotFound || response.StatusCode == http.StatusUnprocessableEntity {
+		log.Error().Msgf("failed to fetch commit; status code: %v, body: %v", response.StatusCode, response.Body)
+		return nil, message.ErrCommitNotFetched
+	}
+
+	if response.StatusCode != http.StatusOK {
+		log.Error().Msgf("failed to fetch commit; status code: %v, body: %v", response.StatusCode, response.Body)
+		return nil, fmt.Errorf("failed to fetch commit; status code: %v, body: %v", response.StatusCode, response.Body)
+	}
+
+	var cr GithubCommitResponse
+	if err := json.Unmarshal([]byte(response.Body), &cr); err != nil {
+		log.Err(err).Msgf("marshal error, [%v]", err)
+		return nil, errors.New("could not unmarshal commit response")
+	}
+
+	commit := githubCommit(cr, repo)
+	commit.EnrichedAt = time.Now()
+	if cr.Stats != nil {
+		commit.Additions = cr.Stats.Additions
+		commit.Deletions = cr.Stats.Deletions
+		commit.TotalChanges = cr.Stats.Total
+	}
+
+	for _, f := range cr.Files {
+		commit.Files = append(commit.Files, domain.CommitFile{
+			Path:         f.Filename,
+			Status:       f.Status,
+			Additions:    f.Additions,
+			Deletions:    f.Deletions,
+			PreviousPath: f.PreviousFilename,
+		})
+	}
+
+	return &commit, nil
 }
 
-// parseLinkHeader parses the Link header into a map
-func (g *GitHubClient) parseLinkHeader(header string) map[string]string {
-	links := make(map[string]string)
+// githubCommit maps a commit served in GitHub's shape, as GitHub and Gitea do, to a domain commit
+func githubCommit(cr GithubCommitResponse, repo domain.RepoMetadata) domain.Commit {
+	commit := domain.Commit{
+		CommitID:       cr.SHA,
+		Message:        cr.Commit.Message,
+		Author:         cr.Commit.Author.Name,
+		AuthorEmail:    cr.Commit.Author.Email,
+		Date:           cr.Commit.Author.Date,
+		CommitterName:  cr.Commit.Committer.Name,
+		CommitterEmail: cr.Commit.Committer.Email,
+		CommitterDate:  cr.Commit.Committer.Date,
+		URL:            cr.HtmlURL,
+		RepositoryName: repo.Name,
+		RepositoryHost: repo.Host,
+	}
+	if cr.Author != nil {
+		commit.AuthorLogin = cr.Author.Login
+		commit.AuthorAvatarURL = cr.Author.AvatarURL
+	}
+	if cr.Committer != nil {
+		commit.CommitterLogin = cr.Committer.Login
+	}
 
-	for _, part := range strings.Split(header, ",") {
-		sections := strings.Split(part, ";")
-		if len(sections) < 2 {
-			continue
+	for _, parent := range cr.Parents {
+		commit.ParentSHAs = append(commit.ParentSHAs, parent.SHA)
+	}
+	commit.IsMerge = len(commit.ParentSHAs) > 1
+
+	return commit
+}
+
+// conditionalHeaders returns the validators stored for the endpoint as conditional request headers
+func (g *GitHubClient) conditionalHeaders(ctx context.Context, endpoint string) map[string]string {
+	headers := map[string]string{}
+	if g.validators == nil {
+		return headers
+	}
+
+	validator, err := g.validators.ValidatorByEndpoint(ctx, validatorKey(endpoint))
+	if err != nil {
+		if err != message.ErrNoRecordFound {
+			log.Err(err).Msgf("error getting cache validators of %s", endpoint)
 		}
-		url := strings.Trim(sections[0], " <>")
-		rel := strings.Trim(sections[1], " rel=\"")
-		links[rel] = url
+		return headers
 	}
-	return links
+
+	if validator.ETag != "" {
+		headers["If-None-Match"] = validator.ETag
+	}
+	if validator.LastModified != "" {
+		headers["If-Modified-Since"] = validator.LastModified
+	}
+	return headers
+}
+
+// httpValidator returns the ETag and Last-Modified validators of the response to the endpoint, nil when it has none
+func httpValidator(endpoint string, resp *client.Response) *domain.HTTPValidator {
+	validator := domain.HTTPValidator{
+		Endpoint:  validatorKey(endpoint),
+		UpdatedAt: time.Now(),
+	}
+	if etag := resp.Headers["Etag"]; len(etag) > 0 {
+		validator.ETag = etag[0]
+	}
+	if lastModified := resp.Headers["Last-Modified"]; len(lastModified) > 0 {
+		validator.LastModified = lastModified[0]
+	}
+
+	if validator.ETag == "" && validator.LastModified == "" {
+		return nil
+	}
+	return &validator
+}
+
+// SaveValidator stores the validators for the next conditional request to their endpoint
+func (g *GitHubClient) SaveValidator(ctx context.Context, validator domain.HTTPValidator) error {
+	if g.validators == nil {
+		return nil
+	}
+	return g.validators.SaveValidator(ctx, validator)
+}
+
+// validatorKey returns the endpoint the validators of a listing are stored under, without its until parameter which
+// is moved to the current time on every round. GitHub ETags hash the response body, so the validators of a previous
+// window still match a listing whose commits did not change
+func validatorKey(endpoint string) string {
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return endpoint
+	}
+
+	query := u.Query()
+	query.Del("until")
+	u.RawQuery = query.Encode()
+	return u.String()
 }
 
-// updateRateLimitHeaders updates the link header rate limit values to the fields
-func (api *GitHubClient) updateRateLimitHeaders(resp *client.Response) {
-	limit := resp.Headers["X-Ratelimit-Limit"]
-	if len(limit) > 0 {
-		api.rateLimitFields.rateLimitLimit, _ = strconv.Atoi(limit[0])
+// pageNumber returns the page parameter of a listing url, the first page has none
+func pageNumber(pageURL string) int {
+	u, err := url.Parse(pageURL)
+	if err != nil {
+		return 0
 	}
 
-	remaining := resp.Headers["X-Ratelimit-Remaining"]
-	if len(remaining) > 0 {
-		api.rateLimitFields.rateLimitRemaining, _ = strconv.Atoi(remaining[0])
-		log.Info().Msgf("Rate limit remaining: %d", api.rateLimitFields.rateLimitRemaining)
+	page := u.Query().Get("page")
+	if page == "" {
+		return 1
 	}
 
-	reset := resp.Headers["X-Ratelimit-Reset"]
-	if len(reset) > 0 {
-		api.rateLimitFields.rateLimitReset, _ = strconv.Atoi(reset[0])
+	n, err := strconv.Atoi(page)
+	if err != nil {
+		return 0
 	}
+	return n
+}
+
+// parseLinkHeader parses the Link header into a map
+func parseLinkHeader(header string) map[string]string {
+	links := make(map[string]string)
 
-	used := resp.Headers["X-Ratelimit-Used"]
-	if len(used) > 0 {
-		usedInt, _ := strconv.Atoi(used[0])
-		log.Info().Msgf("Rate limit used: %d/%d", usedInt, api.rateLimitFields.rateLimitLimit)
+	for _, part := range strings.Split(header, ",") {
+		sections := strings.Split(part, ";")
+		if len(sections) < 2 {
+			continue
+		}
+		link := strings.Trim(sections[0], " <>")
+		rel := strings.Trim(strings.TrimPrefix(strings.TrimSpace(sections[1]), "rel="), "\"")
+		links[rel] = link
 	}
+	return links
 }
@@ -0,0 +1,426 @@
+package git_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/kenmobility/git-api-service/infra/git"
+	"github.com/kenmobility/git-api-service/internal/domain"
+	repo_mocks "github.com/kenmobility/git-api-service/internal/repository/mocks"
+	"github.com/kenmobility/git-api-service/pkg/message"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestGitHubFetchCommitsConditional(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("page") == "2" {
+			require.Empty(t, r.Header.Get("If-None-Match"))
+			w.Write([]byte(`[{"sha": "def456", "commit": {"message": "Older commit", "author": {"name": "john", "date": "2024-01-02T10:00:00Z"}}}]`))
+			return
+		}
+
+		if r.Header.Get("If-None-Match") == `"abc"` {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+
+		w.Header().Set("ETag", `"abc"`)
+		w.Header().Set("Last-Modified", "Wed, 03 Jan 2024 10:00:00 GMT")
+		w.Header().Set("Link", fmt.Sprintf(`<%s/repos/sample/repo/commits?page=2>; rel="next"`, server.URL))
+		w.Write([]byte(`[{"sha": "abc123", "html_url": "https://github.com/sample/repo/commit/abc123",
+			"commit": {"message": "Initial commit", "author": {"name": "john", "date": "2024-01-03T10:00:00Z"}}}]`))
+	}))
+	defer server.Close()
+
+	validators := repo_mocks.NewMockRepository(ctrl)
+	gitClient := git.NewGitHubClient(server.URL, nil, time.Hour, validators)
+	repo := domain.RepoMetadata{Name: "sample/repo"}
+	since, until := time.Now().AddDate(0, -1, 0), time.Now()
+
+	// the first request has no validators, the ones of the response are returned with the page and saved by the
+	// caller once it walked the listing
+	validators.EXPECT().ValidatorByEndpoint(gomock.Any(), gomock.Any()).Return(nil, message.ErrNoRecordFound)
+
+	commitPage, err := gitClient.(git.CursorCommitFetcher).FetchCommitsAfter(context.Background(), repo, since, until, "", "", 10)
+	require.NoError(t, err)
+	require.True(t, commitPage.MorePages)
+	require.Len(t, commitPage.Commits, 1)
+	require.NotNil(t, commitPage.Validator)
+	require.Equal(t, `"abc"`, commitPage.Validator.ETag)
+	require.Equal(t, "Wed, 03 Jan 2024 10:00:00 GMT", commitPage.Validator.LastModified)
+	require.NotContains(t, commitPage.Validator.Endpoint, "until=")
+
+	// the next pages are not requested conditionally, their validators are neither looked up nor returned
+	commitPage, err = gitClient.(git.CursorCommitFetcher).FetchCommitsAfter(context.Background(), repo, since, until, "", commitPage.Cursor, 10)
+	require.NoError(t, err)
+	require.Len(t, commitPage.Commits, 1)
+	require.Nil(t, commitPage.Validator)
+
+	// page numbered walks do not report when they succeeded, the validators of a first page followed by others are not saved
+	validators.EXPECT().ValidatorByEndpoint(gomock.Any(), gomock.Any()).Return(nil, message.ErrNoRecordFound)
+	commits, morePages, err := gitClient.FetchCommits(context.Background(), repo, since, until, "", 1, 10)
+	require.NoError(t, err)
+	require.True(t, morePages)
+	require.Len(t, commits, 1)
+
+	validator := domain.HTTPValidator{Endpoint: "key", ETag: `"abc"`}
+	validators.EXPECT().SaveValidator(gomock.Any(), validator).Return(nil)
+	require.NoError(t, gitClient.(git.ValidatorSaver).SaveValidator(context.Background(), validator))
+
+	// an unchanged listing is answered with a 304 and no commits, the validators match a later until
+	validators.EXPECT().ValidatorByEndpoint(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, key string) (*domain.HTTPValidator, error) {
+		require.NotContains(t, key, "until=")
+		return &domain.HTTPValidator{ETag: `"abc"`}, nil
+	})
+
+	commits, morePages, err = gitClient.FetchCommits(context.Background(), repo, since, until.Add(time.Hour), "", 1, 10)
+	require.NoError(t, err)
+	require.False(t, morePages)
+	require.Empty(t, commits)
+}
+
+func TestGitHubFetchCommitsTokenRotation(t *testing.T) {
+	reset := time.Now().Add(time.Hour).Unix()
+	var requests []string
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token := r.Header.Get("Authorization")
+		requests = append(requests, token)
+
+		w.Header().Set("X-Ratelimit-Limit", "5000")
+		w.Header().Set("X-Ratelimit-Reset", fmt.Sprint(reset))
+
+		// token-a runs out of budget on its second request
+		if token == "Bearer token-a" && len(requests) > 1 {
+			w.Header().Set("X-Ratelimit-Remaining", "0")
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+
+		if token == "Bearer token-a" {
+			w.Header().Set("X-Ratelimit-Remaining", "4000")
+		} else {
+			w.Header().Set("X-Ratelimit-Remaining", "10")
+		}
+		w.Write([]byte(`[]`))
+	}))
+	defer server.Close()
+
+	gitClient := git.NewGitHubClient(server.URL, []string{"token-a", "token-b"}, time.Hour, nil)
+	repo := domain.RepoMetadata{Name: "sample/repo"}
+	since, until := time.Now().AddDate(0, -1, 0), time.Now()
+
+	for i := 0; i < 4; i++ {
+		_, _, err := gitClient.FetchCommits(context.Background(), repo, since, until, "", 1, 10)
+		require.NoError(t, err)
+	}
+
+	// both tokens are tried once, then the one with the most budget is used until it is exhausted
+	require.Equal(t, []string{"Bearer token-a", "Bearer token-b", "Bearer token-a", "Bearer token-b", "Bearer token-b"}, requests)
+}
+
+func TestGitHubFetchCommitsWaitsForReset(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Ratelimit-Remaining", "0")
+		w.Header().Set("X-Ratelimit-Reset", fmt.Sprint(time.Now().Add(time.Hour).Unix()))
+		w.Write([]byte(`[]`))
+	}))
+	defer server.Close()
+
+	gitClient := git.NewGitHubClient(server.URL, []string{"token-a"}, time.Hour, nil)
+	repo := domain.RepoMetadata{Name: "sample/repo"}
+	since, until := time.Now().AddDate(0, -1, 0), time.Now()
+
+	_, _, err := gitClient.FetchCommits(context.Background(), repo, since, until, "", 1, 10)
+	require.NoError(t, err)
+
+	// the only token is exhausted, the next request waits for its reset until the context is done
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	_, _, err = gitClient.FetchCommits(ctx, repo, since, until, "", 1, 10)
+	require.Equal(t, message.ErrContextCancelled, err)
+}
+
+func TestGitHubFetchRepoMetadataForbidden(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/repos/sample/limited" {
+			w.Header().Set("X-Ratelimit-Remaining", "0")
+		}
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	defer server.Close()
+
+	gitClient := git.NewGitHubClient(server.URL, nil, time.Hour, nil)
+
+	// a 403 without an exhausted budget is a permission error
+	_, err := gitClient.FetchRepoMetadata(context.Background(), "sample/private")
+	require.Equal(t, message.ErrRepositoryInaccessible, err)
+
+	_, err = gitClient.FetchRepoMetadata(context.Background(), "sample/limited")
+	require.Equal(t, message.ErrRateLimitExceeded, err)
+}
+
+func TestGitHubFetchRepoMetadataMovedOrGone(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/repos/sample/old-name":
+			http.Redirect(w, r, "/repositories/42", http.StatusMovedPermanently)
+		case "/repositories/42":
+			require.Equal(t, "Bearer token-a", r.Header.Get("Authorization"))
+			w.Write([]byte(`{"id": 42, "full_name": "other/new-name", "default_branch": "main", "archived": true}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	gitClient := git.NewGitHubClient(server.URL, []string{"token-a"}, time.Hour, nil)
+
+	// renamed and transferred repositories are redirected to their current name
+	metadata, err := gitClient.FetchRepoMetadata(context.Background(), "sample/old-name")
+	require.NoError(t, err)
+	require.Equal(t, "other/new-name", metadata.Name)
+	require.Equal(t, domain.RepoStatusArchived, metadata.Status)
+
+	_, err = gitClient.FetchRepoMetadata(context.Background(), "sample/deleted")
+	require.Equal(t, message.ErrRepositoryNotFound, err)
+}
+
+func TestGitHubFetchCommitsFollowsLinks(t *testing.T) {
+	var requests []string
+
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.URL.RawQuery)
+
+		// the next link carries a cursor that can not be rebuilt from the page number
+		switch r.URL.Query().Get("cursor") {
+		case "":
+			w.Header().Set("Link", fmt.Sprintf(`<%s/repositories/1/commits?cursor=p2&page=2>; rel="next", <%s/repositories/1/commits?cursor=p3&page=3>; rel="last"`, server.URL, server.URL))
+			w.Write([]byte(`[{"sha": "abc123", "commit": {"message": "Third commit", "author": {"name": "john"}}}]`))
+		case "p2":
+			w.Header().Set("Link", fmt.Sprintf(`<%s/repositories/1/commits?cursor=p3&page=3>; rel="next", <%s/repositories/1/commits?cursor=p3&page=3>; rel="last"`, server.URL, server.URL))
+			w.Write([]byte(`[{"sha": "def456", "commit": {"message": "Second commit", "author": {"name": "jane"}}}]`))
+		default:
+			w.Header().Set("Link", fmt.Sprintf(`<%s/repositories/1/commits?cursor=p1&page=1>; rel="first"`, server.URL))
+			w.Write([]byte(`[{"sha": "ghi789", "commit": {"message": "Initial commit", "author": {"name": "jane"}}}]`))
+		}
+	}))
+	defer server.Close()
+
+	gitClient := git.NewGitHubClient(server.URL, nil, time.Hour, nil)
+	repo := domain.RepoMetadata{Name: "sample/repo"}
+	since, until := time.Now().AddDate(0, -1, 0), time.Now()
+
+	cursorClient := gitClient.(git.CursorCommitFetcher)
+
+	commitPage, err := cursorClient.FetchCommitsAfter(context.Background(), repo, since, until, "", "", 1)
+	require.NoError(t, err)
+	require.True(t, commitPage.MorePages)
+	require.Equal(t, 3, commitPage.TotalPages)
+	require.Equal(t, "abc123", commitPage.Commits[0].CommitID)
+
+	commitPage, err = cursorClient.FetchCommitsAfter(context.Background(), repo, since, until, "", commitPage.Cursor, 1)
+	require.NoError(t, err)
+	require.Equal(t, "def456", commitPage.Commits[0].CommitID)
+
+	commitPage, err = cursorClient.FetchCommitsAfter(context.Background(), repo, since, until, "", commitPage.Cursor, 1)
+	require.NoError(t, err)
+	require.False(t, commitPage.MorePages)
+	require.Equal(t, 3, commitPage.TotalPages)
+	require.Equal(t, "ghi789", commitPage.Commits[0].CommitID)
+
+	// page numbers follow the next links of the pages reached before
+	_, morePages, err := gitClient.FetchCommits(context.Background(), repo, since, until, "", 1, 1)
+	require.NoError(t, err)
+	require.True(t, morePages)
+
+	commits, _, err := gitClient.FetchCommits(context.Background(), repo, since, until, "", 2, 1)
+	require.NoError(t, err)
+	require.Equal(t, "def456", commits[0].CommitID)
+	require.Equal(t, "cursor=p2&page=2", requests[len(requests)-1])
+}
+
+func TestGitHubFetchCommit(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/repos/sample/repo/commits/abc123" {
+			w.WriteHeader(http.StatusUnprocessableEntity)
+			return
+		}
+
+		w.Write([]byte(`{"sha": "abc123", "html_url": "https://github.com/sample/repo/commit/abc123",
+			"commit": {"message": "Rename main", "author": {"name": "john", "email": "john@example.com", "date": "2024-01-03T10:00:00Z"},
+				"committer": {"name": "GitHub", "email": "noreply@github.com", "date": "2024-01-04T10:00:00Z"}},
+			"author": {"login": "john-dev", "avatar_url": "https://avatars.githubusercontent.com/u/1"}, "committer": {"login": "web-flow"},
+			"parents": [{"sha": "aaa111"}, {"sha": "bbb222"}],
+			"stats": {"additions": 12, "deletions": 3, "total": 15},
+			"files": [{"filename": "cmd/main.go", "status": "renamed", "additions": 2, "deletions": 1, "previous_filename": "main.go"},
+				{"filename": "README.md", "status": "modified", "additions": 10, "deletions": 2}]}`))
+	}))
+	defer server.Close()
+
+	gitClient := git.NewGitHubClient(server.URL, nil, time.Hour, nil).(git.CommitDetailFetcher)
+	repo := domain.RepoMetadata{Name: "sample/repo", Host: "github.com"}
+
+	commit, err := gitClient.FetchCommit(context.Background(), repo, "abc123")
+	require.NoError(t, err)
+	require.Equal(t, "john@example.com", commit.AuthorEmail)
+	require.Equal(t, "john-dev", commit.AuthorLogin)
+	require.Equal(t, "https://avatars.githubusercontent.com/u/1", commit.AuthorAvatarURL)
+	require.Equal(t, time.Date(2024, 1, 3, 10, 0, 0, 0, time.UTC), commit.Date)
+	require.Equal(t, "GitHub", commit.CommitterName)
+	require.Equal(t, "noreply@github.com", commit.CommitterEmail)
+	require.Equal(t, "web-flow", commit.CommitterLogin)
+	require.Equal(t, time.Date(2024, 1, 4, 10, 0, 0, 0, time.UTC), commit.CommitterDate)
+	require.Equal(t, []string{"aaa111", "bbb222"}, commit.ParentSHAs)
+	require.True(t, commit.IsMerge)
+	require.Equal(t, 12, commit.Additions)
+	require.Equal(t, 3, commit.Deletions)
+	require.Equal(t, 15, commit.TotalChanges)
+	require.False(t, commit.EnrichedAt.IsZero())
+	require.Len(t, commit.Files, 2)
+	require.Equal(t, domain.CommitFile{Path: "cmd/main.go", Status: "renamed", Additions: 2, Deletions: 1, PreviousPath: "main.go"}, commit.Files[0])
+
+	_, err = gitClient.FetchCommit(context.Background(), repo, "missing")
+	require.Equal(t, message.ErrCommitNotFetched, err)
+}
+
+func TestGitHubFetchBranches(t *testing.T) {
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/repos/sample/repo/branches":
+			if r.URL.Query().Get("page") == "" {
+				w.Header().Set("Link", fmt.Sprintf(`<%s/repos/sample/repo/branches?per_page=100&page=2>; rel="next"`, server.URL))
+				w.Write([]byte(`[{"name": "main", "commit": {"sha": "abc123"}}]`))
+				return
+			}
+			w.Write([]byte(`[{"name": "release/1.0", "commit": {"sha": "def456"}}]`))
+		case "/repos/sample/repo/commits":
+			require.Equal(t, "release/1.0", r.URL.Query().Get("sha"))
+			w.Write([]byte(`[{"sha": "def456", "commit": {"message": "Release 1.0", "author": {"name": "jane"}}}]`))
+		}
+	}))
+	defer server.Close()
+
+	gitClient := git.NewGitHubClient(server.URL, nil, time.Hour, nil).(git.BranchCommitFetcher)
+	repo := domain.RepoMetadata{Name: "sample/repo"}
+
+	branches, err := gitClient.FetchBranches(context.Background(), repo)
+	require.NoError(t, err)
+	require.Equal(t, []domain.Branch{{Name: "main", HeadSHA: "abc123"}, {Name: "release/1.0", HeadSHA: "def456"}}, branches)
+
+	commitPage, err := gitClient.FetchBranchCommits(context.Background(), repo, "release/1.0", time.Now().AddDate(0, -1, 0), time.Now(), "", 10)
+	require.NoError(t, err)
+	require.False(t, commitPage.MorePages)
+	require.Equal(t, "def456", commitPage.Commits[0].CommitID)
+}
+
+func TestGitHubFetchTagsAndReleases(t *testing.T) {
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/repos/sample/repo/tags":
+			if r.URL.Query().Get("page") == "" {
+				w.Header().Set("Link", fmt.Sprintf(`<%s/repos/sample/repo/tags?per_page=100&page=2>; rel="next"`, server.URL))
+				w.Write([]byte(`[{"name": "v1.1.0", "commit": {"sha": "def456"}}]`))
+				return
+			}
+			w.Write([]byte(`[{"name": "v1.0.0", "commit": {"sha": "abc123"}}]`))
+		case "/repos/sample/repo/releases":
+			w.Write([]byte(`[
+				{"tag_name": "v1.2.0", "target_commitish": "main", "name": "Next", "draft": true, "published_at": null},
+				{"tag_name": "v1.1.0", "target_commitish": "main", "name": "1.1", "body": "Fixes", "prerelease": true, "published_at": "2024-02-01T10:00:00Z"}
+			]`))
+		}
+	}))
+	defer server.Close()
+
+	gitClient := git.NewGitHubClient(server.URL, nil, time.Hour, nil)
+	repo := domain.RepoMetadata{Name: "sample/repo"}
+
+	tags, err := gitClient.FetchTags(context.Background(), repo)
+	require.NoError(t, err)
+	require.Equal(t, []domain.Tag{{Name: "v1.1.0", TargetSHA: "def456"}, {Name: "v1.0.0", TargetSHA: "abc123"}}, tags)
+
+	releases, err := gitClient.FetchReleases(context.Background(), repo)
+	require.NoError(t, err)
+	require.Len(t, releases, 2)
+	require.True(t, releases[0].Draft)
+	require.True(t, releases[0].PublishedAt.IsZero())
+	require.Equal(t, domain.Release{
+		TagName:     "v1.1.0",
+		Title:       "1.1",
+		Body:        "Fixes",
+		Prerelease:  true,
+		PublishedAt: time.Date(2024, 2, 1, 10, 0, 0, 0, time.UTC),
+	}, releases[1])
+}
+
+func TestGitHubFetchPullRequests(t *testing.T) {
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/repos/sample/repo/pulls":
+			require.Equal(t, "all", r.URL.Query().Get("state"))
+			require.Equal(t, "updated", r.URL.Query().Get("sort"))
+			if r.URL.Query().Get("page") == "" {
+				w.Header().Set("Link", fmt.Sprintf(`<%s/repos/sample/repo/pulls?state=all&sort=updated&page=2>; rel="next"`, server.URL))
+				w.Write([]byte(`[
+					{"number": 2, "title": "Add feature", "state": "closed", "user": {"login": "jane"}, "base": {"ref": "main"}, "head": {"ref": "feature"},
+					 "merge_commit_sha": "abc123", "created_at": "2024-03-01T10:00:00Z", "updated_at": "2024-03-03T10:00:00Z",
+					 "merged_at": "2024-03-02T10:00:00Z", "closed_at": "2024-03-02T10:00:00Z"},
+					{"number": 3, "title": "Draft", "state": "open", "merge_commit_sha": "fff000", "created_at": "2024-02-01T10:00:00Z", "updated_at": "2024-02-20T10:00:00Z"}
+				]`))
+				return
+			}
+			w.Write([]byte(`[{"number": 1, "title": "Old", "state": "closed", "updated_at": "2024-01-01T10:00:00Z"}]`))
+		case "/repos/sample/repo/pulls/2/commits":
+			w.Write([]byte(`[{"sha": "def456"}, {"sha": "987fed"}]`))
+		}
+	}))
+	defer server.Close()
+
+	gitClient := git.NewGitHubClient(server.URL, nil, time.Hour, nil).(git.PullRequestFetcher)
+	repo := domain.RepoMetadata{Name: "sample/repo"}
+
+	// paging stops at the first pull request updated before since
+	pullRequests, err := gitClient.FetchPullRequests(context.Background(), repo, time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+	require.Len(t, pullRequests, 2)
+	require.Equal(t, domain.PullRequest{
+		Number:         2,
+		Title:          "Add feature",
+		State:          domain.PullRequestMerged,
+		Author:         "jane",
+		BaseRef:        "main",
+		HeadRef:        "feature",
+		MergeCommitSHA: "abc123",
+		CreatedAt:      time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC),
+		UpdatedAt:      time.Date(2024, 3, 3, 10, 0, 0, 0, time.UTC),
+		MergedAt:       time.Date(2024, 3, 2, 10, 0, 0, 0, time.UTC),
+		ClosedAt:       time.Date(2024, 3, 2, 10, 0, 0, 0, time.UTC),
+	}, pullRequests[0])
+
+	// the test merge commit of an open pull request is not kept
+	require.Equal(t, domain.PullRequestOpen, pullRequests[1].State)
+	require.Empty(t, pullRequests[1].MergeCommitSHA)
+
+	pullRequests, err = gitClient.FetchPullRequests(context.Background(), repo, time.Time{})
+	require.NoError(t, err)
+	require.Len(t, pullRequests, 3)
+
+	commitIDs, err := gitClient.FetchPullRequestCommits(context.Background(), repo, 2)
+	require.NoError(t, err)
+	require.Equal(t, []string{"def456", "987fed"}, commitIDs)
+}
@@ -0,0 +1,727 @@
+package git
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/kenmobility/git-api-service/internal/domain"
+	"github.com/kenmobility/git-api-service/pkg/client"
+	"github.com/kenmobility/git-api-service/pkg/message"
+	"github.com/rs/zerolog/log"
+)
+
+const githubRepoQuery = `query($owner: String!, $name: String!) {
+  repository(owner: $owner, name: $name) {
+    nameWithOwner description url stargazerCount forkCount isArchived
+    primaryLanguage { name }
+    watchers { totalCount }
+    issues(states: OPEN) { totalCount }
+    defaultBranchRef { name }
+  }
+  rateLimit { limit remaining cost resetAt }
+}`
+
+const githubHistoryFields = `history(first: $first, after: $after, since: $since, until: $until) {
+  totalCount
+  pageInfo { hasNextPage endCursor }
+  nodes {
+    oid url message authoredDate committedDate additions deletions
+    author { name email date user { login avatarUrl } }
+    committer { name email date user { login } }
+    parents(first: 10) { nodes { oid } }
+  }
+}`
+
+// githubDefaultBranchHistoryQuery lists the history of the default branch
+const githubDefaultBranchHistoryQuery = `query($owner: String!, $name: String!, $first: Int!, $after: String, $since: GitTimestamp, $until: GitTimestamp) {
+  repository(owner: $owner, name: $name) {
+    ref: defaultBranchRef { target { ... on Commit { ` + githubHistoryFields + ` } } }
+  }
+  rateLimit { limit remaining cost resetAt }
+}`
+
+// githubCommitHistoryQuery lists the history reachable from a commit
+const githubCommitHistoryQuery = `query($owner: String!, $name: String!, $oid: GitObjectID!, $first: Int!, $after: String, $since: GitTimestamp, $until: GitTimestamp) {
+  repository(owner: $owner, name: $name) {
+    object(oid: $oid) { ... on Commit { ` + githubHistoryFields + ` } }
+  }
+  rateLimit { limit remaining cost resetAt }
+}`
+
+// githubBranchHistoryQuery lists the history of a branch, given as its qualified ref name
+const githubBranchHistoryQuery = `query($owner: String!, $name: String!, $branch: String!, $first: Int!, $after: String, $since: GitTimestamp, $until: GitTimestamp) {
+  repository(owner: $owner, name: $name) {
+    ref(qualifiedName: $branch) { target { ... on Commit { ` + githubHistoryFields + ` } } }
+  }
+  rateLimit { limit remaining cost resetAt }
+}`
+
+// githubBranchesQuery lists the branches of a repository with their head commit
+const githubBranchesQuery = `query($owner: String!, $name: String!, $after: String) {
+  repository(owner: $owner, name: $name) {
+    refs(refPrefix: "refs/heads/", first: 100, after: $after) {
+      pageInfo { hasNextPage endCursor }
+      nodes { name target { oid } }
+    }
+  }
+  rateLimit { limit remaining cost resetAt }
+}`
+
+// githubPullRequestsQuery lists the pull requests of a repository most recently updated first
+const githubPullRequestsQuery = `query($owner: String!, $name: String!, $after: String) {
+  repository(owner: $owner, name: $name) {
+    pullRequests(first: 100, after: $after, orderBy: {field: UPDATED_AT, direction: DESC}) {
+      pageInfo { hasNextPage endCursor }
+      nodes {
+        number title state url baseRefName headRefName createdAt updatedAt mergedAt closedAt
+        author { login }
+        mergeCommit { oid }
+      }
+    }
+  }
+  rateLimit { limit remaining cost resetAt }
+}`
+
+// githubPullRequestCommitsQuery lists the commits of a pull request
+const githubPullRequestCommitsQuery = `query($owner: String!, $name: String!, $number: Int!, $after: String) {
+  repository(owner: $owner, name: $name) {
+    pullRequest(number: $number) {
+      commits(first: 100, after: $after) {
+        pageInfo { hasNextPage endCursor }
+        nodes { commit { oid } }
+      }
+    }
+  }
+  rateLimit { limit remaining cost resetAt }
+}`
+
+// githubTagsQuery lists the tags of a repository, the target of an annotated tag is a tag object pointing to the commit
+const githubTagsQuery = `query($owner: String!, $name: String!, $after: String) {
+  repository(owner: $owner, name: $name) {
+    refs(refPrefix: "refs/tags/", first: 100, after: $after) {
+      pageInfo { hasNextPage endCursor }
+      nodes { name target { oid ... on Tag { target { oid } } } }
+    }
+  }
+  rateLimit { limit remaining cost resetAt }
+}`
+
+// githubReleasesQuery lists the releases of a repository
+const githubReleasesQuery = `query($owner: String!, $name: String!, $after: String) {
+  repository(owner: $owner, name: $name) {
+    releases(first: 100, after: $after) {
+      pageInfo { hasNextPage endCursor }
+      nodes { tagName name description isDraft isPrerelease publishedAt tagCommit { oid } }
+    }
+  }
+  rateLimit { limit remaining cost resetAt }
+}`
+
+// GitHubGraphQLClient fetches repositories through the GitHub GraphQL v4 API, paging commit history
+// with cursors so a page is not shifted by commits pushed while paging
+type GitHubGraphQLClient struct {
+	graphqlURL string
+	client     *client.RestClient
+	cursors    *pageCursors
+	rateLimit  *RateLimitGovernor
+}
+
+func (g *GitHubGraphQLClient) getHeaders(token string) map[string]string {
+	return map[string]string{
+		"Content-Type":  "application/json",
+		"Authorization": fmt.Sprintf("Bearer %s", token),
+	}
+}
+
+// NewGitHubGraphQLClient returns a GraphQL backed client rotating queries over tokens, the GraphQL API does not
+// serve anonymous requests so at least a token is required
+func NewGitHubGraphQLClient(graphqlUrl string, tokens []string) GitManagerClient {
+	client := client.NewRestClient()
+
+	gc := GitHubGraphQLClient{
+		graphqlURL: graphqlUrl,
+		client:     client,
+		cursors:    newPageCursors(),
+		rateLimit:  NewRateLimitGovernor(tokens),
+	}
+	return &gc
+}
+
+// WithToken returns a client sending its queries with the token
+func (g *GitHubGraphQLClient) WithToken(token string) GitManagerClient {
+	return NewGitHubGraphQLClient(g.graphqlURL, []string{token})
+}
+
+// RateLimitStatus returns the GraphQL budget left, which is separate from the REST api budget
+func (g *GitHubGraphQLClient) RateLimitStatus() domain.RateLimitStatus {
+	return g.rateLimit.Status()
+}
+
+// query posts a GraphQL query with the token having the most budget left and decodes its response into result.
+// A query whose token ran out of budget is sent again with the next token, or once the earliest token was reset
+func (g *GitHubGraphQLClient) query(ctx context.Context, query string, variables map[string]any, result any) error {
+	body, err := json.Marshal(GraphQLRequest{Query: query, Variables: variables})
+	if err != nil {
+		return err
+	}
+
+	var resp *client.Response
+	token := ""
+	for attempt := 0; attempt <= g.rateLimit.size(); attempt++ {
+		token, err = g.rateLimit.Acquire(ctx)
+		if err != nil {
+			return err
+		}
+
+		resp, err = g.client.Post(ctx, g.graphqlURL, client.WithBody(body), client.WithHeaders(g.getHeaders(token)))
+		if err != nil {
+			return requestError(err)
+		}
+
+		g.rateLimit.Update(token, resp)
+
+		if !client.IsPrimaryRateLimit(resp) {
+			break
+		}
+	}
+
+	if client.IsPrimaryRateLimit(resp) {
+		log.Error().Msgf("failed to query github graphql api; status code: %v, body: %v", resp.StatusCode, resp.Body)
+		return message.ErrRateLimitExceeded
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		log.Error().Msgf("failed to query github graphql api; status code: %v, body: %v", resp.StatusCode, resp.Body)
+		return fmt.Errorf("failed to query github graphql api; status code: %v, body: %v", resp.StatusCode, resp.Body)
+	}
+
+	if err := json.Unmarshal([]byte(resp.Body), result); err != nil {
+		log.Err(err).Msgf("marshal error, [%v]", err)
+		return errors.New("could not unmarshal github graphql response")
+	}
+
+	// every query selects the rateLimit field, which reports the budget of the token sent with it
+	var rateLimitResponse GraphQLRateLimitResponse
+	if err := json.Unmarshal([]byte(resp.Body), &rateLimitResponse); err == nil {
+		g.recordRateLimit(token, rateLimitResponse.Data.RateLimit)
+	}
+	return nil
+}
+
+// graphQLError maps the errors of a GraphQL response, which is served with status 200 even when it failed
+func graphQLError(errs []GraphQLError) error {
+	if len(errs) == 0 {
+		return nil
+	}
+
+	for _, e := range errs {
+		switch e.Type {
+		case "RATE_LIMITED":
+			return message.ErrRateLimitExceeded
+		case "NOT_FOUND":
+			return message.ErrRepoMetaDataNotFetched
+		}
+	}
+	return fmt.Errorf("github graphql error: %s", errs[0].Message)
+}
+
+// recordRateLimit records the budget reported in a response to a query sent with the token
+func (g *GitHubGraphQLClient) recordRateLimit(token string, rateLimit GraphQLRateLimit) {
+	if rateLimit.Limit == 0 {
+		// the rateLimit field was not returned
+		return
+	}
+
+	log.Info().Msgf("GraphQL query cost: %d", rateLimit.Cost)
+	g.rateLimit.Record(token, rateLimit.Limit, rateLimit.Remaining, rateLimit.ResetAt)
+}
+
+func splitRepositoryName(repositoryName string) (string, string, error) {
+	owner, name, ok := strings.Cut(repositoryName, "/")
+	if !ok || owner == "" || name == "" {
+		return "", "", message.ErrInvalidRepositoryName
+	}
+	return owner, name, nil
+}
+
+func (g *GitHubGraphQLClient) FetchRepoMetadata(ctx context.Context, repositoryName string) (*domain.RepoMetadata, error) {
+	owner, name, err := splitRepositoryName(repositoryName)
+	if err != nil {
+		return nil, err
+	}
+
+	var repoResponse GitHubGraphQLRepoResponse
+	if err := g.query(ctx, githubRepoQuery, map[string]any{"owner": owner, "name": name}, &repoResponse); err != nil {
+		if err == message.ErrRateLimitExceeded {
+			return nil, err
+		}
+		return nil, message.ErrRepoMetaDataNotFetched
+	}
+
+	if err := graphQLError(repoResponse.Errors); err != nil {
+		log.Error().Msgf("failed to fetch repository meta data: %v", repoResponse.Errors)
+		if err == message.ErrRateLimitExceeded {
+			return nil, err
+		}
+		if err == message.ErrRepoMetaDataNotFetched {
+			// the NOT_FOUND error of a repository deleted or made private
+			return nil, message.ErrRepositoryNotFound
+		}
+		return nil, message.ErrRepoMetaDataNotFetched
+	}
+
+	r := repoResponse.Data.Repository
+	if r == nil {
+		return nil, message.ErrRepositoryNotFound
+	}
+
+	repoMetadata := &domain.RepoMetadata{
+		Name:            r.NameWithOwner,
+		Description:     r.Description,
+		URL:             r.URL,
+		ForksCount:      r.ForkCount,
+		StarsCount:      r.StargazerCount,
+		OpenIssuesCount: r.Issues.TotalCount,
+		WatchersCount:   r.Watchers.TotalCount,
+		Status:          repoStatus(r.IsArchived),
+	}
+	if r.PrimaryLanguage != nil {
+		repoMetadata.Language = r.PrimaryLanguage.Name
+	}
+	if r.DefaultBranchRef != nil {
+		repoMetadata.DefaultBranch = r.DefaultBranchRef.Name
+	}
+
+	return repoMetadata, nil
+}
+
+// FetchCommits serves page numbers by walking the history cursors, callers able to keep
+// the cursor should use FetchCommitsAfter instead
+func (g *GitHubGraphQLClient) FetchCommits(ctx context.Context, repo domain.RepoMetadata, since time.Time, until time.Time, lastFetchedCommit string, page, perPage int) ([]domain.Commit, bool, error) {
+	listing := fmt.Sprintf("%s|%s|%s|%s|%d", repo.Name, lastFetchedCommit, since.Format(time.RFC3339), until.Format(time.RFC3339), perPage)
+
+	cursor, ok := "", page <= 1
+	if !ok {
+		cursor, ok = g.cursors.get(listing, page)
+	}
+
+	if !ok {
+		// the page was not reached before, eg after a restart
+		for p := 1; p < page; p++ {
+			if ctx.Err() != nil {
+				return nil, false, message.ErrContextCancelled
+			}
+
+			commitPage, err := g.FetchCommitsAfter(ctx, repo, since, until, lastFetchedCommit, cursor, perPage)
+			if err != nil {
+				return nil, false, err
+			}
+			if !commitPage.MorePages {
+				g.cursors.storeNext(listing, p, "")
+				return nil, false, nil
+			}
+			cursor = commitPage.Cursor
+		}
+	}
+
+	commitPage, err := g.FetchCommitsAfter(ctx, repo, since, until, lastFetchedCommit, cursor, perPage)
+	if err != nil {
+		return nil, false, err
+	}
+
+	if commitPage.MorePages {
+		g.cursors.storeNext(listing, page, commitPage.Cursor)
+	} else {
+		g.cursors.storeNext(listing, page, "")
+	}
+
+	return commitPage.Commits, commitPage.MorePages, nil
+}
+
+// FetchCommitsAfter fetches the page of history after the cursor, the number of pages is derived from the
+// total count of commits in the history
+func (g *GitHubGraphQLClient) FetchCommitsAfter(ctx context.Context, repo domain.RepoMetadata, since time.Time, until time.Time, lastFetchedCommit string, cursor string, perPage int) (*CommitPage, error) {
+	variables, err := historyVariables(repo, cursor, perPage)
+	if err != nil {
+		return nil, err
+	}
+
+	query := githubDefaultBranchHistoryQuery
+	if lastFetchedCommit != "" {
+		// like the REST api's sha parameter, resuming from a commit lists its history regardless of the window
+		query = githubCommitHistoryQuery
+		variables["oid"] = lastFetchedCommit
+	} else {
+		variables["since"] = since.Format(time.RFC3339)
+		variables["until"] = until.Format(time.RFC3339)
+	}
+
+	commitPage, err := g.fetchHistory(ctx, repo, query, variables, perPage)
+	if err != nil {
+		return nil, err
+	}
+	if commitPage == nil {
+		// an empty repository has no default branch
+		return &CommitPage{}, nil
+	}
+	return commitPage, nil
+}
+
+// FetchBranchCommits fetches the page of the history of the branch after the cursor, or the first page when it is empty
+func (g *GitHubGraphQLClient) FetchBranchCommits(ctx context.Context, repo domain.RepoMetadata, branch string, since time.Time, until time.Time, cursor string, perPage int) (*CommitPage, error) {
+	variables, err := historyVariables(repo, cursor, perPage)
+	if err != nil {
+		return nil, err
+	}
+	variables["branch"] = "refs/heads/" + branch
+	variables["since"] = since.Format(time.RFC3339)
+	variables["until"] = until.Format(time.RFC3339)
+
+	commitPage, err := g.fetchHistory(ctx, repo, githubBranchHistoryQuery, variables, perPage)
+	if err != nil {
+		return nil, err
+	}
+	if commitPage == nil {
+		log.Error().Msgf("branch %s of repo %s was not found", branch, repo.Name)
+		return nil, fmt.Errorf("branch %s of repo %s was not found", branch, repo.Name)
+	}
+	return commitPage, nil
+}
+
+// historyVariables returns the variables of a history query of the repository
+func historyVariables(repo domain.RepoMetadata, cursor string, perPage int) (map[string]any, error) {
+	owner, name, err := splitRepositoryName(repo.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	variables := map[string]any{
+		"owner": owner,
+		"name":  name,
+		"first": perPage,
+		"after": nil,
+		"since": nil,
+		"until": nil,
+	}
+	if cursor != "" {
+		variables["after"] = cursor
+	}
+	return variables, nil
+}
+
+// fetchHistory runs the history query, the commits are listed with their change statistics so that they do not need
+// to be enriched. No page is returned when the ref or commit the history starts from was not found
+func (g *GitHubGraphQLClient) fetchHistory(ctx context.Context, repo domain.RepoMetadata, query string, variables map[string]any, perPage int) (*CommitPage, error) {
+	var historyResponse GitHubGraphQLHistoryResponse
+	if err := g.query(ctx, query, variables, &historyResponse); err != nil {
+		log.Error().Msgf("error fetching commits: %v", err)
+		return nil, err
+	}
+
+	if err := graphQLError(historyResponse.Errors); err != nil {
+		log.Error().Msgf("failed to fetch commits: %v", historyResponse.Errors)
+		return nil, err
+	}
+
+	var target *GitHubGraphQLCommitTarget
+	if r := historyResponse.Data.Repository; r != nil {
+		if r.Ref != nil {
+			target = r.Ref.Target
+		} else {
+			target = r.Object
+		}
+	}
+
+	if target == nil || target.History == nil {
+		return nil, nil
+	}
+
+	var cc []domain.Commit
+	for _, cr := range target.History.Nodes {
+		cc = append(cc, graphQLCommit(cr, repo))
+	}
+
+	commitPage := &CommitPage{
+		Commits:   cc,
+		Cursor:    target.History.PageInfo.EndCursor,
+		MorePages: target.History.PageInfo.HasNextPage,
+	}
+	if perPage > 0 {
+		commitPage.TotalPages = (target.History.TotalCount + perPage - 1) / perPage
+	}
+	return commitPage, nil
+}
+
+// graphQLCommit maps a commit of a GraphQL history, which carries its change statistics but not the files it changed
+func graphQLCommit(cr GitHubGraphQLCommit, repo domain.RepoMetadata) domain.Commit {
+	commit := domain.Commit{
+		CommitID:       cr.Oid,
+		Message:        cr.Message,
+		Author:         cr.Author.Name,
+		AuthorEmail:    cr.Author.Email,
+		Date:           cr.Author.Date,
+		CommitterName:  cr.Committer.Name,
+		CommitterEmail: cr.Committer.Email,
+		CommitterDate:  cr.Committer.Date,
+		URL:            cr.URL,
+		RepositoryName: repo.Name,
+		RepositoryHost: repo.Host,
+		Additions:      cr.Additions,
+		Deletions:      cr.Deletions,
+		TotalChanges:   cr.Additions + cr.Deletions,
+		EnrichedAt:     time.Now(),
+	}
+	if cr.Author.User != nil {
+		commit.AuthorLogin = cr.Author.User.Login
+		commit.AuthorAvatarURL = cr.Author.User.AvatarURL
+	}
+	if cr.Committer.User != nil {
+		commit.CommitterLogin = cr.Committer.User.Login
+	}
+	for _, parent := range cr.Parents.Nodes {
+		commit.ParentSHAs = append(commit.ParentSHAs, parent.Oid)
+	}
+	commit.IsMerge = len(commit.ParentSHAs) > 1
+
+	return commit
+}
+
+// FetchBranches lists the branches of the repository with their head commit, walking the cursors of the branch refs
+func (g *GitHubGraphQLClient) FetchBranches(ctx context.Context, repo domain.RepoMetadata) ([]domain.Branch, error) {
+	owner, name, err := splitRepositoryName(repo.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	var branches []domain.Branch
+	variables := map[string]any{"owner": owner, "name": name, "after": nil}
+	for {
+		var branchesResponse GitHubGraphQLBranchesResponse
+		if err := g.query(ctx, githubBranchesQuery, variables, &branchesResponse); err != nil {
+			log.Error().Msgf("error fetching branches: %v", err)
+			return nil, err
+		}
+
+		if err := graphQLError(branchesResponse.Errors); err != nil {
+			log.Error().Msgf("failed to fetch branches: %v", branchesResponse.Errors)
+			return nil, err
+		}
+
+		r := branchesResponse.Data.Repository
+		if r == nil {
+			return nil, message.ErrRepoMetaDataNotFetched
+		}
+
+		for _, ref := range r.Refs.Nodes {
+			branches = append(branches, domain.Branch{Name: ref.Name, HeadSHA: ref.Target.Oid})
+		}
+
+		if !r.Refs.PageInfo.HasNextPage {
+			return branches, nil
+		}
+		variables["after"] = r.Refs.PageInfo.EndCursor
+	}
+}
+
+// FetchPullRequests lists the pull requests of the repository most recently updated first, paging stops at the
+// first pull request last updated before since
+func (g *GitHubGraphQLClient) FetchPullRequests(ctx context.Context, repo domain.RepoMetadata, since time.Time) ([]domain.PullRequest, error) {
+	owner, name, err := splitRepositoryName(repo.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	var pullRequests []domain.PullRequest
+	variables := map[string]any{"owner": owner, "name": name, "after": nil}
+	for {
+		var pullRequestsResponse GitHubGraphQLPullRequestsResponse
+		if err := g.query(ctx, githubPullRequestsQuery, variables, &pullRequestsResponse); err != nil {
+			log.Error().Msgf("error fetching pull requests: %v", err)
+			return nil, err
+		}
+
+		if err := graphQLError(pullRequestsResponse.Errors); err != nil {
+			log.Error().Msgf("failed to fetch pull requests: %v", pullRequestsResponse.Errors)
+			return nil, err
+		}
+
+		r := pullRequestsResponse.Data.Repository
+		if r == nil {
+			return nil, message.ErrRepoMetaDataNotFetched
+		}
+
+		for _, pr := range r.PullRequests.Nodes {
+			if pr.UpdatedAt.Before(since) {
+				return pullRequests, nil
+			}
+			pullRequests = append(pullRequests, graphQLPullRequest(pr))
+		}
+
+		if !r.PullRequests.PageInfo.HasNextPage {
+			return pullRequests, nil
+		}
+		variables["after"] = r.PullRequests.PageInfo.EndCursor
+	}
+}
+
+// graphQLPullRequest maps a pull request of the GraphQL pull requests listing
+func graphQLPullRequest(pr GitHubGraphQLPullRequest) domain.PullRequest {
+	pullRequest := domain.PullRequest{
+		Number:    pr.Number,
+		Title:     pr.Title,
+		State:     strings.ToLower(pr.State),
+		URL:       pr.URL,
+		BaseRef:   pr.BaseRefName,
+		HeadRef:   pr.HeadRefName,
+		CreatedAt: pr.CreatedAt,
+		UpdatedAt: pr.UpdatedAt,
+	}
+	if pr.Author != nil {
+		pullRequest.Author = pr.Author.Login
+	}
+	if pr.MergedAt != nil {
+		pullRequest.State = domain.PullRequestMerged
+		pullRequest.MergedAt = *pr.MergedAt
+		if pr.MergeCommit != nil {
+			pullRequest.MergeCommitSHA = pr.MergeCommit.Oid
+		}
+	}
+	if pr.ClosedAt != nil {
+		pullRequest.ClosedAt = *pr.ClosedAt
+	}
+	return pullRequest
+}
+
+// FetchPullRequestCommits lists the ids of the commits of the pull request, GitHub lists up to 250 commits
+// of a pull request
+func (g *GitHubGraphQLClient) FetchPullRequestCommits(ctx context.Context, repo domain.RepoMetadata, number int) ([]string, error) {
+	owner, name, err := splitRepositoryName(repo.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	var commitIDs []string
+	variables := map[string]any{"owner": owner, "name": name, "number": number, "after": nil}
+	for {
+		var commitsResponse GitHubGraphQLPullRequestCommitsResponse
+		if err := g.query(ctx, githubPullRequestCommitsQuery, variables, &commitsResponse); err != nil {
+			log.Error().Msgf("error fetching pull request commits: %v", err)
+			return nil, err
+		}
+
+		if err := graphQLError(commitsResponse.Errors); err != nil {
+			log.Error().Msgf("failed to fetch pull request commits: %v", commitsResponse.Errors)
+			return nil, err
+		}
+
+		r := commitsResponse.Data.Repository
+		if r == nil || r.PullRequest == nil {
+			return nil, message.ErrRepoMetaDataNotFetched
+		}
+
+		for _, node := range r.PullRequest.Commits.Nodes {
+			commitIDs = append(commitIDs, node.Commit.Oid)
+		}
+
+		if !r.PullRequest.Commits.PageInfo.HasNextPage {
+			return commitIDs, nil
+		}
+		variables["after"] = r.PullRequest.Commits.PageInfo.EndCursor
+	}
+}
+
+// FetchTags lists the tags of the repository, walking the cursors of the tag refs
+func (g *GitHubGraphQLClient) FetchTags(ctx context.Context, repo domain.RepoMetadata) ([]domain.Tag, error) {
+	owner, name, err := splitRepositoryName(repo.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	var tags []domain.Tag
+	variables := map[string]any{"owner": owner, "name": name, "after": nil}
+	for {
+		var tagsResponse GitHubGraphQLTagsResponse
+		if err := g.query(ctx, githubTagsQuery, variables, &tagsResponse); err != nil {
+			log.Error().Msgf("error fetching tags: %v", err)
+			return nil, err
+		}
+
+		if err := graphQLError(tagsResponse.Errors); err != nil {
+			log.Error().Msgf("failed to fetch tags: %v", tagsResponse.Errors)
+			return nil, err
+		}
+
+		r := tagsResponse.Data.Repository
+		if r == nil {
+			return nil, message.ErrRepoMetaDataNotFetched
+		}
+
+		for _, ref := range r.Refs.Nodes {
+			tag := domain.Tag{Name: ref.Name, TargetSHA: ref.Target.Oid}
+			if ref.Target.Target != nil {
+				tag.TargetSHA = ref.Target.Target.Oid
+			}
+			tags = append(tags, tag)
+		}
+
+		if !r.Refs.PageInfo.HasNextPage {
+			return tags, nil
+		}
+		variables["after"] = r.Refs.PageInfo.EndCursor
+	}
+}
+
+// FetchReleases lists the releases of the repository, walking the cursors of the releases connection
+func (g *GitHubGraphQLClient) FetchReleases(ctx context.Context, repo domain.RepoMetadata) ([]domain.Release, error) {
+	owner, name, err := splitRepositoryName(repo.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	var releases []domain.Release
+	variables := map[string]any{"owner": owner, "name": name, "after": nil}
+	for {
+		var releasesResponse GitHubGraphQLReleasesResponse
+		if err := g.query(ctx, githubReleasesQuery, variables, &releasesResponse); err != nil {
+			log.Error().Msgf("error fetching releases: %v", err)
+			return nil, err
+		}
+
+		if err := graphQLError(releasesResponse.Errors); err != nil {
+			log.Error().Msgf("failed to fetch releases: %v", releasesResponse.Errors)
+			return nil, err
+		}
+
+		r := releasesResponse.Data.Repository
+		if r == nil {
+			return nil, message.ErrRepoMetaDataNotFetched
+		}
+
+		for _, rr := range r.Releases.Nodes {
+			release := domain.Release{
+				TagName:    rr.TagName,
+				Title:      rr.Name,
+				Body:       rr.Description,
+				Draft:      rr.IsDraft,
+				Prerelease: rr.IsPrerelease,
+			}
+			if rr.TagCommit != nil {
+				release.TargetSHA = rr.TagCommit.Oid
+			}
+			if rr.PublishedAt != nil {
+				release.PublishedAt = *rr.PublishedAt
+			}
+			releases = append(releases, release)
+		}
+
+		if !r.Releases.PageInfo.HasNextPage {
+			return releases, nil
+		}
+		variables["after"] = r.Releases.PageInfo.EndCursor
+	}
+}
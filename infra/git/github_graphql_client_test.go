@@ -0,0 +1,231 @@
+package git_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kenmobility/git-api-service/infra/git"
+	"github.com/kenmobility/git-api-service/internal/domain"
+	"github.com/kenmobility/git-api-service/pkg/message"
+	"github.com/stretchr/testify/require"
+)
+
+func newGitHubGraphQLTestServer(t *testing.T) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, http.MethodPost, r.Method)
+		require.Equal(t, "Bearer test_token", r.Header.Get("Authorization"))
+
+		var request git.GraphQLRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&request))
+
+		if request.Variables["name"] == "missing" {
+			w.Write([]byte(`{"data": {"repository": null}, "errors": [{"type": "NOT_FOUND", "message": "Could not resolve to a Repository"}]}`))
+			return
+		}
+
+		if _, ok := request.Variables["first"]; !ok {
+			w.Write([]byte(`{"data": {"repository": {"nameWithOwner": "sample/repo", "url": "https://github.com/sample/repo",
+				"stargazerCount": 10, "forkCount": 2, "primaryLanguage": {"name": "Go"}, "watchers": {"totalCount": 4},
+				"issues": {"totalCount": 1}}, "rateLimit": {"limit": 5000, "remaining": 4999, "cost": 1}}}`))
+			return
+		}
+
+		// two pages of history, the second one is served after the first page's end cursor
+		if request.Variables["after"] == nil {
+			w.Write([]byte(`{"data": {"repository": {"ref": {"target": {"history": {"totalCount": 2,
+				"pageInfo": {"hasNextPage": true, "endCursor": "cursor-1"},
+				"nodes": [{"oid": "abc123", "url": "https://github.com/sample/repo/commit/abc123", "message": "Second commit",
+					"additions": 3, "deletions": 1, "author": {"name": "john", "date": "2024-01-03T10:00:00Z", "user": {"login": "john"}}}]}}}}}}`))
+			return
+		}
+
+		require.Equal(t, "cursor-1", request.Variables["after"])
+		w.Write([]byte(`{"data": {"repository": {"ref": {"target": {"history": {"totalCount": 2,
+			"pageInfo": {"hasNextPage": false, "endCursor": "cursor-2"},
+			"nodes": [{"oid": "def456", "url": "https://github.com/sample/repo/commit/def456", "message": "Initial commit",
+				"author": {"name": "jane", "date": "2024-01-02T10:00:00Z"}}]}}}}}}`))
+	}))
+}
+
+func TestGitHubGraphQLFetchRepoMetadata(t *testing.T) {
+	server := newGitHubGraphQLTestServer(t)
+	defer server.Close()
+
+	gitClient := git.NewGitHubGraphQLClient(server.URL, []string{"test_token"})
+
+	metadata, err := gitClient.FetchRepoMetadata(context.Background(), "sample/repo")
+	require.NoError(t, err)
+	require.Equal(t, "sample/repo", metadata.Name)
+	require.Equal(t, "Go", metadata.Language)
+	require.Equal(t, 10, metadata.StarsCount)
+	require.Equal(t, 4, metadata.WatchersCount)
+	require.Equal(t, 1, metadata.OpenIssuesCount)
+
+	_, err = gitClient.FetchRepoMetadata(context.Background(), "sample/missing")
+	require.Equal(t, message.ErrRepositoryNotFound, err)
+}
+
+func TestGitHubGraphQLTokenRotation(t *testing.T) {
+	var requests []string
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token := r.Header.Get("Authorization")
+		requests = append(requests, token)
+
+		// token-a runs out of budget on its second query
+		if token == "Bearer token-a" && len(requests) > 1 {
+			w.Header().Set("X-Ratelimit-Remaining", "0")
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+
+		remaining := 4000
+		if token == "Bearer token-b" {
+			remaining = 10
+		}
+		w.Write([]byte(fmt.Sprintf(`{"data": {"repository": {"nameWithOwner": "sample/repo"},
+			"rateLimit": {"limit": 5000, "remaining": %d, "cost": 1, "resetAt": "2030-01-01T00:00:00Z"}}}`, remaining)))
+	}))
+	defer server.Close()
+
+	gitClient := git.NewGitHubGraphQLClient(server.URL, []string{"token-a", "token-b"})
+
+	for i := 0; i < 4; i++ {
+		_, err := gitClient.FetchRepoMetadata(context.Background(), "sample/repo")
+		require.NoError(t, err)
+	}
+
+	// both tokens are tried once, then the one with the most budget is used until it is exhausted
+	require.Equal(t, []string{"Bearer token-a", "Bearer token-b", "Bearer token-a", "Bearer token-b", "Bearer token-b"}, requests)
+	require.Equal(t, 10, gitClient.(git.RateLimitReporter).RateLimitStatus().Remaining)
+}
+
+func TestGitHubGraphQLFetchCommitsAfter(t *testing.T) {
+	server := newGitHubGraphQLTestServer(t)
+	defer server.Close()
+
+	gitClient := git.NewGitHubGraphQLClient(server.URL, []string{"test_token"}).(git.CursorCommitFetcher)
+	repo := domain.RepoMetadata{Name: "sample/repo"}
+
+	commitPage, err := gitClient.FetchCommitsAfter(context.Background(), repo, time.Now().AddDate(0, -1, 0), time.Now(), "", "", 1)
+	require.NoError(t, err)
+	require.True(t, commitPage.MorePages)
+	require.Equal(t, "cursor-1", commitPage.Cursor)
+	require.Equal(t, 2, commitPage.TotalPages)
+	require.Len(t, commitPage.Commits, 1)
+	require.Equal(t, "abc123", commitPage.Commits[0].CommitID)
+	require.Equal(t, "john", commitPage.Commits[0].Author)
+	// the change statistics are listed with the history, the commit needs no enrichment
+	require.Equal(t, 3, commitPage.Commits[0].Additions)
+	require.Equal(t, 1, commitPage.Commits[0].Deletions)
+	require.Equal(t, 4, commitPage.Commits[0].TotalChanges)
+	require.False(t, commitPage.Commits[0].EnrichedAt.IsZero())
+
+	commitPage, err = gitClient.FetchCommitsAfter(context.Background(), repo, time.Now().AddDate(0, -1, 0), time.Now(), "", commitPage.Cursor, 1)
+	require.NoError(t, err)
+	require.False(t, commitPage.MorePages)
+	require.Equal(t, "def456", commitPage.Commits[0].CommitID)
+}
+
+func TestGitHubGraphQLFetchCommitsByPage(t *testing.T) {
+	server := newGitHubGraphQLTestServer(t)
+	defer server.Close()
+
+	repo := domain.RepoMetadata{Name: "sample/repo"}
+	since, until := time.Now().AddDate(0, -1, 0), time.Now()
+
+	// page 2 is reached by walking the cursors from the first page
+	commits, morePages, err := git.NewGitHubGraphQLClient(server.URL, []string{"test_token"}).FetchCommits(context.Background(), repo, since, until, "", 2, 1)
+	require.NoError(t, err)
+	require.False(t, morePages)
+	require.Len(t, commits, 1)
+	require.Equal(t, "def456", commits[0].CommitID)
+}
+
+func newGitHubGraphQLBranchTestServer(t *testing.T) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var request git.GraphQLRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&request))
+
+		switch {
+		case strings.Contains(request.Query, "refs(refPrefix: \"refs/heads/\""):
+			w.Write([]byte(`{"data": {"repository": {"refs": {"pageInfo": {"hasNextPage": false},
+				"nodes": [{"name": "main", "target": {"oid": "abc123"}}, {"name": "release/1.0", "target": {"oid": "def456"}}]}}}}`))
+		case strings.Contains(request.Query, "ref(qualifiedName: $branch)"):
+			if request.Variables["branch"] != "refs/heads/release/1.0" {
+				w.Write([]byte(`{"data": {"repository": {"ref": null}}}`))
+				return
+			}
+			w.Write([]byte(`{"data": {"repository": {"ref": {"target": {"history": {"totalCount": 1,
+				"pageInfo": {"hasNextPage": false, "endCursor": "cursor-1"},
+				"nodes": [{"oid": "def456", "message": "Release fix", "author": {"name": "jane", "date": "2024-01-02T10:00:00Z"}}]}}}}}}`))
+		case strings.Contains(request.Query, "pullRequests("):
+			w.Write([]byte(`{"data": {"repository": {"pullRequests": {"pageInfo": {"hasNextPage": true, "endCursor": "cursor-1"},
+				"nodes": [
+					{"number": 2, "title": "Add feature", "state": "MERGED", "baseRefName": "main", "headRefName": "feature",
+						"updatedAt": "2024-01-05T10:00:00Z", "mergedAt": "2024-01-05T10:00:00Z", "closedAt": "2024-01-05T10:00:00Z",
+						"author": {"login": "john"}, "mergeCommit": {"oid": "abc123"}},
+					{"number": 1, "title": "Old fix", "state": "OPEN", "updatedAt": "2023-12-01T10:00:00Z"}]}}}}`))
+		case strings.Contains(request.Query, "pullRequest(number: $number)"):
+			require.EqualValues(t, 2, request.Variables["number"])
+			if request.Variables["after"] == nil {
+				w.Write([]byte(`{"data": {"repository": {"pullRequest": {"commits": {"pageInfo": {"hasNextPage": true, "endCursor": "cursor-1"},
+					"nodes": [{"commit": {"oid": "aaa111"}}]}}}}}`))
+				return
+			}
+			w.Write([]byte(`{"data": {"repository": {"pullRequest": {"commits": {"pageInfo": {"hasNextPage": false},
+				"nodes": [{"commit": {"oid": "bbb222"}}]}}}}}`))
+		default:
+			t.Fatalf("unexpected query %s", request.Query)
+		}
+	}))
+}
+
+func TestGitHubGraphQLFetchBranchCommits(t *testing.T) {
+	server := newGitHubGraphQLBranchTestServer(t)
+	defer server.Close()
+
+	gitClient := git.NewGitHubGraphQLClient(server.URL, []string{"test_token"}).(git.BranchCommitFetcher)
+	repo := domain.RepoMetadata{Name: "sample/repo"}
+
+	branches, err := gitClient.FetchBranches(context.Background(), repo)
+	require.NoError(t, err)
+	require.Equal(t, []domain.Branch{{Name: "main", HeadSHA: "abc123"}, {Name: "release/1.0", HeadSHA: "def456"}}, branches)
+
+	commitPage, err := gitClient.FetchBranchCommits(context.Background(), repo, "release/1.0", time.Time{}, time.Now(), "", 10)
+	require.NoError(t, err)
+	require.False(t, commitPage.MorePages)
+	require.Len(t, commitPage.Commits, 1)
+	require.Equal(t, "def456", commitPage.Commits[0].CommitID)
+
+	_, err = gitClient.FetchBranchCommits(context.Background(), repo, "deleted", time.Time{}, time.Now(), "", 10)
+	require.Error(t, err)
+}
+
+func TestGitHubGraphQLFetchPullRequests(t *testing.T) {
+	server := newGitHubGraphQLBranchTestServer(t)
+	defer server.Close()
+
+	gitClient := git.NewGitHubGraphQLClient(server.URL, []string{"test_token"}).(git.PullRequestFetcher)
+	repo := domain.RepoMetadata{Name: "sample/repo"}
+
+	// paging stops at the first pull request updated before since
+	pullRequests, err := gitClient.FetchPullRequests(context.Background(), repo, time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+	require.Len(t, pullRequests, 1)
+	require.Equal(t, 2, pullRequests[0].Number)
+	require.Equal(t, domain.PullRequestMerged, pullRequests[0].State)
+	require.Equal(t, "john", pullRequests[0].Author)
+	require.Equal(t, "abc123", pullRequests[0].MergeCommitSHA)
+	require.Equal(t, "feature", pullRequests[0].HeadRef)
+
+	commitIDs, err := gitClient.FetchPullRequestCommits(context.Background(), repo, 2)
+	require.NoError(t, err)
+	require.Equal(t, []string{"aaa111", "bbb222"}, commitIDs)
+}
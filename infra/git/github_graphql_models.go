@@ -0,0 +1,233 @@
+package git
+
+import "time"
+
+type (
+	GraphQLRequest struct {
+		Query     string         `json:"query"`
+		Variables map[string]any `json:"variables"`
+	}
+
+	GraphQLError struct {
+		Type    string `json:"type"`
+		Message string `json:"message"`
+	}
+
+	GraphQLRateLimit struct {
+		Limit     int       `json:"limit"`
+		Remaining int       `json:"remaining"`
+		Cost      int       `json:"cost"`
+		ResetAt   time.Time `json:"resetAt"`
+	}
+
+	GraphQLRateLimitResponse struct {
+		Data struct {
+			RateLimit GraphQLRateLimit `json:"rateLimit"`
+		} `json:"data"`
+	}
+)
+
+type (
+	GitHubGraphQLRepoResponse struct {
+		Data struct {
+			Repository *struct {
+				NameWithOwner   string `json:"nameWithOwner"`
+				Description     string `json:"description"`
+				URL             string `json:"url"`
+				StargazerCount  int    `json:"stargazerCount"`
+				ForkCount       int    `json:"forkCount"`
+				PrimaryLanguage *struct {
+					Name string `json:"name"`
+				} `json:"primaryLanguage"`
+				Watchers struct {
+					TotalCount int `json:"totalCount"`
+				} `json:"watchers"`
+				Issues struct {
+					TotalCount int `json:"totalCount"`
+				} `json:"issues"`
+				DefaultBranchRef *struct {
+					Name string `json:"name"`
+				} `json:"defaultBranchRef"`
+				IsArchived bool `json:"isArchived"`
+			} `json:"repository"`
+			RateLimit GraphQLRateLimit `json:"rateLimit"`
+		} `json:"data"`
+		Errors []GraphQLError `json:"errors"`
+	}
+)
+
+type (
+	GitHubGraphQLHistoryResponse struct {
+		Data struct {
+			Repository *struct {
+				Ref *struct {
+					Target *GitHubGraphQLCommitTarget `json:"target"`
+				} `json:"ref"`
+				Object *GitHubGraphQLCommitTarget `json:"object"`
+			} `json:"repository"`
+			RateLimit GraphQLRateLimit `json:"rateLimit"`
+		} `json:"data"`
+		Errors []GraphQLError `json:"errors"`
+	}
+
+	GitHubGraphQLCommitTarget struct {
+		History *struct {
+			TotalCount int `json:"totalCount"`
+			PageInfo   struct {
+				HasNextPage bool   `json:"hasNextPage"`
+				EndCursor   string `json:"endCursor"`
+			} `json:"pageInfo"`
+			Nodes []GitHubGraphQLCommit `json:"nodes"`
+		} `json:"history"`
+	}
+
+	GitHubGraphQLCommit struct {
+		Oid           string              `json:"oid"`
+		URL           string              `json:"url"`
+		Message       string              `json:"message"`
+		AuthoredDate  time.Time           `json:"authoredDate"`
+		CommittedDate time.Time           `json:"committedDate"`
+		Additions     int                 `json:"additions"`
+		Deletions     int                 `json:"deletions"`
+		Author        GitHubGraphQLPerson `json:"author"`
+		Committer     GitHubGraphQLPerson `json:"committer"`
+		Parents       struct {
+			Nodes []struct {
+				Oid string `json:"oid"`
+			} `json:"nodes"`
+		} `json:"parents"`
+	}
+
+	GitHubGraphQLPerson struct {
+		Name  string    `json:"name"`
+		Email string    `json:"email"`
+		Date  time.Time `json:"date"`
+		User  *struct {
+			Login     string `json:"login"`
+			AvatarURL string `json:"avatarUrl"`
+		} `json:"user"`
+	}
+)
+
+type (
+	GraphQLPageInfo struct {
+		HasNextPage bool   `json:"hasNextPage"`
+		EndCursor   string `json:"endCursor"`
+	}
+
+	GitHubGraphQLTagsResponse struct {
+		Data struct {
+			Repository *struct {
+				Refs struct {
+					PageInfo GraphQLPageInfo `json:"pageInfo"`
+					Nodes    []struct {
+						Name   string `json:"name"`
+						Target struct {
+							Oid string `json:"oid"`
+							// Target is the commit of an annotated tag
+							Target *struct {
+								Oid string `json:"oid"`
+							} `json:"target"`
+						} `json:"target"`
+					} `json:"nodes"`
+				} `json:"refs"`
+			} `json:"repository"`
+			RateLimit GraphQLRateLimit `json:"rateLimit"`
+		} `json:"data"`
+		Errors []GraphQLError `json:"errors"`
+	}
+
+	GitHubGraphQLReleasesResponse struct {
+		Data struct {
+			Repository *struct {
+				Releases struct {
+					PageInfo GraphQLPageInfo `json:"pageInfo"`
+					Nodes    []struct {
+						TagName      string     `json:"tagName"`
+						Name         string     `json:"name"`
+						Description  string     `json:"description"`
+						IsDraft      bool       `json:"isDraft"`
+						IsPrerelease bool       `json:"isPrerelease"`
+						PublishedAt  *time.Time `json:"publishedAt"`
+						TagCommit    *struct {
+							Oid string `json:"oid"`
+						} `json:"tagCommit"`
+					} `json:"nodes"`
+				} `json:"releases"`
+			} `json:"repository"`
+			RateLimit GraphQLRateLimit `json:"rateLimit"`
+		} `json:"data"`
+		Errors []GraphQLError `json:"errors"`
+	}
+)
+
+type (
+	GitHubGraphQLBranchesResponse struct {
+		Data struct {
+			Repository *struct {
+				Refs struct {
+					PageInfo GraphQLPageInfo `json:"pageInfo"`
+					Nodes    []struct {
+						Name   string `json:"name"`
+						Target struct {
+							Oid string `json:"oid"`
+						} `json:"target"`
+					} `json:"nodes"`
+				} `json:"refs"`
+			} `json:"repository"`
+			RateLimit GraphQLRateLimit `json:"rateLimit"`
+		} `json:"data"`
+		Errors []GraphQLError `json:"errors"`
+	}
+
+	GitHubGraphQLPullRequestsResponse struct {
+		Data struct {
+			Repository *struct {
+				PullRequests struct {
+					PageInfo GraphQLPageInfo            `json:"pageInfo"`
+					Nodes    []GitHubGraphQLPullRequest `json:"nodes"`
+				} `json:"pullRequests"`
+			} `json:"repository"`
+			RateLimit GraphQLRateLimit `json:"rateLimit"`
+		} `json:"data"`
+		Errors []GraphQLError `json:"errors"`
+	}
+
+	GitHubGraphQLPullRequest struct {
+		Number      int        `json:"number"`
+		Title       string     `json:"title"`
+		State       string     `json:"state"`
+		URL         string     `json:"url"`
+		BaseRefName string     `json:"baseRefName"`
+		HeadRefName string     `json:"headRefName"`
+		CreatedAt   time.Time  `json:"createdAt"`
+		UpdatedAt   time.Time  `json:"updatedAt"`
+		MergedAt    *time.Time `json:"mergedAt"`
+		ClosedAt    *time.Time `json:"closedAt"`
+		Author      *struct {
+			Login string `json:"login"`
+		} `json:"author"`
+		MergeCommit *struct {
+			Oid string `json:"oid"`
+		} `json:"mergeCommit"`
+	}
+
+	GitHubGraphQLPullRequestCommitsResponse struct {
+		Data struct {
+			Repository *struct {
+				PullRequest *struct {
+					Commits struct {
+						PageInfo GraphQLPageInfo `json:"pageInfo"`
+						Nodes    []struct {
+							Commit struct {
+								Oid string `json:"oid"`
+							} `json:"commit"`
+						} `json:"nodes"`
+					} `json:"commits"`
+				} `json:"pullRequest"`
+			} `json:"repository"`
+			RateLimit GraphQLRateLimit `json:"rateLimit"`
+		} `json:"data"`
+		Errors []GraphQLError `json:"errors"`
+	}
+)
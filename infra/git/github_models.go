@@ -9,12 +9,43 @@ type (
 		Commit  Commit `json:"commit"`
 		URL     string `json:"url"`
 		HtmlURL string `json:"html_url"`
+		// Author and Committer are the host accounts matching the git identities, nil when none does
+		Author    *GitHubUser          `json:"author"`
+		Committer *GitHubUser          `json:"committer"`
+		Parents   []GitHubCommitParent `json:"parents"`
+		// Stats and Files are only returned by the single commit endpoint
+		Stats *GitHubCommitStats `json:"stats"`
+		Files []GitHubCommitFile `json:"files"`
+	}
+
+	GitHubCommitStats struct {
+		Additions int `json:"additions"`
+		Deletions int `json:"deletions"`
+		Total     int `json:"total"`
+	}
+
+	GitHubCommitFile struct {
+		Filename         string `json:"filename"`
+		Status           string `json:"status"`
+		Additions        int    `json:"additions"`
+		Deletions        int    `json:"deletions"`
+		PreviousFilename string `json:"previous_filename"`
 	}
 
 	Commit struct {
-		Author  Author `json:"author"`
-		Message string `json:"message"`
-		URL     string `json:"url"`
+		Author    Author `json:"author"`
+		Committer Author `json:"committer"`
+		Message   string `json:"message"`
+		URL       string `json:"url"`
+	}
+
+	GitHubUser struct {
+		Login     string `json:"login"`
+		AvatarURL string `json:"avatar_url"`
+	}
+
+	GitHubCommitParent struct {
+		SHA string `json:"sha"`
 	}
 
 	Author struct {
@@ -41,5 +72,67 @@ type (
 		Language        string `json:"language"`
 		ForksCount      int    `json:"forks_count"`
 		OpenIssues      int    `json:"open_issues"`
+		DefaultBranch   string `json:"default_branch"`
+		Archived        bool   `json:"archived"`
+	}
+
+	GitHubBranchResponse struct {
+		Name   string `json:"name"`
+		Commit struct {
+			SHA string `json:"sha"`
+		} `json:"commit"`
+	}
+
+	// GitHubTagResponse is a tag of the tags listing, the commit of an annotated tag is already peeled
+	GitHubTagResponse struct {
+		Name   string `json:"name"`
+		Commit struct {
+			SHA string `json:"sha"`
+		} `json:"commit"`
+	}
+
+	GitHubPullRequestResponse struct {
+		Number  int         `json:"number"`
+		Title   string      `json:"title"`
+		State   string      `json:"state"`
+		HtmlURL string      `json:"html_url"`
+		User    *GitHubUser `json:"user"`
+		Base    struct {
+			Ref string `json:"ref"`
+		} `json:"base"`
+		Head struct {
+			Ref string `json:"ref"`
+		} `json:"head"`
+		// MergeCommitSHA is the test merge commit of an unmerged pull request
+		MergeCommitSHA string     `json:"merge_commit_sha"`
+		CreatedAt      time.Time  `json:"created_at"`
+		UpdatedAt      time.Time  `json:"updated_at"`
+		MergedAt       *time.Time `json:"merged_at"`
+		ClosedAt       *time.Time `json:"closed_at"`
+	}
+
+	GitHubReleaseResponse struct {
+		TagName string `json:"tag_name"`
+		// TargetCommitish is the branch or commit the tag is created from when it does not exist yet
+		TargetCommitish string     `json:"target_commitish"`
+		Name            string     `json:"name"`
+		Body            string     `json:"body"`
+		Draft           bool       `json:"draft"`
+		Prerelease      bool       `json:"prerelease"`
+		PublishedAt     *time.Time `json:"published_at"`
+	}
+)
+
+type (
+	GitHubAppInstallationResponse struct {
+		ID      int64 `json:"id"`
+		Account struct {
+			Login string `json:"login"`
+		} `json:"account"`
+	}
+
+	GitHubAppInstallationTokenResponse struct {
+		Token     string    `json:"token"`
+		ExpiresAt time.Time `json:"expires_at"`
 	}
 )
@@ -0,0 +1,111 @@
+package git
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/kenmobility/git-api-service/internal/domain"
+)
+
+// GitHub webhook event names
+const (
+	GitHubPingEvent = "ping"
+	GitHubPushEvent = "push"
+)
+
+// gitHubPushCommitLimit is the number of commits a push event lists at most
+const gitHubPushCommitLimit = 20
+
+// VerifyGitHubSignature reports whether the X-Hub-Signature-256 header of a webhook delivery is the HMAC-SHA256 of its
+// body keyed with the webhook secret
+func VerifyGitHubSignature(secret string, signature string, body []byte) bool {
+	hexDigest, ok := strings.CutPrefix(signature, "sha256=")
+	if !ok {
+		return false
+	}
+
+	digest, err := hex.DecodeString(hexDigest)
+	if err != nil {
+		return false
+	}
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return hmac.Equal(digest, mac.Sum(nil))
+}
+
+// ParseGitHubPushEvent parses the payload of a push event, tag pushes have no branch
+func ParseGitHubPushEvent(body []byte, host string) (*domain.PushEvent, error) {
+	var payload GitHubPushEventPayload
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return nil, err
+	}
+
+	event := &domain.PushEvent{
+		RepositoryName: payload.Repository.FullName,
+		Before:         payload.Before,
+		Deleted:        payload.Deleted,
+		Forced:         payload.Forced,
+		Truncated:      len(payload.Commits) >= gitHubPushCommitLimit,
+	}
+	event.Branch, _ = strings.CutPrefix(payload.Ref, "refs/heads/")
+	if event.Branch == payload.Ref {
+		event.Branch = ""
+	}
+
+	for _, pc := range payload.Commits {
+		// the payload has no parents of the commits, they are filled in when the commit is enriched
+		event.Commits = append(event.Commits, domain.Commit{
+			CommitID:       pc.ID,
+			Message:        pc.Message,
+			Author:         pc.Author.Name,
+			AuthorEmail:    pc.Author.Email,
+			AuthorLogin:    pc.Author.Username,
+			Date:           pc.Timestamp,
+			CommitterName:  pc.Committer.Name,
+			CommitterEmail: pc.Committer.Email,
+			CommitterLogin: pc.Committer.Username,
+			CommitterDate:  pc.Timestamp,
+			URL:            pc.URL,
+			RepositoryName: payload.Repository.FullName,
+			RepositoryHost: host,
+		})
+	}
+
+	return event, nil
+}
+
+type (
+	GitHubPushEventPayload struct {
+		Ref     string `json:"ref"`
+		Before  string `json:"before"`
+		After   string `json:"after"`
+		Deleted bool   `json:"deleted"`
+		Forced  bool   `json:"forced"`
+		// Commits lists up to 20 of the pushed commits, oldest first
+		Commits    []GitHubPushCommit `json:"commits"`
+		Repository struct {
+			FullName string `json:"full_name"`
+		} `json:"repository"`
+	}
+
+	GitHubPushCommit struct {
+		ID        string           `json:"id"`
+		Message   string           `json:"message"`
+		Timestamp time.Time        `json:"timestamp"`
+		URL       string           `json:"url"`
+		Distinct  bool             `json:"distinct"`
+		Author    GitHubPushPerson `json:"author"`
+		Committer GitHubPushPerson `json:"committer"`
+	}
+
+	GitHubPushPerson struct {
+		Name     string `json:"name"`
+		Email    string `json:"email"`
+		Username string `json:"username"`
+	}
+)
@@ -0,0 +1,68 @@
+package git_test
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"testing"
+	"time"
+
+	"github.com/kenmobility/git-api-service/infra/git"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVerifyGitHubSignature(t *testing.T) {
+	body := []byte(`{"zen": "Keep it logically awesome."}`)
+
+	mac := hmac.New(sha256.New, []byte("secret"))
+	mac.Write(body)
+	signature := "sha256=" + hex.EncodeToString(mac.Sum(nil))
+
+	require.True(t, git.VerifyGitHubSignature("secret", signature, body))
+	require.False(t, git.VerifyGitHubSignature("other-secret", signature, body))
+	require.False(t, git.VerifyGitHubSignature("secret", signature, []byte(`{}`)))
+	require.False(t, git.VerifyGitHubSignature("secret", hex.EncodeToString(mac.Sum(nil)), body))
+	require.False(t, git.VerifyGitHubSignature("secret", "sha256=not-hex", body))
+	require.False(t, git.VerifyGitHubSignature("secret", "", body))
+}
+
+func TestParseGitHubPushEvent(t *testing.T) {
+	body := []byte(`{
+		"ref": "refs/heads/main",
+		"before": "def456",
+		"deleted": false,
+		"forced": true,
+		"repository": {"full_name": "sample/repo"},
+		"commits": [{
+			"id": "abc123",
+			"message": "Fix bug",
+			"timestamp": "2024-03-01T10:00:00Z",
+			"url": "https://github.com/sample/repo/commit/abc123",
+			"author": {"name": "Jane Doe", "email": "jane@example.com", "username": "jane"},
+			"committer": {"name": "GitHub", "email": "noreply@github.com", "username": "web-flow"}
+		}]
+	}`)
+
+	push, err := git.ParseGitHubPushEvent(body, "github.com")
+	require.NoError(t, err)
+	require.Equal(t, "sample/repo", push.RepositoryName)
+	require.Equal(t, "main", push.Branch)
+	require.Equal(t, "def456", push.Before)
+	require.False(t, push.Deleted)
+	require.True(t, push.Forced)
+	require.False(t, push.Truncated)
+	require.Len(t, push.Commits, 1)
+
+	commit := push.Commits[0]
+	require.Equal(t, "abc123", commit.CommitID)
+	require.Equal(t, "Jane Doe", commit.Author)
+	require.Equal(t, "jane", commit.AuthorLogin)
+	require.Equal(t, "web-flow", commit.CommitterLogin)
+	require.Equal(t, "github.com", commit.RepositoryHost)
+	require.True(t, commit.Date.Equal(time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)))
+
+	// tag pushes have no branch
+	push, err = git.ParseGitHubPushEvent([]byte(`{"ref": "refs/tags/v1.0.0", "repository": {"full_name": "sample/repo"}}`), "github.com")
+	require.NoError(t, err)
+	require.Empty(t, push.Branch)
+}
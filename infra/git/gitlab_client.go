@@ -0,0 +1,281 @@
+package git
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/kenmobility/git-api-service/internal/domain"
+	"github.com/kenmobility/git-api-service/pkg/client"
+	"github.com/kenmobility/git-api-service/pkg/message"
+	"github.com/rs/zerolog/log"
+)
+
+type GitLabClient struct {
+	baseURL string
+	token   string
+	client  *client.RestClient
+}
+
+func (g *GitLabClient) getHeaders() map[string]string {
+	if len(g.token) == 0 {
+		return map[string]string{}
+	}
+	return map[string]string{
+		"Content-Type":  "application/json",
+		"PRIVATE-TOKEN": g.token,
+	}
+}
+
+func NewGitLabClient(baseUrl string, token string) GitManagerClient {
+	client := client.NewRestClient()
+
+	gc := GitLabClient{
+		baseURL: baseUrl,
+		token:   token,
+		client:  client,
+	}
+	return &gc
+}
+
+// WithToken returns a client authenticating with the token
+func (g *GitLabClient) WithToken(token string) GitManagerClient {
+	return NewGitLabClient(g.baseURL, token)
+}
+
+// projectEndpoint builds the project endpoint, GitLab addresses projects by their url-encoded path
+func (g *GitLabClient) projectEndpoint(repositoryName string) string {
+	return fmt.Sprintf("%s/projects/%s", g.baseURL, url.PathEscape(repositoryName))
+}
+
+func (g *GitLabClient) FetchRepoMetadata(ctx context.Context, repositoryName string) (*domain.RepoMetadata, error) {
+	resp, err := g.client.Get(ctx, g.projectEndpoint(repositoryName), client.WithHeaders(g.getHeaders()))
+	if err != nil {
+		return nil, requestError(err)
+	}
+
+	if resp.StatusCode == http.StatusTooManyRequests {
+		log.Error().Msgf("failed to fetch gitlab project; status code: %v, body: %v", resp.StatusCode, resp.Body)
+		return nil, message.ErrRateLimitExceeded
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		log.Error().Msgf("failed to fetch gitlab project; status code: %v, body: %v", resp.StatusCode, resp.Body)
+		return nil, repoMetadataError(resp.StatusCode)
+	}
+
+	var project GitLabProjectResponse
+
+	if err := json.Unmarshal([]byte(resp.Body), &project); err != nil {
+		log.Error().Msgf("marshal error, [%v]", err)
+		return nil, errors.New("could not unmarshal gitlab project response")
+	}
+
+	repoMetadata := &domain.RepoMetadata{
+		Name:            project.PathWithNamespace,
+		Description:     project.Description,
+		URL:             project.WebURL,
+		Language:        g.fetchPrimaryLanguage(ctx, repositoryName),
+		ForksCount:      project.ForksCount,
+		StarsCount:      project.StarCount,
+		OpenIssuesCount: project.OpenIssuesCount,
+		DefaultBranch:   project.DefaultBranch,
+		Status:          repoStatus(project.Archived),
+	}
+
+	return repoMetadata, nil
+}
+
+// fetchPrimaryLanguage returns the language with the highest share in the project, GitLab does not
+// include it on the project resource itself
+func (g *GitLabClient) fetchPrimaryLanguage(ctx context.Context, repositoryName string) string {
+	resp, err := g.client.Get(ctx, g.projectEndpoint(repositoryName)+"/languages", client.WithHeaders(g.getHeaders()))
+	if err != nil || resp.StatusCode != http.StatusOK {
+		return ""
+	}
+
+	var languages map[string]float64
+	if err := json.Unmarshal([]byte(resp.Body), &languages); err != nil {
+		return ""
+	}
+
+	var primary string
+	var share float64
+	for language, percentage := range languages {
+		if percentage > share {
+			primary, share = language, percentage
+		}
+	}
+	return primary
+}
+
+func (g *GitLabClient) FetchCommits(ctx context.Context, repo domain.RepoMetadata, since time.Time, until time.Time, lastFetchedCommit string, page, perPage int) ([]domain.Commit, bool, error) {
+	queryParams := map[string]string{
+		"per_page": strconv.Itoa(perPage),
+		"page":     strconv.Itoa(page),
+	}
+
+	if lastFetchedCommit != "" {
+		queryParams["ref_name"] = lastFetchedCommit
+	} else {
+		queryParams["since"] = since.Format(time.RFC3339)
+		queryParams["until"] = until.Format(time.RFC3339)
+	}
+
+	response, err := g.client.Get(ctx, g.projectEndpoint(repo.Name)+"/repository/commits", client.WithQuery(queryParams), client.WithHeaders(g.getHeaders()))
+	if err != nil {
+		log.Error().Msgf("error fetching gitlab commits: %v", err)
+		return nil, false, requestError(err)
+	}
+
+	if response.StatusCode == http.StatusTooManyRequests {
+		log.Error().Msgf("failed to fetch gitlab commits; status code: %v, body: %v", response.StatusCode, response.Body)
+		return nil, false, message.ErrRateLimitExceeded
+	}
+
+	if response.StatusCode != http.StatusOK {
+		log.Error().Msgf("failed to fetch gitlab commits; status code: %v, body: %v", response.StatusCode, response.Body)
+		return nil, false, fmt.Errorf("failed to fetch commits; status code: %v, body: %v", response.StatusCode, response.Body)
+	}
+
+	var commitRes []GitLabCommitResponse
+
+	if err := json.Unmarshal([]byte(response.Body), &commitRes); err != nil {
+		log.Err(err).Msgf("marshal error, [%v]", err)
+		return nil, false, errors.New("could not unmarshal gitlab commits response")
+	}
+
+	var cc []domain.Commit
+	for _, cr := range commitRes {
+		commit := domain.Commit{
+			CommitID:       cr.ID,
+			Message:        cr.Message,
+			Author:         cr.AuthorName,
+			AuthorEmail:    cr.AuthorEmail,
+			Date:           cr.AuthoredDate,
+			CommitterName:  cr.CommitterName,
+			CommitterEmail: cr.CommitterEmail,
+			CommitterDate:  cr.CommittedDate,
+			ParentSHAs:     cr.ParentIDs,
+			IsMerge:        len(cr.ParentIDs) > 1,
+			URL:            cr.WebURL,
+			RepositoryName: repo.Name,
+			RepositoryHost: repo.Host,
+		}
+
+		cc = append(cc, commit)
+	}
+
+	return cc, g.hasNextPage(response), nil
+}
+
+// FetchTags lists the tags of the project with the commit they point to
+func (g *GitLabClient) FetchTags(ctx context.Context, repo domain.RepoMetadata) ([]domain.Tag, error) {
+	var tags []domain.Tag
+
+	err := g.fetchListing(ctx, g.projectEndpoint(repo.Name)+"/repository/tags", "tags", func(body string) error {
+		var tagRes []GitLabTagResponse
+		if err := json.Unmarshal([]byte(body), &tagRes); err != nil {
+			return err
+		}
+
+		for _, tr := range tagRes {
+			tags = append(tags, domain.Tag{Name: tr.Name, TargetSHA: tr.Commit.ID})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return tags, nil
+}
+
+// FetchReleases lists the releases of the project. GitLab has no draft releases, an upcoming release
+// is listed as a prerelease until its release date
+func (g *GitLabClient) FetchReleases(ctx context.Context, repo domain.RepoMetadata) ([]domain.Release, error) {
+	var releases []domain.Release
+
+	err := g.fetchListing(ctx, g.projectEndpoint(repo.Name)+"/releases", "releases", func(body string) error {
+		var releaseRes []GitLabReleaseResponse
+		if err := json.Unmarshal([]byte(body), &releaseRes); err != nil {
+			return err
+		}
+
+		for _, rr := range releaseRes {
+			release := domain.Release{
+				TagName:    rr.TagName,
+				TargetSHA:  rr.Commit.ID,
+				Title:      rr.Name,
+				Body:       rr.Description,
+				Prerelease: rr.UpcomingRelease,
+			}
+			if rr.ReleasedAt != nil {
+				release.PublishedAt = *rr.ReleasedAt
+			}
+			releases = append(releases, release)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return releases, nil
+}
+
+// fetchListing fetches every page of the listing at the endpoint and hands the body of each page to decode
+func (g *GitLabClient) fetchListing(ctx context.Context, endpoint string, resource string, decode func(body string) error) error {
+	for page := 1; ; page++ {
+		queryParams := map[string]string{
+			"per_page": "100",
+			"page":     strconv.Itoa(page),
+		}
+
+		response, err := g.client.Get(ctx, endpoint, client.WithQuery(queryParams), client.WithHeaders(g.getHeaders()))
+		if err != nil {
+			log.Error().Msgf("error fetching gitlab %s: %v", resource, err)
+			return requestError(err)
+		}
+
+		if response.StatusCode == http.StatusTooManyRequests {
+			log.Error().Msgf("failed to fetch gitlab %s; status code: %v, body: %v", resource, response.StatusCode, response.Body)
+			return message.ErrRateLimitExceeded
+		}
+
+		if response.StatusCode != http.StatusOK {
+			log.Error().Msgf("failed to fetch gitlab %s; status code: %v, body: %v", resource, response.StatusCode, response.Body)
+			return fmt.Errorf("failed to fetch %s; status code: %v, body: %v", resource, response.StatusCode, response.Body)
+		}
+
+		if err := decode(response.Body); err != nil {
+			log.Err(err).Msgf("marshal error, [%v]", err)
+			return fmt.Errorf("could not unmarshal gitlab %s response", resource)
+		}
+
+		if !g.hasNextPage(response) {
+			return nil
+		}
+	}
+}
+
+// hasNextPage checks the X-Next-Page header used by offset pagination, falling back to
+// the 'next' link GitLab returns for keyset paginated responses
+func (g *GitLabClient) hasNextPage(resp *client.Response) bool {
+	nextPage := resp.Headers["X-Next-Page"]
+	if len(nextPage) > 0 && nextPage[0] != "" {
+		return true
+	}
+
+	linkHeader := resp.Headers["Link"]
+	if len(linkHeader) > 0 {
+		_, hasNext := parseLinkHeader(linkHeader[0])["next"]
+		return hasNext
+	}
+	return false
+}
@@ -0,0 +1,80 @@
+package git_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/kenmobility/git-api-service/infra/git"
+	"github.com/kenmobility/git-api-service/internal/domain"
+	"github.com/kenmobility/git-api-service/pkg/message"
+	"github.com/stretchr/testify/require"
+)
+
+func newGitLabTestServer(t *testing.T) *httptest.Server {
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("/projects/", func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "test_token", r.Header.Get("PRIVATE-TOKEN"))
+
+		switch r.URL.EscapedPath() {
+		case "/projects/sample%2Frepo":
+			w.Write([]byte(`{"id": 1, "path_with_namespace": "sample/repo", "description": "A sample repository",
+				"web_url": "https://gitlab.com/sample/repo", "star_count": 10, "forks_count": 2, "open_issues_count": 3}`))
+		case "/projects/sample%2Frepo/languages":
+			w.Write([]byte(`{"Go": 80.5, "Shell": 19.5}`))
+		case "/projects/sample%2Frepo/repository/commits":
+			require.Equal(t, "2", r.URL.Query().Get("per_page"))
+			if r.URL.Query().Get("page") == "1" {
+				w.Header().Set("X-Next-Page", "2")
+			} else {
+				w.Header().Set("X-Next-Page", "")
+			}
+			w.Write([]byte(`[{"id": "abc123", "message": "Initial commit", "author_name": "john",
+				"authored_date": "2024-01-02T15:04:05Z", "web_url": "https://gitlab.com/sample/repo/-/commit/abc123"}]`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+
+	return httptest.NewServer(mux)
+}
+
+func TestGitLabFetchRepoMetadata(t *testing.T) {
+	server := newGitLabTestServer(t)
+	defer server.Close()
+
+	gitClient := git.NewGitLabClient(server.URL, "test_token")
+
+	metadata, err := gitClient.FetchRepoMetadata(context.Background(), "sample/repo")
+	require.NoError(t, err)
+	require.Equal(t, "sample/repo", metadata.Name)
+	require.Equal(t, "Go", metadata.Language)
+	require.Equal(t, 10, metadata.StarsCount)
+	require.Equal(t, 3, metadata.OpenIssuesCount)
+
+	_, err = gitClient.FetchRepoMetadata(context.Background(), "unknown/repo")
+	require.Equal(t, message.ErrRepositoryNotFound, err)
+}
+
+func TestGitLabFetchCommits(t *testing.T) {
+	server := newGitLabTestServer(t)
+	defer server.Close()
+
+	gitClient := git.NewGitLabClient(server.URL, "test_token")
+	repo := domain.RepoMetadata{Name: "sample/repo"}
+
+	commits, morePages, err := gitClient.FetchCommits(context.Background(), repo, time.Now().AddDate(0, -1, 0), time.Now(), "", 1, 2)
+	require.NoError(t, err)
+	require.True(t, morePages)
+	require.Len(t, commits, 1)
+	require.Equal(t, "abc123", commits[0].CommitID)
+	require.Equal(t, "john", commits[0].Author)
+	require.Equal(t, "sample/repo", commits[0].RepositoryName)
+
+	_, morePages, err = gitClient.FetchCommits(context.Background(), repo, time.Now().AddDate(0, -1, 0), time.Now(), "", 2, 2)
+	require.NoError(t, err)
+	require.False(t, morePages)
+}
@@ -0,0 +1,58 @@
+package git
+
+import "time"
+
+type (
+	GitLabCommitResponse struct {
+		ID             string    `json:"id"`
+		ShortID        string    `json:"short_id"`
+		Title          string    `json:"title"`
+		Message        string    `json:"message"`
+		AuthorName     string    `json:"author_name"`
+		AuthorEmail    string    `json:"author_email"`
+		AuthoredDate   time.Time `json:"authored_date"`
+		CommitterName  string    `json:"committer_name"`
+		CommitterEmail string    `json:"committer_email"`
+		CommittedDate  time.Time `json:"committed_date"`
+		ParentIDs      []string  `json:"parent_ids"`
+		WebURL         string    `json:"web_url"`
+	}
+)
+
+type (
+	GitLabProjectResponse struct {
+		ID                int    `json:"id"`
+		Name              string `json:"name"`
+		PathWithNamespace string `json:"path_with_namespace"`
+		Description       string `json:"description"`
+		WebURL            string `json:"web_url"`
+		DefaultBranch     string `json:"default_branch"`
+		StarCount         int    `json:"star_count"`
+		ForksCount        int    `json:"forks_count"`
+		OpenIssuesCount   int    `json:"open_issues_count"`
+		Archived          bool   `json:"archived"`
+	}
+)
+
+type (
+	GitLabTagResponse struct {
+		Name string `json:"name"`
+		// Target is the id of the tag object of an annotated tag, the commit is served apart
+		Target string `json:"target"`
+		Commit struct {
+			ID string `json:"id"`
+		} `json:"commit"`
+	}
+
+	GitLabReleaseResponse struct {
+		TagName     string     `json:"tag_name"`
+		Name        string     `json:"name"`
+		Description string     `json:"description"`
+		ReleasedAt  *time.Time `json:"released_at"`
+		// UpcomingRelease is set for releases whose release date is in the future
+		UpcomingRelease bool `json:"upcoming_release"`
+		Commit          struct {
+			ID string `json:"id"`
+		} `json:"commit"`
+	}
+)
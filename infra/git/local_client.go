@@ -0,0 +1,298 @@
+package git
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/kenmobility/git-api-service/internal/domain"
+	"github.com/kenmobility/git-api-service/pkg/message"
+	"github.com/rs/zerolog/log"
+)
+
+// LocalRepositoryScheme prefixes repository names that are read from a path on disk
+const LocalRepositoryScheme = "file://"
+
+const (
+	// git log record and field separators, neither can appear in commit metadata
+	logRecordSeparator = "\x1e"
+	logFieldSeparator  = "\x1f"
+
+	logFormat = "%H" + logFieldSeparator + "%an" + logFieldSeparator + "%ae" + logFieldSeparator + "%aI" + logFieldSeparator +
+		"%cn" + logFieldSeparator + "%ce" + logFieldSeparator + "%cI" + logFieldSeparator + "%P" + logFieldSeparator + "%B" + logRecordSeparator
+)
+
+// LocalGitClient reads repository metadata and commit history from a bare or working-tree
+// git repository on disk using the git binary, without any network access
+type LocalGitClient struct {
+	gitBinary string
+	// root is the directory the repositories must be under, after their symlinks are resolved
+	root string
+}
+
+// NewLocalGitClient returns a client reading the repositories under the root directory, paths outside of it are rejected
+func NewLocalGitClient(root string) GitManagerClient {
+	return &LocalGitClient{gitBinary: "git", root: resolvePath(root)}
+}
+
+// resolvePath returns the absolute path with its symlinks resolved, or only cleaned when it can not be resolved
+func resolvePath(path string) string {
+	path, err := filepath.Abs(path)
+	if err != nil {
+		return filepath.Clean(path)
+	}
+	if resolved, err := filepath.EvalSymlinks(path); err == nil {
+		return resolved
+	}
+	return path
+}
+
+// IsLocalRepository reports whether the repository name points to a repository on disk, eg file:///srv/mirrors/foo.git
+func IsLocalRepository(repositoryName string) bool {
+	return strings.HasPrefix(repositoryName, LocalRepositoryScheme)
+}
+
+// repositoryPath returns the path on disk of a file:// repository name, with its symlinks resolved so that a link
+// under the root can not lead out of it. ErrLocalRepositoryOutsideRoot is returned for paths outside of the root
+func (l *LocalGitClient) repositoryPath(repositoryName string) (string, error) {
+	if !IsLocalRepository(repositoryName) {
+		return "", message.ErrInvalidRepositoryName
+	}
+
+	u, err := url.Parse(repositoryName)
+	if err != nil || u.Path == "" || !filepath.IsAbs(u.Path) {
+		return "", message.ErrInvalidRepositoryName
+	}
+
+	path := filepath.Clean(u.Path)
+	if !l.underRoot(path) {
+		return "", message.ErrLocalRepositoryOutsideRoot
+	}
+
+	path, err = filepath.EvalSymlinks(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return "", message.ErrRepositoryNotFound
+	}
+	if err != nil {
+		return "", err
+	}
+	if !l.underRoot(path) {
+		return "", message.ErrLocalRepositoryOutsideRoot
+	}
+	return path, nil
+}
+
+// underRoot reports whether the clean absolute path is the root or a path below it
+func (l *LocalGitClient) underRoot(path string) bool {
+	rel, err := filepath.Rel(l.root, path)
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+// run executes a git command against the repository and returns its standard output
+func (l *LocalGitClient) run(ctx context.Context, path string, args ...string) (string, error) {
+	var stdout, stderr bytes.Buffer
+
+	cmd := exec.CommandContext(ctx, l.gitBinary, append([]string{"-C", path}, args...)...)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		if ctx.Err() == context.Canceled {
+			return "", message.ErrContextCancelled
+		}
+		return "", fmt.Errorf("git %s: %w: %s", args[0], err, strings.TrimSpace(stderr.String()))
+	}
+	return stdout.String(), nil
+}
+
+func (l *LocalGitClient) FetchRepoMetadata(ctx context.Context, repositoryName string) (*domain.RepoMetadata, error) {
+	path, err := l.repositoryPath(repositoryName)
+	if err == message.ErrRepositoryNotFound {
+		log.Error().Msgf("local repository %s does not exist", repositoryName)
+		return nil, err
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	gitDir, err := l.run(ctx, path, "rev-parse", "--absolute-git-dir")
+	if err != nil {
+		log.Error().Msgf("failed to read local repository %s: %v", path, err)
+		return nil, message.ErrRepoMetaDataNotFetched
+	}
+
+	repoMetadata := &domain.RepoMetadata{
+		Name:        repositoryName,
+		Description: readRepositoryDescription(strings.TrimSpace(gitDir)),
+		URL:         repositoryName,
+		Status:      domain.RepoStatusActive,
+	}
+
+	return repoMetadata, nil
+}
+
+// readRepositoryDescription reads the description file of the git directory, ignoring the
+// placeholder git writes on init
+func readRepositoryDescription(gitDir string) string {
+	description, err := os.ReadFile(filepath.Join(gitDir, "description"))
+	if err != nil {
+		return ""
+	}
+
+	d := strings.TrimSpace(string(description))
+	if strings.HasPrefix(d, "Unnamed repository;") {
+		return ""
+	}
+	return d
+}
+
+func (l *LocalGitClient) FetchCommits(ctx context.Context, repo domain.RepoMetadata, since time.Time, until time.Time, lastFetchedCommit string, page, perPage int) ([]domain.Commit, bool, error) {
+	path, err := l.repositoryPath(repo.Name)
+	if err != nil {
+		return nil, false, err
+	}
+
+	if page < 1 {
+		page = 1
+	}
+
+	// one extra commit is read to find out whether another page exists
+	args := []string{
+		"log",
+		"--format=" + logFormat,
+		fmt.Sprintf("--skip=%d", (page-1)*perPage),
+		fmt.Sprintf("--max-count=%d", perPage+1),
+	}
+
+	if lastFetchedCommit != "" {
+		args = append(args, lastFetchedCommit)
+	} else {
+		args = append(args, "--since="+since.Format(time.RFC3339), "--until="+until.Format(time.RFC3339), "HEAD")
+	}
+
+	// separates revisions from paths, so a revision that does not exist fails instead of being read as a path
+	args = append(args, "--")
+
+	output, err := l.run(ctx, path, args...)
+	if err != nil {
+		if errors.Is(err, message.ErrContextCancelled) {
+			return nil, false, err
+		}
+
+		if _, headErr := l.run(ctx, path, "rev-parse", "--verify", "--quiet", "HEAD"); headErr != nil {
+			// the repository has no commits yet
+			return nil, false, nil
+		}
+
+		log.Error().Msgf("error reading commits of local repository %s: %v", path, err)
+		return nil, false, err
+	}
+
+	cc, err := parseLocalCommits(output, repo)
+	if err != nil {
+		return nil, false, err
+	}
+
+	morePages := len(cc) > perPage
+	if morePages {
+		cc = cc[:perPage]
+	}
+
+	return cc, morePages, nil
+}
+
+// parseLocalCommits parses git log output written with logFormat
+func parseLocalCommits(output string, repo domain.RepoMetadata) ([]domain.Commit, error) {
+	var cc []domain.Commit
+
+	for _, record := range strings.Split(output, logRecordSeparator) {
+		record = strings.TrimLeft(record, "\n")
+		if record == "" {
+			continue
+		}
+
+		fields := strings.SplitN(record, logFieldSeparator, 9)
+		if len(fields) != 9 {
+			return nil, fmt.Errorf("unexpected git log record: %q", record)
+		}
+
+		date, err := time.Parse(time.RFC3339, fields[3])
+		if err != nil {
+			return nil, fmt.Errorf("unexpected git log date: %w", err)
+		}
+
+		committerDate, err := time.Parse(time.RFC3339, fields[6])
+		if err != nil {
+			return nil, fmt.Errorf("unexpected git log date: %w", err)
+		}
+
+		parents := strings.Fields(fields[7])
+
+		commit := domain.Commit{
+			CommitID:       fields[0],
+			Message:        strings.TrimSpace(fields[8]),
+			Author:         fields[1],
+			AuthorEmail:    fields[2],
+			Date:           date,
+			CommitterName:  fields[4],
+			CommitterEmail: fields[5],
+			CommitterDate:  committerDate,
+			ParentSHAs:     parents,
+			IsMerge:        len(parents) > 1,
+			RepositoryName: repo.Name,
+			RepositoryHost: repo.Host,
+		}
+
+		cc = append(cc, commit)
+	}
+	return cc, nil
+}
+
+// tagFormat lists the name of a tag, the object it points to and, for an annotated tag, the commit the tag object points to
+const tagFormat = "%(refname:lstrip=2)%1f%(objectname)%1f%(*objectname)"
+
+// FetchTags lists the tags of the repository, annotated tags are peeled to their commit
+func (l *LocalGitClient) FetchTags(ctx context.Context, repo domain.RepoMetadata) ([]domain.Tag, error) {
+	path, err := l.repositoryPath(repo.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	output, err := l.run(ctx, path, "for-each-ref", "--format="+tagFormat, "refs/tags")
+	if err != nil {
+		log.Error().Msgf("error reading tags of local repository %s: %v", path, err)
+		return nil, err
+	}
+
+	var tags []domain.Tag
+	for _, line := range strings.Split(output, "\n") {
+		if line == "" {
+			continue
+		}
+
+		fields := strings.Split(line, logFieldSeparator)
+		if len(fields) != 3 {
+			return nil, fmt.Errorf("unexpected git for-each-ref line: %q", line)
+		}
+
+		tag := domain.Tag{Name: fields[0], TargetSHA: fields[1]}
+		if fields[2] != "" {
+			tag.TargetSHA = fields[2]
+		}
+		tags = append(tags, tag)
+	}
+	return tags, nil
+}
+
+// FetchReleases returns no releases, releases are a feature of git hosts rather than of git
+func (l *LocalGitClient) FetchReleases(ctx context.Context, repo domain.RepoMetadata) ([]domain.Release, error) {
+	return nil, nil
+}
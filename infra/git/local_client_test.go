@@ -0,0 +1,151 @@
+package git_test
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/kenmobility/git-api-service/infra/git"
+	"github.com/kenmobility/git-api-service/internal/domain"
+	"github.com/kenmobility/git-api-service/pkg/message"
+	"github.com/stretchr/testify/require"
+)
+
+// newLocalTestRepository creates a repository with one commit per day of January 2024, up to the given count
+func newLocalTestRepository(t *testing.T, commits int) string {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git binary not available")
+	}
+
+	dir := t.TempDir()
+	runGit := func(env []string, args ...string) {
+		cmd := exec.Command("git", append([]string{"-C", dir}, args...)...)
+		cmd.Env = append(os.Environ(), env...)
+		out, err := cmd.CombinedOutput()
+		require.NoError(t, err, string(out))
+	}
+
+	runGit(nil, "init", "--quiet")
+	for i := 1; i <= commits; i++ {
+		date := fmt.Sprintf("2024-01-%02dT10:00:00Z", i)
+		runGit([]string{
+			"GIT_AUTHOR_NAME=john", "GIT_AUTHOR_EMAIL=john@example.com", "GIT_AUTHOR_DATE=" + date,
+			"GIT_COMMITTER_NAME=john", "GIT_COMMITTER_EMAIL=john@example.com", "GIT_COMMITTER_DATE=" + date,
+		}, "commit", "--quiet", "--allow-empty", "-m", fmt.Sprintf("commit %d", i))
+	}
+	return dir
+}
+
+func TestLocalFetchRepoMetadata(t *testing.T) {
+	dir := newLocalTestRepository(t, 1)
+	repoName := git.LocalRepositoryScheme + dir
+
+	metadata, err := git.NewLocalGitClient(filepath.Dir(dir)).FetchRepoMetadata(context.Background(), repoName)
+	require.NoError(t, err)
+	require.Equal(t, repoName, metadata.Name)
+	require.Empty(t, metadata.Description)
+
+	_, err = git.NewLocalGitClient(filepath.Dir(dir)).FetchRepoMetadata(context.Background(), git.LocalRepositoryScheme+t.TempDir())
+	require.Equal(t, message.ErrRepoMetaDataNotFetched, err)
+
+	_, err = git.NewLocalGitClient(filepath.Dir(dir)).FetchRepoMetadata(context.Background(), repoName+"-deleted")
+	require.Equal(t, message.ErrRepositoryNotFound, err)
+}
+
+func TestLocalFetchCommits(t *testing.T) {
+	dir := newLocalTestRepository(t, 5)
+	repo := domain.RepoMetadata{Name: git.LocalRepositoryScheme + dir}
+	gitClient := git.NewLocalGitClient(filepath.Dir(dir))
+
+	since := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+	until := time.Date(2024, 1, 4, 23, 0, 0, 0, time.UTC)
+
+	commits, morePages, err := gitClient.FetchCommits(context.Background(), repo, since, until, "", 1, 2)
+	require.NoError(t, err)
+	require.True(t, morePages)
+	require.Len(t, commits, 2)
+	require.Equal(t, "commit 4", commits[0].Message)
+	require.Equal(t, "john", commits[0].Author)
+	require.Equal(t, repo.Name, commits[0].RepositoryName)
+	require.True(t, commits[0].Date.Equal(time.Date(2024, 1, 4, 10, 0, 0, 0, time.UTC)))
+
+	commits, morePages, err = gitClient.FetchCommits(context.Background(), repo, since, until, "", 2, 2)
+	require.NoError(t, err)
+	require.False(t, morePages)
+	require.Len(t, commits, 1)
+	require.Equal(t, "commit 2", commits[0].Message)
+
+	// resuming from a commit lists its history regardless of the window
+	commits, _, err = gitClient.FetchCommits(context.Background(), repo, since, until, commits[0].CommitID, 1, 10)
+	require.NoError(t, err)
+	require.Len(t, commits, 2)
+}
+
+func TestLocalFetchCommitsEmptyRepository(t *testing.T) {
+	dir := newLocalTestRepository(t, 0)
+
+	commits, morePages, err := git.NewLocalGitClient(filepath.Dir(dir)).FetchCommits(context.Background(), domain.RepoMetadata{Name: git.LocalRepositoryScheme + dir}, time.Time{}, time.Now(), "", 1, 10)
+	require.NoError(t, err)
+	require.False(t, morePages)
+	require.Empty(t, commits)
+}
+
+func TestLocalFetchTags(t *testing.T) {
+	dir := newLocalTestRepository(t, 2)
+	repo := domain.RepoMetadata{Name: git.LocalRepositoryScheme + dir}
+	gitClient := git.NewLocalGitClient(filepath.Dir(dir))
+
+	commits, _, err := gitClient.FetchCommits(context.Background(), repo, time.Time{}, time.Now(), "HEAD", 1, 10)
+	require.NoError(t, err)
+	require.Len(t, commits, 2)
+
+	tagCmds := [][]string{
+		{"tag", "v1.0.0", commits[1].CommitID},
+		{"tag", "-a", "v1.1.0", "-m", "release 1.1.0", commits[0].CommitID},
+	}
+	for _, args := range tagCmds {
+		cmd := exec.Command("git", append([]string{"-C", dir}, args...)...)
+		cmd.Env = append(os.Environ(), "GIT_COMMITTER_NAME=john", "GIT_COMMITTER_EMAIL=john@example.com")
+		out, err := cmd.CombinedOutput()
+		require.NoError(t, err, string(out))
+	}
+
+	// the annotated tag is peeled to its commit
+	tags, err := gitClient.FetchTags(context.Background(), repo)
+	require.NoError(t, err)
+	require.Equal(t, []domain.Tag{{Name: "v1.0.0", TargetSHA: commits[1].CommitID}, {Name: "v1.1.0", TargetSHA: commits[0].CommitID}}, tags)
+
+	releases, err := gitClient.FetchReleases(context.Background(), repo)
+	require.NoError(t, err)
+	require.Empty(t, releases)
+}
+
+func TestLocalRepositoryOutsideRoot(t *testing.T) {
+	dir := newLocalTestRepository(t, 1)
+	root := t.TempDir()
+	gitClient := git.NewLocalGitClient(root)
+
+	_, err := gitClient.FetchRepoMetadata(context.Background(), git.LocalRepositoryScheme+dir)
+	require.Equal(t, message.ErrLocalRepositoryOutsideRoot, err)
+
+	// dot segments are cleaned before the path is checked
+	_, err = gitClient.FetchRepoMetadata(context.Background(), git.LocalRepositoryScheme+root+"/../"+filepath.Base(dir))
+	require.Equal(t, message.ErrLocalRepositoryOutsideRoot, err)
+
+	// a symlink under the root is resolved to the repository it leads to
+	link := filepath.Join(root, "link.git")
+	require.NoError(t, os.Symlink(dir, link))
+	_, err = gitClient.FetchRepoMetadata(context.Background(), git.LocalRepositoryScheme+link)
+	require.Equal(t, message.ErrLocalRepositoryOutsideRoot, err)
+
+	_, _, err = gitClient.FetchCommits(context.Background(), domain.RepoMetadata{Name: git.LocalRepositoryScheme + link}, time.Time{}, time.Now(), "", 1, 10)
+	require.Equal(t, message.ErrLocalRepositoryOutsideRoot, err)
+
+	metadata, err := git.NewLocalGitClient(filepath.Dir(dir)).FetchRepoMetadata(context.Background(), git.LocalRepositoryScheme+dir)
+	require.NoError(t, err)
+	require.Equal(t, git.LocalRepositoryScheme+dir, metadata.Name)
+}
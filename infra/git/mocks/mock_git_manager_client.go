@@ -1,9 +1,9 @@
 // Code generated by MockGen. DO NOT EDIT.
-// Source: github.com/kenmobility/git-api-service/infra/git (interfaces: GitManagerClient)
+// Source: github.com/kenmobility/git-api-service/infra/git (interfaces: GitManagerClient,CursorCommitFetcher,BranchCommitFetcher,PullRequestFetcher,ValidatorSaver)
 //
 // Generated by this command:
 //
-//	mockgen -package git_mocks -destination infra/git/mocks/mock_git_manager_client.go github.com/kenmobility/git-api-service/infra/git GitManagerClient
+//	mockgen -package git_mocks -destination infra/git/mocks/mock_git_manager_client.go github.com/kenmobility/git-api-service/infra/git GitManagerClient,CursorCommitFetcher,BranchCommitFetcher,PullRequestFetcher,ValidatorSaver
 //
 
 // Package git_mocks is a generated GoMock package.
@@ -14,6 +14,7 @@ import (
 	reflect "reflect"
 	time "time"
 
+	git "github.com/kenmobility/git-api-service/infra/git"
 	domain "github.com/kenmobility/git-api-service/internal/domain"
 	gomock "go.uber.org/mock/gomock"
 )
@@ -57,6 +58,21 @@ func (mr *MockGitManagerClientMockRecorder) FetchCommits(arg0, arg1, arg2, arg3,
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FetchCommits", reflect.TypeOf((*MockGitManagerClient)(nil).FetchCommits), arg0, arg1, arg2, arg3, arg4, arg5, arg6)
 }
 
+// FetchReleases mocks base method.
+func (m *MockGitManagerClient) FetchReleases(arg0 context.Context, arg1 domain.RepoMetadata) ([]domain.Release, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FetchReleases", arg0, arg1)
+	ret0, _ := ret[0].([]domain.Release)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FetchReleases indicates an expected call of FetchReleases.
+func (mr *MockGitManagerClientMockRecorder) FetchReleases(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FetchReleases", reflect.TypeOf((*MockGitManagerClient)(nil).FetchReleases), arg0, arg1)
+}
+
 // FetchRepoMetadata mocks base method.
 func (m *MockGitManagerClient) FetchRepoMetadata(arg0 context.Context, arg1 string) (*domain.RepoMetadata, error) {
 	m.ctrl.T.Helper()
@@ -71,3 +87,199 @@ func (mr *MockGitManagerClientMockRecorder) FetchRepoMetadata(arg0, arg1 any) *g
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FetchRepoMetadata", reflect.TypeOf((*MockGitManagerClient)(nil).FetchRepoMetadata), arg0, arg1)
 }
+
+// FetchTags mocks base method.
+func (m *MockGitManagerClient) FetchTags(arg0 context.Context, arg1 domain.RepoMetadata) ([]domain.Tag, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FetchTags", arg0, arg1)
+	ret0, _ := ret[0].([]domain.Tag)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FetchTags indicates an expected call of FetchTags.
+func (mr *MockGitManagerClientMockRecorder) FetchTags(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FetchTags", reflect.TypeOf((*MockGitManagerClient)(nil).FetchTags), arg0, arg1)
+}
+
+// MockCursorCommitFetcher is a mock of CursorCommitFetcher interface.
+type MockCursorCommitFetcher struct {
+	ctrl     *gomock.Controller
+	recorder *MockCursorCommitFetcherMockRecorder
+}
+
+// MockCursorCommitFetcherMockRecorder is the mock recorder for MockCursorCommitFetcher.
+type MockCursorCommitFetcherMockRecorder struct {
+	mock *MockCursorCommitFetcher
+}
+
+// NewMockCursorCommitFetcher creates a new mock instance.
+func NewMockCursorCommitFetcher(ctrl *gomock.Controller) *MockCursorCommitFetcher {
+	mock := &MockCursorCommitFetcher{ctrl: ctrl}
+	mock.recorder = &MockCursorCommitFetcherMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCursorCommitFetcher) EXPECT() *MockCursorCommitFetcherMockRecorder {
+	return m.recorder
+}
+
+// FetchCommitsAfter mocks base method.
+func (m *MockCursorCommitFetcher) FetchCommitsAfter(arg0 context.Context, arg1 domain.RepoMetadata, arg2, arg3 time.Time, arg4, arg5 string, arg6 int) (*git.CommitPage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FetchCommitsAfter", arg0, arg1, arg2, arg3, arg4, arg5, arg6)
+	ret0, _ := ret[0].(*git.CommitPage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FetchCommitsAfter indicates an expected call of FetchCommitsAfter.
+func (mr *MockCursorCommitFetcherMockRecorder) FetchCommitsAfter(arg0, arg1, arg2, arg3, arg4, arg5, arg6 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FetchCommitsAfter", reflect.TypeOf((*MockCursorCommitFetcher)(nil).FetchCommitsAfter), arg0, arg1, arg2, arg3, arg4, arg5, arg6)
+}
+
+// MockBranchCommitFetcher is a mock of BranchCommitFetcher interface.
+type MockBranchCommitFetcher struct {
+	ctrl     *gomock.Controller
+	recorder *MockBranchCommitFetcherMockRecorder
+}
+
+// MockBranchCommitFetcherMockRecorder is the mock recorder for MockBranchCommitFetcher.
+type MockBranchCommitFetcherMockRecorder struct {
+	mock *MockBranchCommitFetcher
+}
+
+// NewMockBranchCommitFetcher creates a new mock instance.
+func NewMockBranchCommitFetcher(ctrl *gomock.Controller) *MockBranchCommitFetcher {
+	mock := &MockBranchCommitFetcher{ctrl: ctrl}
+	mock.recorder = &MockBranchCommitFetcherMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockBranchCommitFetcher) EXPECT() *MockBranchCommitFetcherMockRecorder {
+	return m.recorder
+}
+
+// FetchBranchCommits mocks base method.
+func (m *MockBranchCommitFetcher) FetchBranchCommits(arg0 context.Context, arg1 domain.RepoMetadata, arg2 string, arg3, arg4 time.Time, arg5 string, arg6 int) (*git.CommitPage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FetchBranchCommits", arg0, arg1, arg2, arg3, arg4, arg5, arg6)
+	ret0, _ := ret[0].(*git.CommitPage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FetchBranchCommits indicates an expected call of FetchBranchCommits.
+func (mr *MockBranchCommitFetcherMockRecorder) FetchBranchCommits(arg0, arg1, arg2, arg3, arg4, arg5, arg6 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FetchBranchCommits", reflect.TypeOf((*MockBranchCommitFetcher)(nil).FetchBranchCommits), arg0, arg1, arg2, arg3, arg4, arg5, arg6)
+}
+
+// FetchBranches mocks base method.
+func (m *MockBranchCommitFetcher) FetchBranches(arg0 context.Context, arg1 domain.RepoMetadata) ([]domain.Branch, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FetchBranches", arg0, arg1)
+	ret0, _ := ret[0].([]domain.Branch)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FetchBranches indicates an expected call of FetchBranches.
+func (mr *MockBranchCommitFetcherMockRecorder) FetchBranches(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FetchBranches", reflect.TypeOf((*MockBranchCommitFetcher)(nil).FetchBranches), arg0, arg1)
+}
+
+// MockPullRequestFetcher is a mock of PullRequestFetcher interface.
+type MockPullRequestFetcher struct {
+	ctrl     *gomock.Controller
+	recorder *MockPullRequestFetcherMockRecorder
+}
+
+// MockPullRequestFetcherMockRecorder is the mock recorder for MockPullRequestFetcher.
+type MockPullRequestFetcherMockRecorder struct {
+	mock *MockPullRequestFetcher
+}
+
+// NewMockPullRequestFetcher creates a new mock instance.
+func NewMockPullRequestFetcher(ctrl *gomock.Controller) *MockPullRequestFetcher {
+	mock := &MockPullRequestFetcher{ctrl: ctrl}
+	mock.recorder = &MockPullRequestFetcherMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPullRequestFetcher) EXPECT() *MockPullRequestFetcherMockRecorder {
+	return m.recorder
+}
+
+// FetchPullRequestCommits mocks base method.
+func (m *MockPullRequestFetcher) FetchPullRequestCommits(arg0 context.Context, arg1 domain.RepoMetadata, arg2 int) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FetchPullRequestCommits", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FetchPullRequestCommits indicates an expected call of FetchPullRequestCommits.
+func (mr *MockPullRequestFetcherMockRecorder) FetchPullRequestCommits(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FetchPullRequestCommits", reflect.TypeOf((*MockPullRequestFetcher)(nil).FetchPullRequestCommits), arg0, arg1, arg2)
+}
+
+// FetchPullRequests mocks base method.
+func (m *MockPullRequestFetcher) FetchPullRequests(arg0 context.Context, arg1 domain.RepoMetadata, arg2 time.Time) ([]domain.PullRequest, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FetchPullRequests", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]domain.PullRequest)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FetchPullRequests indicates an expected call of FetchPullRequests.
+func (mr *MockPullRequestFetcherMockRecorder) FetchPullRequests(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FetchPullRequests", reflect.TypeOf((*MockPullRequestFetcher)(nil).FetchPullRequests), arg0, arg1, arg2)
+}
+
+// MockValidatorSaver is a mock of ValidatorSaver interface.
+type MockValidatorSaver struct {
+	ctrl     *gomock.Controller
+	recorder *MockValidatorSaverMockRecorder
+}
+
+// MockValidatorSaverMockRecorder is the mock recorder for MockValidatorSaver.
+type MockValidatorSaverMockRecorder struct {
+	mock *MockValidatorSaver
+}
+
+// NewMockValidatorSaver creates a new mock instance.
+func NewMockValidatorSaver(ctrl *gomock.Controller) *MockValidatorSaver {
+	mock := &MockValidatorSaver{ctrl: ctrl}
+	mock.recorder = &MockValidatorSaverMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockValidatorSaver) EXPECT() *MockValidatorSaverMockRecorder {
+	return m.recorder
+}
+
+// SaveValidator mocks base method.
+func (m *MockValidatorSaver) SaveValidator(arg0 context.Context, arg1 domain.HTTPValidator) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveValidator", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveValidator indicates an expected call of SaveValidator.
+func (mr *MockValidatorSaverMockRecorder) SaveValidator(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveValidator", reflect.TypeOf((*MockValidatorSaver)(nil).SaveValidator), arg0, arg1)
+}
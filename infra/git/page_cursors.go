@@ -0,0 +1,49 @@
+package git
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// pageCursors keeps the cursor (or url) of every page reached in a listing, for providers that paginate
+// with opaque cursors rather than the page numbers the GitManagerClient interface uses
+type pageCursors struct {
+	mu      sync.Mutex
+	cursors map[string]string
+}
+
+func newPageCursors() *pageCursors {
+	return &pageCursors{cursors: make(map[string]string)}
+}
+
+// get returns the cursor of the page of the listing, if it was reached before
+func (p *pageCursors) get(listing string, page int) (string, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	cursor, ok := p.cursors[pageCursorKey(listing, page)]
+	return cursor, ok
+}
+
+// storeNext keeps the cursor of the page after the given one, the cursors kept for a listing
+// are dropped once its last page is reached
+func (p *pageCursors) storeNext(listing string, page int, next string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if next != "" {
+		p.cursors[pageCursorKey(listing, page+1)] = next
+		return
+	}
+
+	for key := range p.cursors {
+		if strings.HasPrefix(key, listing+"#") {
+			delete(p.cursors, key)
+		}
+	}
+}
+
+func pageCursorKey(listing string, page int) string {
+	return fmt.Sprintf("%s#%d", listing, page)
+}
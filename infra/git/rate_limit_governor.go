@@ -0,0 +1,267 @@
+package git
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/kenmobility/git-api-service/internal/domain"
+	"github.com/kenmobility/git-api-service/pkg/client"
+	"github.com/kenmobility/git-api-service/pkg/message"
+	"github.com/rs/zerolog/log"
+)
+
+// RateLimitReporter is implemented by the clients reporting the rate limit budget left on their host
+type RateLimitReporter interface {
+	RateLimitStatus() domain.RateLimitStatus
+}
+
+// requestError maps the error of a request rejected by a secondary rate limit on every attempt to
+// ErrRateLimitExceeded, keeping the retry count and cause, and the error of a cancelled request to ErrContextCancelled
+func requestError(err error) error {
+	if errors.Is(err, context.Canceled) {
+		return message.ErrContextCancelled
+	}
+
+	if errors.Is(err, client.ErrSecondaryRateLimit) {
+		return fmt.Errorf("%w: %w", message.ErrRateLimitExceeded, err)
+	}
+	return err
+}
+
+// rateLimitBudget is the budget reported for a token, remaining is -1 until the token served its first request
+type rateLimitBudget struct {
+	key       string
+	limit     int
+	remaining int
+	reset     time.Time
+}
+
+// RateLimitGovernor is the rate limit budget shared by every goroutine fetching from a host, each request
+// asks it for the token to send with and waits, until the context is done, while no token has budget left
+type RateLimitGovernor struct {
+	mu      sync.Mutex
+	budgets []*rateLimitBudget
+	waiters atomic.Int64
+}
+
+// NewRateLimitGovernor returns a governor rotating requests over the tokens, without tokens requests are
+// sent anonymously
+func NewRateLimitGovernor(tokens []string) *RateLimitGovernor {
+	governor := &RateLimitGovernor{}
+	for _, token := range tokens {
+		if token != "" {
+			governor.budgets = append(governor.budgets, &rateLimitBudget{key: token, remaining: -1})
+		}
+	}
+
+	if len(governor.budgets) == 0 {
+		governor.budgets = append(governor.budgets, &rateLimitBudget{remaining: -1})
+	}
+	return governor
+}
+
+// size returns the number of tokens of the governor
+func (g *RateLimitGovernor) size() int {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	return len(g.budgets)
+}
+
+// Acquire returns the token with the most budget left, when every token is exhausted it waits
+// for the earliest reset
+func (g *RateLimitGovernor) Acquire(ctx context.Context) (string, error) {
+	return g.acquire(ctx, func(now time.Time) (string, time.Duration) {
+		return g.best(now)
+	})
+}
+
+// Wait waits until the budget kept under key is reset if it is exhausted, for tokens that are not
+// rotated such as installation tokens
+func (g *RateLimitGovernor) Wait(ctx context.Context, key string) error {
+	_, err := g.acquire(ctx, func(now time.Time) (string, time.Duration) {
+		g.mu.Lock()
+		defer g.mu.Unlock()
+
+		b := g.budget(key)
+		if b.remaining == 0 && now.Before(b.reset) {
+			return "", b.reset.Sub(now)
+		}
+		return key, 0
+	})
+	return err
+}
+
+func (g *RateLimitGovernor) acquire(ctx context.Context, next func(now time.Time) (string, time.Duration)) (string, error) {
+	for {
+		if ctx.Err() != nil {
+			return "", message.ErrContextCancelled
+		}
+
+		key, wait := next(time.Now())
+		if wait <= 0 {
+			return key, nil
+		}
+
+		log.Info().Msgf("Rate limit exceeded. Waiting for %v until reset...", wait)
+
+		g.waiters.Add(1)
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			g.waiters.Add(-1)
+			return "", message.ErrContextCancelled
+		case <-timer.C:
+			g.waiters.Add(-1)
+		}
+	}
+}
+
+// best returns the token with the most budget left, or how long to wait for the earliest reset
+// when none has budget left
+func (g *RateLimitGovernor) best(now time.Time) (string, time.Duration) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	var best *rateLimitBudget
+	var earliestReset time.Time
+	for _, b := range g.budgets {
+		if b.remaining == 0 && !now.Before(b.reset) {
+			// the window was reset, the budget is unknown until the next response
+			b.remaining = -1
+		}
+
+		if b.remaining == 0 {
+			if earliestReset.IsZero() || b.reset.Before(earliestReset) {
+				earliestReset = b.reset
+			}
+			continue
+		}
+
+		if best == nil || budgetLeft(b) > budgetLeft(best) {
+			best = b
+		}
+	}
+
+	if best == nil {
+		return "", earliestReset.Sub(now)
+	}
+
+	if best.remaining > 0 {
+		// reserve the request so concurrent callers spread over the tokens
+		best.remaining--
+	}
+	return best.key, 0
+}
+
+// budgetLeft ranks a token, tokens that did not serve a request yet are tried first
+func budgetLeft(b *rateLimitBudget) int {
+	if b.remaining < 0 {
+		return int(^uint(0) >> 1)
+	}
+	return b.remaining
+}
+
+// budget returns the budget kept under key, adding it when it is not tracked yet, g.mu must be held
+func (g *RateLimitGovernor) budget(key string) *rateLimitBudget {
+	for _, b := range g.budgets {
+		if b.key == key {
+			return b
+		}
+	}
+
+	b := &rateLimitBudget{key: key, remaining: -1}
+	g.budgets = append(g.budgets, b)
+	return b
+}
+
+// Update records the rate limit headers of a response sent with the token kept under key
+func (g *RateLimitGovernor) Update(key string, resp *client.Response) {
+	if len(resp.Headers["X-Ratelimit-Remaining"]) == 0 {
+		// the response does not report the budget
+		return
+	}
+
+	limit, remaining, reset := -1, -1, time.Time{}
+
+	if l := resp.Headers["X-Ratelimit-Limit"]; len(l) > 0 {
+		if v, err := strconv.Atoi(l[0]); err == nil {
+			limit = v
+		}
+	}
+
+	if r := resp.Headers["X-Ratelimit-Remaining"]; len(r) > 0 {
+		if v, err := strconv.Atoi(r[0]); err == nil {
+			remaining = v
+		}
+	}
+
+	if r := resp.Headers["X-Ratelimit-Reset"]; len(r) > 0 {
+		if v, err := strconv.ParseInt(r[0], 10, 64); err == nil {
+			reset = time.Unix(v, 0)
+		}
+	}
+
+	g.Record(key, limit, remaining, reset)
+}
+
+// Record records the budget reported for the token kept under key, negative values and a zero reset are
+// left unchanged
+func (g *RateLimitGovernor) Record(key string, limit, remaining int, reset time.Time) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	b := g.budget(key)
+	if limit >= 0 {
+		b.limit = limit
+	}
+	if remaining >= 0 {
+		b.remaining = remaining
+	}
+	if !reset.IsZero() {
+		b.reset = reset
+	}
+
+	log.Info().Msgf("Rate limit remaining: %d/%d, resets at %v", b.remaining, b.limit, b.reset)
+}
+
+// Status returns the budget left summed over the tokens, tokens that did not serve a request yet are left out
+func (g *RateLimitGovernor) Status() domain.RateLimitStatus {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	status := domain.RateLimitStatus{Waiters: g.waiters.Load()}
+
+	var earliestExhausted, earliestReset time.Time
+	now := time.Now()
+	for _, b := range g.budgets {
+		if b.remaining < 0 {
+			continue
+		}
+
+		status.Limit += b.limit
+		if b.remaining == 0 && now.Before(b.reset) {
+			if earliestExhausted.IsZero() || b.reset.Before(earliestExhausted) {
+				earliestExhausted = b.reset
+			}
+		} else {
+			status.Remaining += b.remaining
+		}
+
+		if earliestReset.IsZero() || b.reset.Before(earliestReset) {
+			earliestReset = b.reset
+		}
+	}
+
+	status.Reset = earliestReset
+	if !earliestExhausted.IsZero() {
+		status.Reset = earliestExhausted
+	}
+	return status
+}
@@ -0,0 +1,93 @@
+package git_test
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/kenmobility/git-api-service/infra/git"
+	"github.com/kenmobility/git-api-service/pkg/client"
+	"github.com/kenmobility/git-api-service/pkg/message"
+	"github.com/stretchr/testify/require"
+)
+
+func rateLimitResponse(limit, remaining int, reset time.Time) *client.Response {
+	return &client.Response{
+		StatusCode: http.StatusOK,
+		Headers: map[string][]string{
+			"X-Ratelimit-Limit":     {strconv.Itoa(limit)},
+			"X-Ratelimit-Remaining": {strconv.Itoa(remaining)},
+			"X-Ratelimit-Reset":     {strconv.FormatInt(reset.Unix(), 10)},
+		},
+	}
+}
+
+func TestRateLimitGovernorConcurrentAcquire(t *testing.T) {
+	governor := git.NewRateLimitGovernor([]string{"token-a", "token-b"})
+	reset := time.Now().Add(time.Hour)
+
+	// both tokens report their budget before they are shared
+	for i := 0; i < 2; i++ {
+		token, err := governor.Acquire(context.Background())
+		require.NoError(t, err)
+		governor.Update(token, rateLimitResponse(5000, 100, reset))
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			token, err := governor.Acquire(context.Background())
+			require.NoError(t, err)
+			governor.Update(token, rateLimitResponse(5000, 100, reset))
+		}()
+	}
+	wg.Wait()
+
+	status := governor.Status()
+	require.Equal(t, 10000, status.Limit)
+	require.Equal(t, 200, status.Remaining)
+	require.Equal(t, reset.Unix(), status.Reset.Unix())
+	require.Zero(t, status.Waiters)
+}
+
+func TestRateLimitGovernorWaitsForReset(t *testing.T) {
+	governor := git.NewRateLimitGovernor([]string{"token-a"})
+	reset := time.Now().Add(time.Hour)
+	governor.Update("token-a", rateLimitResponse(5000, 0, reset))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() {
+		_, err := governor.Acquire(ctx)
+		done <- err
+	}()
+
+	// the waiting request is reported until its context is cancelled
+	require.Eventually(t, func() bool { return governor.Status().Waiters == 1 }, time.Second, 5*time.Millisecond)
+	require.Equal(t, 0, governor.Status().Remaining)
+	require.Equal(t, reset.Unix(), governor.Status().Reset.Unix())
+
+	cancel()
+	require.Equal(t, message.ErrContextCancelled, <-done)
+	require.Zero(t, governor.Status().Waiters)
+}
+
+func TestRateLimitGovernorWaitResumesAfterReset(t *testing.T) {
+	governor := git.NewRateLimitGovernor(nil)
+	governor.Record("installation-1", 5000, 0, time.Now().Add(50*time.Millisecond))
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	require.NoError(t, governor.Wait(ctx, "installation-1"))
+
+	// other budgets are not affected by the exhausted one
+	governor.Record("installation-1", 5000, 0, time.Now().Add(time.Hour))
+	require.NoError(t, governor.Wait(ctx, "installation-2"))
+}
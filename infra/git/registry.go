@@ -0,0 +1,337 @@
+package git
+
+import (
+	"context"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/kenmobility/git-api-service/infra/config"
+	"github.com/kenmobility/git-api-service/internal/domain"
+	"github.com/kenmobility/git-api-service/internal/repository"
+	"github.com/kenmobility/git-api-service/pkg/message"
+	"github.com/rs/zerolog/log"
+)
+
+// Supported git providers, stored on each repository so it is monitored with the backend it was indexed with
+const (
+	ProviderGitHub    = "github"
+	ProviderGitLab    = "gitlab"
+	ProviderBitbucket = "bitbucket"
+	ProviderGitea     = "gitea"
+	ProviderLocal     = "local"
+)
+
+// GitHub fetch modes, selected with GITHUB_FETCH_MODE
+const (
+	GitHubFetchModeREST    = "rest"
+	GitHubFetchModeGraphQL = "graphql"
+)
+
+// LocalRepositoryHost is the host recorded for repositories read from disk
+const LocalRepositoryHost = "localhost"
+
+// RepositoryRef identifies a repository on a git host
+type RepositoryRef struct {
+	Provider string
+	Host     string
+	// Name is the repository path on its host, eg owner/repo or group/subgroup/repo for GitLab
+	Name string
+}
+
+type registeredClient struct {
+	provider string
+	client   GitManagerClient
+}
+
+// Registry routes repositories to the GitManagerClient serving their host
+type Registry struct {
+	defaultHost string
+	credentials repository.CredentialRepository
+
+	mu      sync.RWMutex
+	clients map[string]registeredClient
+	// credentialClients are the clients authenticating with the credential of repositories, by host and credential
+	credentialClients map[string]GitManagerClient
+}
+
+// NewRegistry returns an empty registry, repository names without a host resolve to defaultHost
+func NewRegistry(defaultHost string) *Registry {
+	return &Registry{
+		defaultHost:       strings.ToLower(defaultHost),
+		clients:           make(map[string]registeredClient),
+		credentialClients: make(map[string]GitManagerClient),
+	}
+}
+
+// NewProviderRegistry returns a registry with a client registered for each configured git host, validators
+// keeps the cache validators of conditional requests and credentials the credentials of the repositories having one
+func NewProviderRegistry(config config.Config, validators repository.HTTPValidatorRepository, credentials repository.CredentialRepository) (*Registry, error) {
+	registry := NewRegistry(config.GitHubHost)
+	registry.SetCredentials(credentials)
+
+	githubClient, err := newGitHubClientForMode(config, validators)
+	if err != nil {
+		return nil, err
+	}
+
+	registry.Register(ProviderGitHub, config.GitHubHost, githubClient)
+	registry.Register(ProviderGitLab, hostOf(config.GitLabApiBaseURL), NewGitLabClient(config.GitLabApiBaseURL, config.GitLabToken))
+	registry.Register(ProviderBitbucket, config.BitbucketHost, NewBitbucketClient(config.BitbucketApiBaseURL, config.BitbucketUsername, config.BitbucketAppPassword))
+
+	for _, instance := range config.GiteaInstances {
+		registry.Register(ProviderGitea, hostOf(instance.ApiBaseURL), NewGiteaClient(instance.ApiBaseURL, instance.Token))
+	}
+
+	// repositories on disk are only served from the configured root, file:// paths are unsupported without one
+	if config.LocalRepositoriesRoot != "" {
+		registry.Register(ProviderLocal, LocalRepositoryHost, NewLocalGitClient(config.LocalRepositoriesRoot))
+	}
+
+	return registry, nil
+}
+
+// newGitHubClientForMode returns the GitHub client for the configured fetch mode and authentication, the
+// GraphQL api does not serve anonymous requests so the REST client is used when no token is set
+func newGitHubClientForMode(config config.Config, validators repository.HTTPValidatorRepository) (GitManagerClient, error) {
+	if config.GitHubAppID != 0 {
+		appAuth, err := NewGitHubAppAuth(config.GitHubApiBaseURL, config.GitHubAppID, config.GitHubAppPrivateKey)
+		if err != nil {
+			return nil, err
+		}
+
+		if config.GitHubFetchMode == GitHubFetchModeGraphQL {
+			log.Warn().Msg("GITHUB_FETCH_MODE is graphql but GitHub App authentication is only supported by the REST api, falling back to the REST api")
+		}
+		return NewGitHubAppClient(config.GitHubApiBaseURL, appAuth, config.FetchInterval, validators), nil
+	}
+
+	if config.GitHubFetchMode == GitHubFetchModeGraphQL {
+		if len(config.GitHubTokens) > 0 {
+			return NewGitHubGraphQLClient(config.GitHubGraphQLURL, config.GitHubTokens), nil
+		}
+		log.Warn().Msg("GITHUB_FETCH_MODE is graphql but GIT_HUB_TOKEN is not set, falling back to the REST api")
+	}
+	return NewGitHubClient(config.GitHubApiBaseURL, config.GitHubTokens, config.FetchInterval, validators), nil
+}
+
+// Register routes repositories on host to the client, replacing any client registered for it before
+func (r *Registry) Register(provider, host string, client GitManagerClient) {
+	if host == "" {
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.clients[strings.ToLower(host)] = registeredClient{provider: provider, client: client}
+}
+
+// SetCredentials sets the repository the credentials of the repositories having one are read from
+func (r *Registry) SetCredentials(credentials repository.CredentialRepository) {
+	r.credentials = credentials
+}
+
+// Client returns the client serving a stored repository, authenticating with the credential of the repository when it has one
+func (r *Registry) Client(repo domain.RepoMetadata) (GitManagerClient, error) {
+	host := repo.Host
+	if host == "" {
+		host = r.defaultHost
+	}
+
+	rc, err := r.lookup(host)
+	if err != nil {
+		return nil, err
+	}
+
+	if repo.CredentialID == "" {
+		return rc.client, nil
+	}
+	return r.credentialClient(host, repo.CredentialID)
+}
+
+// ClientWithToken returns a client of the host authenticating with the token
+func (r *Registry) ClientWithToken(host string, token string) (GitManagerClient, error) {
+	rc, err := r.lookup(host)
+	if err != nil {
+		return nil, err
+	}
+
+	scoped, ok := rc.client.(TokenScopedClient)
+	if !ok {
+		return nil, message.ErrCredentialsNotSupported
+	}
+	return scoped.WithToken(token), nil
+}
+
+// credentialClient returns the client of the host authenticating with the credential, built on its first use
+// so that the requests of every repository sharing the credential count towards the same budget
+func (r *Registry) credentialClient(host string, credentialID string) (GitManagerClient, error) {
+	key := strings.ToLower(host) + "/" + credentialID
+
+	r.mu.RLock()
+	c, ok := r.credentialClients[key]
+	r.mu.RUnlock()
+	if ok {
+		return c, nil
+	}
+
+	if r.credentials == nil {
+		return nil, message.ErrCredentialsNotConfigured
+	}
+
+	credential, err := r.credentials.CredentialByPublicId(context.Background(), credentialID)
+	if err != nil {
+		return nil, err
+	}
+
+	c, err = r.ClientWithToken(host, credential.Token)
+	if err != nil {
+		return nil, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	// another caller may have built the client meanwhile
+	if existing, ok := r.credentialClients[key]; ok {
+		return existing, nil
+	}
+	r.credentialClients[key] = c
+	return c, nil
+}
+
+// RateLimits returns the rate limit status of every host whose client reports one, sorted by host
+func (r *Registry) RateLimits() []domain.RateLimitStatus {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	statuses := []domain.RateLimitStatus{}
+	for host, rc := range r.clients {
+		reporter, ok := rc.client.(RateLimitReporter)
+		if !ok {
+			continue
+		}
+
+		status := reporter.RateLimitStatus()
+		status.Provider, status.Host = rc.provider, host
+		statuses = append(statuses, status)
+	}
+
+	slices.SortFunc(statuses, func(a, b domain.RateLimitStatus) int {
+		return strings.Compare(a.Host, b.Host)
+	})
+	return statuses
+}
+
+// Resolve parses a repository identifier and returns it with the client serving its host. Identifiers can be
+// owner/repo for the default host, host-qualified like gitlab.example.com/group/repo, clone urls like
+// https://gitlab.example.com/group/repo.git or git@github.com:owner/repo.git, or file:// paths
+func (r *Registry) Resolve(identifier string) (*RepositoryRef, GitManagerClient, error) {
+	identifier = strings.TrimSpace(identifier)
+
+	if IsLocalRepository(identifier) {
+		rc, err := r.lookup(LocalRepositoryHost)
+		if err != nil {
+			return nil, nil, err
+		}
+		return &RepositoryRef{Provider: rc.provider, Host: LocalRepositoryHost, Name: identifier}, rc.client, nil
+	}
+
+	host, path, err := r.splitIdentifier(identifier)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	rc, err := r.lookup(host)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	segments := strings.Split(path, "/")
+
+	// urls copied from the web ui may point below the repository, eg /-/tree/main on GitLab or /tree/main elsewhere;
+	// only GitLab nests repositories in subgroups, every other provider addresses them as owner/repo
+	if i := slices.Index(segments, "-"); i >= 0 {
+		segments = segments[:i]
+	}
+	if rc.provider != ProviderGitLab && len(segments) > 2 {
+		segments = segments[:2]
+	}
+
+	if len(segments) < 2 {
+		return nil, nil, message.ErrInvalidRepositoryName
+	}
+	for _, s := range segments {
+		if s == "" {
+			return nil, nil, message.ErrInvalidRepositoryName
+		}
+	}
+
+	return &RepositoryRef{Provider: rc.provider, Host: strings.ToLower(host), Name: strings.Join(segments, "/")}, rc.client, nil
+}
+
+// splitIdentifier splits an identifier into its host and repository path
+func (r *Registry) splitIdentifier(identifier string) (string, string, error) {
+	var host, path string
+
+	switch {
+	case strings.Contains(identifier, "://"):
+		u, err := url.Parse(identifier)
+		if err != nil || u.Host == "" {
+			return "", "", message.ErrInvalidRepositoryName
+		}
+		host, path = u.Host, u.Path
+		// ssh clone urls carry the ssh port, which is not part of the host's web address
+		if u.Scheme == "ssh" || u.Scheme == "git" {
+			host = u.Hostname()
+		}
+	case strings.Contains(identifier, "@") && strings.Contains(identifier, ":"):
+		// scp-like clone url, eg git@github.com:owner/repo.git
+		_, rest, _ := strings.Cut(identifier, "@")
+		host, path, _ = strings.Cut(rest, ":")
+	default:
+		first, rest, _ := strings.Cut(identifier, "/")
+		// owner names cannot contain dots, so a dotted first segment is a host even when none is registered for it
+		if r.isRegistered(first) || (strings.Contains(first, ".") && strings.Count(rest, "/") > 0) {
+			host, path = first, rest
+		} else {
+			host, path = r.defaultHost, identifier
+		}
+	}
+
+	path = strings.Trim(path, "/")
+	path = strings.TrimSuffix(path, ".git")
+
+	if host == "" || path == "" {
+		return "", "", message.ErrInvalidRepositoryName
+	}
+	return host, path, nil
+}
+
+func (r *Registry) isRegistered(host string) bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	_, ok := r.clients[strings.ToLower(host)]
+	return ok
+}
+
+func (r *Registry) lookup(host string) (registeredClient, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	rc, ok := r.clients[strings.ToLower(host)]
+	if !ok {
+		return registeredClient{}, message.ErrUnsupportedGitHost
+	}
+	return rc, nil
+}
+
+// hostOf returns the host of an api base url, eg gitlab.example.com for https://gitlab.example.com/api/v4
+func hostOf(baseURL string) string {
+	u, err := url.Parse(baseURL)
+	if err != nil {
+		return ""
+	}
+	return u.Host
+}
@@ -0,0 +1,150 @@
+package git_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/kenmobility/git-api-service/infra/git"
+	git_mocks "github.com/kenmobility/git-api-service/infra/git/mocks"
+	"github.com/kenmobility/git-api-service/internal/domain"
+	repo_mocks "github.com/kenmobility/git-api-service/internal/repository/mocks"
+	"github.com/kenmobility/git-api-service/pkg/message"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestRegistryResolve(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	githubClient := git_mocks.NewMockGitManagerClient(ctrl)
+	gitlabClient := git_mocks.NewMockGitManagerClient(ctrl)
+	localClient := git_mocks.NewMockGitManagerClient(ctrl)
+
+	registry := git.NewRegistry("github.com")
+	registry.Register(git.ProviderGitHub, "github.com", githubClient)
+	registry.Register(git.ProviderGitLab, "gitlab.example.com", gitlabClient)
+	registry.Register(git.ProviderLocal, git.LocalRepositoryHost, localClient)
+
+	testCases := []struct {
+		identifier string
+		expected   git.RepositoryRef
+		client     git.GitManagerClient
+	}{
+		{"owner/repo", git.RepositoryRef{Provider: git.ProviderGitHub, Host: "github.com", Name: "owner/repo"}, githubClient},
+		{"github.com/owner/repo", git.RepositoryRef{Provider: git.ProviderGitHub, Host: "github.com", Name: "owner/repo"}, githubClient},
+		{"https://github.com/owner/repo/tree/main", git.RepositoryRef{Provider: git.ProviderGitHub, Host: "github.com", Name: "owner/repo"}, githubClient},
+		{"git@github.com:owner/repo.git", git.RepositoryRef{Provider: git.ProviderGitHub, Host: "github.com", Name: "owner/repo"}, githubClient},
+		{"gitlab.example.com/group/sub/repo", git.RepositoryRef{Provider: git.ProviderGitLab, Host: "gitlab.example.com", Name: "group/sub/repo"}, gitlabClient},
+		{"https://GitLab.example.com/group/repo.git", git.RepositoryRef{Provider: git.ProviderGitLab, Host: "gitlab.example.com", Name: "group/repo"}, gitlabClient},
+		{"https://gitlab.example.com/group/repo/-/tree/main", git.RepositoryRef{Provider: git.ProviderGitLab, Host: "gitlab.example.com", Name: "group/repo"}, gitlabClient},
+		{"ssh://git@gitlab.example.com:2222/group/repo.git", git.RepositoryRef{Provider: git.ProviderGitLab, Host: "gitlab.example.com", Name: "group/repo"}, gitlabClient},
+		{"file:///srv/mirrors/foo.git", git.RepositoryRef{Provider: git.ProviderLocal, Host: git.LocalRepositoryHost, Name: "file:///srv/mirrors/foo.git"}, localClient},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.identifier, func(t *testing.T) {
+			ref, client, err := registry.Resolve(tc.identifier)
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, *ref)
+			require.True(t, client == tc.client)
+		})
+	}
+}
+
+func TestRegistryResolveErrors(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	registry := git.NewRegistry("github.com")
+	registry.Register(git.ProviderGitHub, "github.com", git_mocks.NewMockGitManagerClient(ctrl))
+
+	_, _, err := registry.Resolve("gitlab.unknown.com/group/repo")
+	require.Equal(t, message.ErrUnsupportedGitHost, err)
+
+	_, _, err = registry.Resolve("https://bitbucket.org/owner/repo")
+	require.Equal(t, message.ErrUnsupportedGitHost, err)
+
+	_, _, err = registry.Resolve("github.com/owner")
+	require.Equal(t, message.ErrInvalidRepositoryName, err)
+}
+
+func TestRegistryClient(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	githubClient := git_mocks.NewMockGitManagerClient(ctrl)
+	gitlabClient := git_mocks.NewMockGitManagerClient(ctrl)
+
+	registry := git.NewRegistry("github.com")
+	registry.Register(git.ProviderGitHub, "github.com", githubClient)
+	registry.Register(git.ProviderGitLab, "gitlab.com", gitlabClient)
+
+	client, err := registry.Client(domain.RepoMetadata{Host: "gitlab.com", Name: "owner/repo"})
+	require.NoError(t, err)
+	require.True(t, client == gitlabClient)
+
+	// repositories stored without a host were indexed from the default host
+	client, err = registry.Client(domain.RepoMetadata{Name: "owner/repo"})
+	require.NoError(t, err)
+	require.True(t, client == githubClient)
+
+	_, err = registry.Client(domain.RepoMetadata{Host: "gitea.example.com", Name: "owner/repo"})
+	require.Equal(t, message.ErrUnsupportedGitHost, err)
+}
+
+func TestRegistryRateLimits(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	registry := git.NewRegistry("github.com")
+	registry.Register(git.ProviderGitHub, "github.com", git.NewGitHubClient("https://api.github.com", []string{"token"}, time.Hour, nil))
+	registry.Register(git.ProviderGitLab, "gitlab.com", git_mocks.NewMockGitManagerClient(ctrl))
+
+	// only the clients reporting a rate limit are listed
+	rateLimits := registry.RateLimits()
+	require.Len(t, rateLimits, 1)
+	require.Equal(t, git.ProviderGitHub, rateLimits[0].Provider)
+	require.Equal(t, "github.com", rateLimits[0].Host)
+}
+
+func TestRegistryClientWithCredential(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer private-token" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Write([]byte(`{"name":"repo","full_name":"owner/repo","default_branch":"main"}`))
+	}))
+	defer server.Close()
+
+	credentials := repo_mocks.NewMockRepository(ctrl)
+	// the credential is read once and its client reused afterwards
+	credentials.EXPECT().CredentialByPublicId(gomock.Any(), "credential-id").
+		Return(&domain.Credential{PublicID: "credential-id", Token: "private-token"}, nil).Times(1)
+
+	registry := git.NewRegistry("github.com")
+	registry.Register(git.ProviderGitHub, "github.com", git.NewGitHubClient(server.URL, []string{"service-token"}, time.Hour, nil))
+	registry.SetCredentials(credentials)
+
+	publicClient, err := registry.Client(domain.RepoMetadata{Host: "github.com", Name: "owner/repo"})
+	require.NoError(t, err)
+	_, err = publicClient.FetchRepoMetadata(context.Background(), "owner/repo")
+	require.Equal(t, message.ErrRepositoryNotFound, err)
+
+	repo := domain.RepoMetadata{Host: "github.com", Name: "owner/repo", CredentialID: "credential-id"}
+	for i := 0; i < 2; i++ {
+		client, err := registry.Client(repo)
+		require.NoError(t, err)
+
+		metadata, err := client.FetchRepoMetadata(context.Background(), "owner/repo")
+		require.NoError(t, err)
+		require.Equal(t, "owner/repo", metadata.Name)
+	}
+}
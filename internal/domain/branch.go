@@ -0,0 +1,30 @@
+package domain
+
+import "time"
+
+// Branch is a branch of a repository, as listed by its git host or as tracked by the indexing
+type Branch struct {
+	Name    string
+	HeadSHA string
+	// IndexedAt is when the history up to HeadSHA was indexed, zero for branches listed by a git host
+	IndexedAt time.Time
+}
+
+// CommitFilter narrows a commit listing
+type CommitFilter struct {
+	// Branch lists only the commits contained in the branch
+	Branch string
+	// IncludeOrphaned lists the orphaned commits too, which are left out otherwise
+	IncludeOrphaned bool
+}
+
+// HistoryRewrite is a rewrite of the history of a branch, eg by a force-push, found when the previous head
+// of the branch was no longer reachable from its new head
+type HistoryRewrite struct {
+	Branch          string
+	PreviousHeadSHA string
+	HeadSHA         string
+	// OrphanedCommits counts the commits dropped by the rewrite which are left in no indexed branch
+	OrphanedCommits int64
+	DetectedAt      time.Time
+}
@@ -5,14 +5,49 @@ import (
 )
 
 type Commit struct {
-	CommitID       string
-	Message        string
-	Author         string
+	CommitID    string
+	Message     string
+	Author      string
+	AuthorEmail string
+	AuthorLogin string
+	// AuthorAvatarURL is the avatar of the host account of the author
+	AuthorAvatarURL string
+	// Date is when the commit was authored, which is kept apart from when it was committed, eg by a rebase
 	Date           time.Time
+	CommitterName  string
+	CommitterEmail string
+	CommitterLogin string
+	CommitterDate  time.Time
+	// ParentSHAs are the ids of the parent commits, a merge commit has more than one
+	ParentSHAs     []string
+	IsMerge        bool
 	URL            string
 	RepositoryName string
-	CreatedAt      time.Time
-	UpdatedAt      time.Time
+	RepositoryHost string
+	// Branches are the tracked branches containing the commit, only loaded with the commit's details
+	Branches []string
+	// PullRequest is the pull request which brought the commit in, nil when none was found
+	PullRequest  *PullRequest
+	Additions    int
+	Deletions    int
+	TotalChanges int
+	Files        []CommitFile
+	// EnrichedAt is when the change statistics and files were fetched, zero until then
+	EnrichedAt time.Time
+	// OrphanedAt is when the commit was found unreachable from every indexed branch, zero while it is reachable
+	OrphanedAt time.Time
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+}
+
+// CommitFile is a file changed by a commit
+type CommitFile struct {
+	Path      string
+	Status    string
+	Additions int
+	Deletions int
+	// PreviousPath is the path of a renamed file before the commit
+	PreviousPath string
 }
 
 type AuthorCommitCount struct {
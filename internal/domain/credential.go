@@ -0,0 +1,12 @@
+package domain
+
+import "time"
+
+// Credential is a token authenticating the requests of the repositories using it on their git host,
+// named credentials can be referenced by several repositories while unnamed ones belong to the repository added with them
+type Credential struct {
+	PublicID  string
+	Name      string
+	Token     string
+	CreatedAt time.Time
+}
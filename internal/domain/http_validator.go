@@ -0,0 +1,14 @@
+package domain
+
+import (
+	"time"
+)
+
+// HTTPValidator holds the cache validators a git host returned for an endpoint, they are sent back
+// on the next request to the endpoint so an unchanged response is answered with 304 Not Modified
+type HTTPValidator struct {
+	Endpoint     string
+	ETag         string
+	LastModified string
+	UpdatedAt    time.Time
+}
@@ -0,0 +1,41 @@
+package domain
+
+import "time"
+
+const (
+	// JobKindIndex indexes the history of the default branch of a repository after it is added
+	JobKindIndex = "index"
+	// JobKindMonitor fetches and reconciles the new commits of a repository, it is rescheduled every fetch interval
+	JobKindMonitor = "monitor"
+	// JobKindIndexBranches indexes the tracked branches of a repository after they changed
+	JobKindIndexBranches = "index_branches"
+	// JobKindReconcileBranches reconciles the default and tracked branches of a repository with their host, after a push
+	// whose webhook delivery did not carry all of its changes
+	JobKindReconcileBranches = "reconcile_branches"
+)
+
+const (
+	JobStatusPending = "pending"
+	JobStatusRunning = "running"
+	JobStatusDone    = "done"
+	// JobStatusDead is the status of the jobs which failed on all their attempts, they only run again once requeued
+	JobStatusDead = "dead"
+)
+
+// Job is a unit of background work on a repository, queued until a worker claims it
+type Job struct {
+	PublicID     string
+	Kind         string
+	RepositoryID string
+	Status       string
+	// Attempts counts the runs of the job, a job claimed again after its worker stopped counts one more
+	Attempts    int
+	MaxAttempts int
+	// RunAt is when a pending job can be claimed
+	RunAt time.Time
+	// LockedUntil is when a running job is claimable again by another worker, unless its worker extends it
+	LockedUntil time.Time
+	LastError   string
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
@@ -0,0 +1,34 @@
+package domain
+
+import "time"
+
+// States of a pull request, a merged pull request is closed on its host as well
+const (
+	PullRequestOpen   = "open"
+	PullRequestClosed = "closed"
+	PullRequestMerged = "merged"
+)
+
+// PullRequest is a pull request of a repository
+type PullRequest struct {
+	Number  int
+	Title   string
+	State   string
+	Author  string
+	URL     string
+	BaseRef string
+	HeadRef string
+	// MergeCommitSHA is the commit the pull request was merged, squashed or rebased into its base as, empty unless merged
+	MergeCommitSHA string
+	// CreatedAt and UpdatedAt are when the pull request was opened and last updated on its host
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	MergedAt  time.Time
+	ClosedAt  time.Time
+}
+
+// PullRequestFilter narrows a pull request listing
+type PullRequestFilter struct {
+	// State lists only the pull requests in the state, one of open, closed or merged
+	State string
+}
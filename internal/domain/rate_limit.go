@@ -0,0 +1,15 @@
+package domain
+
+import "time"
+
+// RateLimitStatus is the rate limit budget left on a git host, summed over the tokens used on it
+type RateLimitStatus struct {
+	Provider  string
+	Host      string
+	Limit     int
+	Remaining int
+	// Reset is when the earliest exhausted token is reset, or when the budget is next reset if none is exhausted
+	Reset time.Time
+	// Waiters is the number of requests waiting for a reset
+	Waiters int64
+}
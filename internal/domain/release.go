@@ -0,0 +1,27 @@
+package domain
+
+import "time"
+
+// Tag is a tag of a repository
+type Tag struct {
+	Name string
+	// TargetSHA is the commit the tag points to, annotated tags are peeled to their commit
+	TargetSHA string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// Release is a release published from a tag of a repository
+type Release struct {
+	TagName string
+	// TargetSHA is the commit of the tag of the release, empty while the tag of a draft does not exist yet
+	TargetSHA  string
+	Title      string
+	Body       string
+	Draft      bool
+	Prerelease bool
+	// PublishedAt is zero for drafts
+	PublishedAt time.Time
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
@@ -4,19 +4,64 @@ import (
 	"time"
 )
 
+// states of a repository on its git host, deleted and inaccessible repositories are no longer monitored
+const (
+	RepoStatusActive       = "active"
+	RepoStatusArchived     = "archived"
+	RepoStatusDeleted      = "deleted"
+	RepoStatusInaccessible = "inaccessible"
+)
+
 type RepoMetadata struct {
-	PublicID          string
-	Name              string
-	Description       string
-	URL               string
-	Language          string
+	PublicID      string
+	Provider      string
+	Host          string
+	Name          string
+	Description   string
+	URL           string
+	Language      string
+	DefaultBranch string
+	// TrackedBranches are the names or glob patterns, eg release/*, of the branches indexed besides the default one
+	TrackedBranches []string
+	// CredentialID is the public id of the credential authenticating the requests of the repository, empty for none
+	CredentialID      string
 	ForksCount        int
 	StarsCount        int
 	OpenIssuesCount   int
 	WatchersCount     int
+	Status            string
+	StatusChangedAt   time.Time
 	CreatedAt         time.Time
 	UpdatedAt         time.Time
 	LastFetchedCommit string
 	LastFetchedPage   int32
+	LastFetchedCursor string
 	IsFetching        bool
+	Progress          IndexingProgress
+}
+
+// IsGone reports whether the repository was deleted from its git host or is no longer accessible on it
+func (r RepoMetadata) IsGone() bool {
+	return r.Status == RepoStatusDeleted || r.Status == RepoStatusInaccessible
+}
+
+// IndexingProgress is the progress of the indexing of the commits of a repository, counted in pages
+type IndexingProgress struct {
+	Page       int32
+	TotalPages int32
+	// StartPage is the page the indexing started or resumed from, pages before it do not count towards the rate
+	StartPage         int32
+	StartedAt         time.Time
+	UpdatedAt         time.Time
+	FinishedAt        time.Time
+	EstimatedTimeLeft time.Duration
+}
+
+// RepoMetadataSnapshot is the popularity of a repository captured at a point in time
+type RepoMetadataSnapshot struct {
+	StarsCount      int
+	ForksCount      int
+	WatchersCount   int
+	OpenIssuesCount int
+	CapturedAt      time.Time
 }
@@ -0,0 +1,18 @@
+package domain
+
+// PushEvent is a push to a branch of a repository delivered by a git host webhook
+type PushEvent struct {
+	// RepositoryName is the full name of the repository, eg owner/repo
+	RepositoryName string
+	Branch         string
+	// Before is the head of the branch before the push, it is zeroed when the push created the branch
+	Before string
+	// Deleted is set when the push deleted the branch
+	Deleted bool
+	// Forced is set when the push rewrote the history of the branch, dropping the commits after the merge base
+	Forced bool
+	// Commits are the pushed commits, oldest first, git hosts deliver a limited number of them
+	Commits []Commit
+	// Truncated is set when more commits were pushed than the git host delivered
+	Truncated bool
+}
@@ -0,0 +1,63 @@
+package dtos
+
+import (
+	"time"
+
+	"github.com/kenmobility/git-api-service/internal/domain"
+)
+
+type BranchResponseDto struct {
+	Name      string    `json:"name"`
+	HeadSHA   string    `json:"head_sha"`
+	IndexedAt time.Time `json:"indexed_at"`
+}
+
+// AllBranchResponse maps array of dto response from array of branch domain objects
+func AllBranchResponse(branches []domain.Branch) []BranchResponseDto {
+	branchesResponse := make([]BranchResponseDto, 0, len(branches))
+
+	for _, b := range branches {
+		branchesResponse = append(branchesResponse, BranchResponseDto{
+			Name:      b.Name,
+			HeadSHA:   b.HeadSHA,
+			IndexedAt: b.IndexedAt,
+		})
+	}
+
+	return branchesResponse
+}
+
+type HistoryRewriteResponseDto struct {
+	Branch          string    `json:"branch"`
+	PreviousHeadSHA string    `json:"previous_head_sha"`
+	HeadSHA         string    `json:"head_sha"`
+	OrphanedCommits int64     `json:"orphaned_commits"`
+	DetectedAt      time.Time `json:"detected_at"`
+}
+
+type HistoryRewritesResponseDto struct {
+	Id       string                      `json:"id"`
+	Name     string                      `json:"name"`
+	Rewrites []HistoryRewriteResponseDto `json:"rewrites"`
+}
+
+// HistoryRewritesResponse maps the dto response of the repository and the rewrites found in the history of its branches
+func HistoryRewritesResponse(r domain.RepoMetadata, rewrites []domain.HistoryRewrite) HistoryRewritesResponseDto {
+	rewritesResponse := make([]HistoryRewriteResponseDto, 0, len(rewrites))
+
+	for _, h := range rewrites {
+		rewritesResponse = append(rewritesResponse, HistoryRewriteResponseDto{
+			Branch:          h.Branch,
+			PreviousHeadSHA: h.PreviousHeadSHA,
+			HeadSHA:         h.HeadSHA,
+			OrphanedCommits: h.OrphanedCommits,
+			DetectedAt:      h.DetectedAt,
+		})
+	}
+
+	return HistoryRewritesResponseDto{
+		Id:       r.PublicID,
+		Name:     r.Name,
+		Rewrites: rewritesResponse,
+	}
+}
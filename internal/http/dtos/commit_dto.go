@@ -12,14 +12,48 @@ type AllCommitResponse struct {
 }
 
 type CommitResponseDto struct {
-	CommitID   string    `json:"commit_id"`
-	Message    string    `json:"message"`
-	Author     string    `json:"author"`
-	Date       time.Time `json:"date"`
-	URL        string    `json:"url"`
-	Repository string    `json:"repository"`
-	CreatedAt  time.Time `json:"created_at"`
-	UpdatedAt  time.Time `json:"updated_at"`
+	CommitID        string    `json:"commit_id"`
+	Message         string    `json:"message"`
+	Author          string    `json:"author"`
+	AuthorEmail     string    `json:"author_email"`
+	AuthorLogin     string    `json:"author_login"`
+	AuthorAvatarURL string    `json:"author_avatar_url"`
+	Date            time.Time `json:"date"`
+	CommitterName   string    `json:"committer_name"`
+	CommitterEmail  string    `json:"committer_email"`
+	CommitterLogin  string    `json:"committer_login"`
+	CommitterDate   time.Time `json:"committer_date"`
+	ParentSHAs      []string  `json:"parent_shas"`
+	IsMerge         bool      `json:"is_merge"`
+	URL             string    `json:"url"`
+	Repository      string    `json:"repository"`
+	// PullRequest is the pull request which brought the commit in, null when none was found
+	PullRequest *PullRequestSummaryDto `json:"pull_request"`
+	// Orphaned tells the commit is no longer reachable from any indexed branch, eg after a force-push
+	Orphaned   bool       `json:"orphaned"`
+	OrphanedAt *time.Time `json:"orphaned_at,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+}
+
+// CommitDetailResponseDto is a commit with its change statistics and the files it changed, which are
+// empty until the commit is enriched
+type CommitDetailResponseDto struct {
+	CommitResponseDto
+	Branches     []string                `json:"branches"`
+	Additions    int                     `json:"additions"`
+	Deletions    int                     `json:"deletions"`
+	TotalChanges int                     `json:"total_changes"`
+	Files        []CommitFileResponseDto `json:"files"`
+	EnrichedAt   *time.Time              `json:"enriched_at"`
+}
+
+type CommitFileResponseDto struct {
+	Path             string `json:"path"`
+	Status           string `json:"status"`
+	Additions        int    `json:"additions"`
+	Deletions        int    `json:"deletions"`
+	PreviousFilename string `json:"previous_filename,omitempty"`
 }
 
 // AuthorCommitCountDto holds the result with author and count of commits
@@ -59,14 +93,26 @@ func AllAuthorCommitCountResponse(authors []domain.AuthorCommitCount) []AuthorCo
 // CommitResponse is a mapper of dto commit response from a commit domain entity
 func CommitResponse(c domain.Commit) CommitResponseDto {
 	return CommitResponseDto{
-		CommitID:   c.CommitID,
-		Message:    c.Message,
-		Author:     c.Author,
-		Date:       c.Date,
-		URL:        c.URL,
-		Repository: c.RepositoryName,
-		CreatedAt:  c.CreatedAt,
-		UpdatedAt:  c.UpdatedAt,
+		CommitID:        c.CommitID,
+		Message:         c.Message,
+		Author:          c.Author,
+		AuthorEmail:     c.AuthorEmail,
+		AuthorLogin:     c.AuthorLogin,
+		AuthorAvatarURL: c.AuthorAvatarURL,
+		Date:            c.Date,
+		CommitterName:   c.CommitterName,
+		CommitterEmail:  c.CommitterEmail,
+		CommitterLogin:  c.CommitterLogin,
+		CommitterDate:   c.CommitterDate,
+		ParentSHAs:      stringsOrEmpty(c.ParentSHAs),
+		IsMerge:         c.IsMerge,
+		URL:             c.URL,
+		Repository:      c.RepositoryName,
+		PullRequest:     PullRequestSummary(c.PullRequest),
+		Orphaned:        !c.OrphanedAt.IsZero(),
+		OrphanedAt:      optionalTime(c.OrphanedAt),
+		CreatedAt:       c.CreatedAt,
+		UpdatedAt:       c.UpdatedAt,
 	}
 }
 
@@ -79,19 +125,32 @@ func CommitsResponse(commits []domain.Commit) []CommitResponseDto {
 	commitsResponse := make([]CommitResponseDto, 0, len(commits))
 
 	for _, c := range commits {
-		cr := CommitResponseDto{
-			CommitID:   c.CommitID,
-			Message:    c.Message,
-			Author:     c.Author,
-			Date:       c.Date,
-			URL:        c.URL,
-			Repository: c.RepositoryName,
-			CreatedAt:  c.CreatedAt,
-			UpdatedAt:  c.UpdatedAt,
-		}
-
-		commitsResponse = append(commitsResponse, cr)
+		commitsResponse = append(commitsResponse, CommitResponse(c))
 	}
 
 	return commitsResponse
 }
+
+// CommitDetailResponse is a mapper of dto commit detail response from a commit domain entity
+func CommitDetailResponse(c domain.Commit) CommitDetailResponseDto {
+	files := make([]CommitFileResponseDto, 0, len(c.Files))
+	for _, f := range c.Files {
+		files = append(files, CommitFileResponseDto{
+			Path:             f.Path,
+			Status:           f.Status,
+			Additions:        f.Additions,
+			Deletions:        f.Deletions,
+			PreviousFilename: f.PreviousPath,
+		})
+	}
+
+	return CommitDetailResponseDto{
+		CommitResponseDto: CommitResponse(c),
+		Branches:          stringsOrEmpty(c.Branches),
+		Additions:         c.Additions,
+		Deletions:         c.Deletions,
+		TotalChanges:      c.TotalChanges,
+		Files:             files,
+		EnrichedAt:        optionalTime(c.EnrichedAt),
+	}
+}
@@ -0,0 +1,38 @@
+package dtos
+
+import (
+	"time"
+
+	"github.com/kenmobility/git-api-service/internal/domain"
+)
+
+type AddCredentialRequestDto struct {
+	Name  string `json:"name" validate:"required"`
+	Token string `json:"token" validate:"required"`
+}
+
+// CredentialResponseDto describes a stored credential, its token is never returned
+type CredentialResponseDto struct {
+	Id        string `json:"id"`
+	Name      string `json:"name"`
+	CreatedAt string `json:"created_at"`
+}
+
+func CredentialResponse(c domain.Credential) CredentialResponseDto {
+	return CredentialResponseDto{
+		Id:        c.PublicID,
+		Name:      c.Name,
+		CreatedAt: c.CreatedAt.Format(time.RFC850),
+	}
+}
+
+// AllCredentialResponse maps array of dto response from array of credential domain objects
+func AllCredentialResponse(credentials []domain.Credential) []CredentialResponseDto {
+	credentialsResponse := make([]CredentialResponseDto, 0, len(credentials))
+
+	for _, c := range credentials {
+		credentialsResponse = append(credentialsResponse, CredentialResponse(c))
+	}
+
+	return credentialsResponse
+}
@@ -0,0 +1,58 @@
+package dtos
+
+import (
+	"time"
+
+	"github.com/kenmobility/git-api-service/internal/domain"
+)
+
+type AllJobResponse struct {
+	Jobs     []JobResponseDto `json:"jobs"`
+	PageInfo PagingInfoDto    `json:"page_info"`
+}
+
+type JobResponseDto struct {
+	Id           string    `json:"id"`
+	Kind         string    `json:"kind"`
+	RepositoryId string    `json:"repository_id"`
+	Status       string    `json:"status"`
+	Attempts     int       `json:"attempts"`
+	MaxAttempts  int       `json:"max_attempts"`
+	RunAt        time.Time `json:"run_at"`
+	// LockedUntil is when a running job is claimed again unless its worker extends it
+	LockedUntil *time.Time `json:"locked_until,omitempty"`
+	LastError   string     `json:"last_error,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+}
+
+// JobResponse is a mapper of dto job response from a job domain entity
+func JobResponse(j domain.Job) JobResponseDto {
+	jobResponse := JobResponseDto{
+		Id:           j.PublicID,
+		Kind:         j.Kind,
+		RepositoryId: j.RepositoryID,
+		Status:       j.Status,
+		Attempts:     j.Attempts,
+		MaxAttempts:  j.MaxAttempts,
+		RunAt:        j.RunAt,
+		LastError:    j.LastError,
+		CreatedAt:    j.CreatedAt,
+		UpdatedAt:    j.UpdatedAt,
+	}
+	if j.Status == domain.JobStatusRunning {
+		jobResponse.LockedUntil = optionalTime(j.LockedUntil)
+	}
+	return jobResponse
+}
+
+// JobsResponse is a mapper of job response dto from an array of job domain entity
+func JobsResponse(jobs []domain.Job) []JobResponseDto {
+	jobsResponse := make([]JobResponseDto, 0, len(jobs))
+
+	for _, j := range jobs {
+		jobsResponse = append(jobsResponse, JobResponse(j))
+	}
+
+	return jobsResponse
+}
@@ -0,0 +1,54 @@
+package dtos
+
+import (
+	"time"
+
+	"github.com/kenmobility/git-api-service/internal/domain"
+)
+
+type IndexingProgressResponseDto struct {
+	Id         string  `json:"id"`
+	Name       string  `json:"name"`
+	IsFetching bool    `json:"is_fetching"`
+	Page       int32   `json:"current_page"`
+	TotalPages int32   `json:"total_pages"`
+	Percent    float64 `json:"percent"`
+	// EstimatedSecondsLeft is nil while the number of pages is unknown
+	EstimatedSecondsLeft *int64     `json:"estimated_seconds_left"`
+	StartedAt            *time.Time `json:"started_at"`
+	UpdatedAt            *time.Time `json:"updated_at"`
+	FinishedAt           *time.Time `json:"finished_at"`
+}
+
+// IndexingProgressResponse maps the indexing progress of the repository to its dto response
+func IndexingProgressResponse(r domain.RepoMetadata) IndexingProgressResponseDto {
+	p := r.Progress
+
+	progressDto := IndexingProgressResponseDto{
+		Id:         r.PublicID,
+		Name:       r.Name,
+		IsFetching: r.IsFetching,
+		Page:       p.Page,
+		TotalPages: p.TotalPages,
+		StartedAt:  optionalTime(p.StartedAt),
+		UpdatedAt:  optionalTime(p.UpdatedAt),
+		FinishedAt: optionalTime(p.FinishedAt),
+	}
+
+	if p.TotalPages > 0 {
+		progressDto.Percent = float64(p.Page) / float64(p.TotalPages) * 100
+
+		secondsLeft := int64(p.EstimatedTimeLeft.Round(time.Second) / time.Second)
+		progressDto.EstimatedSecondsLeft = &secondsLeft
+	}
+
+	return progressDto
+}
+
+// optionalTime returns nil for the zero time, which stands for a time not reached yet
+func optionalTime(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	return &t
+}
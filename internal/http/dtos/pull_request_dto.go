@@ -0,0 +1,75 @@
+package dtos
+
+import (
+	"time"
+
+	"github.com/kenmobility/git-api-service/internal/domain"
+)
+
+type AllPullRequestResponse struct {
+	PullRequests []PullRequestResponseDto `json:"pull_requests"`
+	PageInfo     PagingInfoDto            `json:"page_info"`
+}
+
+type PullRequestResponseDto struct {
+	Number         int        `json:"number"`
+	Title          string     `json:"title"`
+	State          string     `json:"state"`
+	Author         string     `json:"author"`
+	URL            string     `json:"url"`
+	BaseRef        string     `json:"base_ref"`
+	HeadRef        string     `json:"head_ref"`
+	MergeCommitSHA string     `json:"merge_commit_sha"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+	MergedAt       *time.Time `json:"merged_at"`
+	ClosedAt       *time.Time `json:"closed_at"`
+}
+
+// PullRequestSummaryDto identifies the pull request of a commit
+type PullRequestSummaryDto struct {
+	Number   int        `json:"number"`
+	Title    string     `json:"title"`
+	State    string     `json:"state"`
+	URL      string     `json:"url"`
+	MergedAt *time.Time `json:"merged_at"`
+}
+
+// PullRequestsResponse is a mapper of pull request response dto from an array of pull request domain entity
+func PullRequestsResponse(pullRequests []domain.PullRequest) []PullRequestResponseDto {
+	pullRequestsResponse := make([]PullRequestResponseDto, 0, len(pullRequests))
+
+	for _, p := range pullRequests {
+		pullRequestsResponse = append(pullRequestsResponse, PullRequestResponseDto{
+			Number:         p.Number,
+			Title:          p.Title,
+			State:          p.State,
+			Author:         p.Author,
+			URL:            p.URL,
+			BaseRef:        p.BaseRef,
+			HeadRef:        p.HeadRef,
+			MergeCommitSHA: p.MergeCommitSHA,
+			CreatedAt:      p.CreatedAt,
+			UpdatedAt:      p.UpdatedAt,
+			MergedAt:       optionalTime(p.MergedAt),
+			ClosedAt:       optionalTime(p.ClosedAt),
+		})
+	}
+
+	return pullRequestsResponse
+}
+
+// PullRequestSummary is a mapper of pull request summary dto from a pull request domain entity, nil when there is none
+func PullRequestSummary(p *domain.PullRequest) *PullRequestSummaryDto {
+	if p == nil {
+		return nil
+	}
+
+	return &PullRequestSummaryDto{
+		Number:   p.Number,
+		Title:    p.Title,
+		State:    p.State,
+		URL:      p.URL,
+		MergedAt: optionalTime(p.MergedAt),
+	}
+}
@@ -0,0 +1,41 @@
+package dtos
+
+import (
+	"time"
+
+	"github.com/kenmobility/git-api-service/internal/domain"
+)
+
+type RateLimitResponseDto struct {
+	Provider  string     `json:"provider"`
+	Host      string     `json:"host"`
+	Limit     int        `json:"limit"`
+	Remaining int        `json:"remaining"`
+	Reset     *time.Time `json:"reset"`
+	Waiters   int64      `json:"queued_waiters"`
+}
+
+// AllRateLimitResponse maps array of dto response from array of rate limit status domain objects
+func AllRateLimitResponse(statuses []domain.RateLimitStatus) []RateLimitResponseDto {
+	rlResponse := make([]RateLimitResponseDto, 0, len(statuses))
+
+	for _, s := range statuses {
+		rlDto := RateLimitResponseDto{
+			Provider:  s.Provider,
+			Host:      s.Host,
+			Limit:     s.Limit,
+			Remaining: s.Remaining,
+			Waiters:   s.Waiters,
+		}
+
+		// the reset time is unknown until the host served a request
+		if !s.Reset.IsZero() {
+			reset := s.Reset
+			rlDto.Reset = &reset
+		}
+
+		rlResponse = append(rlResponse, rlDto)
+	}
+
+	return rlResponse
+}
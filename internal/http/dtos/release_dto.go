@@ -0,0 +1,73 @@
+package dtos
+
+import (
+	"time"
+
+	"github.com/kenmobility/git-api-service/internal/domain"
+)
+
+type AllTagResponse struct {
+	Tags     []TagResponseDto `json:"tags"`
+	PageInfo PagingInfoDto    `json:"page_info"`
+}
+
+type TagResponseDto struct {
+	Name      string    `json:"name"`
+	TargetSHA string    `json:"target_sha"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type AllReleaseResponse struct {
+	Releases []ReleaseResponseDto `json:"releases"`
+	PageInfo PagingInfoDto        `json:"page_info"`
+}
+
+type ReleaseResponseDto struct {
+	TagName     string     `json:"tag_name"`
+	TargetSHA   string     `json:"target_sha"`
+	Title       string     `json:"title"`
+	Body        string     `json:"body"`
+	Draft       bool       `json:"draft"`
+	Prerelease  bool       `json:"prerelease"`
+	PublishedAt *time.Time `json:"published_at"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+}
+
+// TagsResponse is a mapper of tag response dto from an array of tag domain entity
+func TagsResponse(tags []domain.Tag) []TagResponseDto {
+	tagsResponse := make([]TagResponseDto, 0, len(tags))
+
+	for _, t := range tags {
+		tagsResponse = append(tagsResponse, TagResponseDto{
+			Name:      t.Name,
+			TargetSHA: t.TargetSHA,
+			CreatedAt: t.CreatedAt,
+			UpdatedAt: t.UpdatedAt,
+		})
+	}
+
+	return tagsResponse
+}
+
+// ReleasesResponse is a mapper of release response dto from an array of release domain entity
+func ReleasesResponse(releases []domain.Release) []ReleaseResponseDto {
+	releasesResponse := make([]ReleaseResponseDto, 0, len(releases))
+
+	for _, r := range releases {
+		releasesResponse = append(releasesResponse, ReleaseResponseDto{
+			TagName:     r.TagName,
+			TargetSHA:   r.TargetSHA,
+			Title:       r.Title,
+			Body:        r.Body,
+			Draft:       r.Draft,
+			Prerelease:  r.Prerelease,
+			PublishedAt: optionalTime(r.PublishedAt),
+			CreatedAt:   r.CreatedAt,
+			UpdatedAt:   r.UpdatedAt,
+		})
+	}
+
+	return releasesResponse
+}
@@ -7,34 +7,59 @@ import (
 )
 
 type AddRepositoryRequestDto struct {
+	// Name is owner/repo for the default host, a host-qualified name eg gitlab.example.com/group/repo, or a clone url
 	Name string `json:"name" validate:"required"`
+	// Branches are the names or glob patterns, eg release/*, of the branches to index besides the default one
+	Branches []string `json:"branches"`
+	// Credential is the name of a stored credential authenticating the requests of the repository
+	Credential string `json:"credential"`
+	// Token authenticates the requests of the repository instead of a stored credential, it is stored encrypted
+	Token string `json:"token"`
+}
+
+type UpdateTrackedBranchesRequestDto struct {
+	Branches []string `json:"branches"`
 }
 
 type GitRepoMetadataResponseDto struct {
-	Id              string `json:"id"`
-	Name            string `json:"name"`
-	Description     string `json:"description"`
-	URL             string `json:"url"`
-	Language        string `json:"language"`
-	ForksCount      int    `json:"forks_count"`
-	StarsCount      int    `json:"stars_count"`
-	OpenIssuesCount int    `json:"open_issues_count"`
-	WatchersCount   int    `json:"watchers_count"`
-	CreatedAt       string `json:"added_at"`
-	UpdatedAt       string `json:"last_updated_at"`
+	Id              string   `json:"id"`
+	Provider        string   `json:"provider"`
+	Host            string   `json:"host"`
+	Name            string   `json:"name"`
+	Description     string   `json:"description"`
+	URL             string   `json:"url"`
+	Language        string   `json:"language"`
+	DefaultBranch   string   `json:"default_branch"`
+	TrackedBranches []string `json:"tracked_branches"`
+	HasCredential   bool     `json:"has_credential"`
+	ForksCount      int      `json:"forks_count"`
+	StarsCount      int      `json:"stars_count"`
+	OpenIssuesCount int      `json:"open_issues_count"`
+	WatchersCount   int      `json:"watchers_count"`
+	Status          string   `json:"status"`
+	StatusChangedAt string   `json:"status_changed_at,omitempty"`
+	CreatedAt       string   `json:"added_at"`
+	UpdatedAt       string   `json:"last_updated_at"`
 }
 
 func RepoMetadataResponse(r domain.RepoMetadata) GitRepoMetadataResponseDto {
 	return GitRepoMetadataResponseDto{
 		Id:              r.PublicID,
+		Provider:        r.Provider,
+		Host:            r.Host,
 		Name:            r.Name,
 		Description:     r.Description,
 		URL:             r.URL,
 		Language:        r.Language,
+		DefaultBranch:   r.DefaultBranch,
+		TrackedBranches: stringsOrEmpty(r.TrackedBranches),
+		HasCredential:   r.CredentialID != "",
 		ForksCount:      r.ForksCount,
 		StarsCount:      r.StarsCount,
 		OpenIssuesCount: r.OpenIssuesCount,
 		WatchersCount:   r.WatchersCount,
+		Status:          r.Status,
+		StatusChangedAt: formatOptionalTime(r.StatusChangedAt),
 		CreatedAt:       r.CreatedAt.Format(time.RFC850),
 		UpdatedAt:       r.UpdatedAt.Format(time.RFC850),
 	}
@@ -50,14 +75,21 @@ func AllRepoMetadataResponse(repos []domain.RepoMetadata) []GitRepoMetadataRespo
 	for _, r := range repos {
 		rr := GitRepoMetadataResponseDto{
 			Id:              r.PublicID,
+			Provider:        r.Provider,
+			Host:            r.Host,
 			Name:            r.Name,
 			Description:     r.Description,
 			URL:             r.URL,
 			Language:        r.Language,
+			DefaultBranch:   r.DefaultBranch,
+			TrackedBranches: stringsOrEmpty(r.TrackedBranches),
+			HasCredential:   r.CredentialID != "",
 			ForksCount:      r.ForksCount,
 			StarsCount:      r.StarsCount,
 			OpenIssuesCount: r.OpenIssuesCount,
 			WatchersCount:   r.WatchersCount,
+			Status:          r.Status,
+			StatusChangedAt: formatOptionalTime(r.StatusChangedAt),
 			CreatedAt:       r.CreatedAt.Format(time.RFC850),
 			UpdatedAt:       r.UpdatedAt.Format(time.RFC850),
 		}
@@ -67,3 +99,54 @@ func AllRepoMetadataResponse(repos []domain.RepoMetadata) []GitRepoMetadataRespo
 
 	return reposResponse
 }
+
+type MetadataSnapshotResponseDto struct {
+	StarsCount      int       `json:"stars_count"`
+	ForksCount      int       `json:"forks_count"`
+	WatchersCount   int       `json:"watchers_count"`
+	OpenIssuesCount int       `json:"open_issues_count"`
+	CapturedAt      time.Time `json:"captured_at"`
+}
+
+type MetadataHistoryResponseDto struct {
+	Id        string                        `json:"id"`
+	Name      string                        `json:"name"`
+	Snapshots []MetadataSnapshotResponseDto `json:"snapshots"`
+}
+
+// MetadataHistoryResponse maps the dto response of the repository and its metadata snapshots
+func MetadataHistoryResponse(r domain.RepoMetadata, snapshots []domain.RepoMetadataSnapshot) MetadataHistoryResponseDto {
+	snapshotsResponse := make([]MetadataSnapshotResponseDto, 0, len(snapshots))
+
+	for _, s := range snapshots {
+		snapshotsResponse = append(snapshotsResponse, MetadataSnapshotResponseDto{
+			StarsCount:      s.StarsCount,
+			ForksCount:      s.ForksCount,
+			WatchersCount:   s.WatchersCount,
+			OpenIssuesCount: s.OpenIssuesCount,
+			CapturedAt:      s.CapturedAt,
+		})
+	}
+
+	return MetadataHistoryResponseDto{
+		Id:        r.PublicID,
+		Name:      r.Name,
+		Snapshots: snapshotsResponse,
+	}
+}
+
+// formatOptionalTime formats the time, a zero time is left empty so it is omitted
+func formatOptionalTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format(time.RFC850)
+}
+
+// stringsOrEmpty returns an empty list for nil, so it is encoded as [] rather than null
+func stringsOrEmpty(values []string) []string {
+	if values == nil {
+		return []string{}
+	}
+	return values
+}
@@ -5,6 +5,7 @@ import (
 	"net/http"
 
 	"github.com/gin-gonic/gin"
+	"github.com/kenmobility/git-api-service/internal/domain"
 	"github.com/kenmobility/git-api-service/internal/http/dtos"
 	"github.com/kenmobility/git-api-service/internal/usecases"
 	"github.com/kenmobility/git-api-service/pkg/message"
@@ -31,7 +32,15 @@ func (ch CommitHandlers) GetCommitsByRepositoryId(ctx *gin.Context) {
 		return
 	}
 
-	repoName, commits, pagingInfo, err := ch.manageGitCommitUsecase.GetAllCommitsByRepository(ctx, repositoryId, dtos.PagingDataFromPagingDto(query))
+	includeOrphaned, err := getBoolQuery(ctx, "include_orphaned")
+	if err != nil {
+		response.Failure(ctx, http.StatusBadRequest, "include_orphaned must be true or false", err.Error())
+		return
+	}
+
+	filter := domain.CommitFilter{Branch: ctx.Query("branch"), IncludeOrphaned: includeOrphaned}
+
+	repoName, commits, pagingInfo, err := ch.manageGitCommitUsecase.GetAllCommitsByRepository(ctx, repositoryId, filter, dtos.PagingDataFromPagingDto(query))
 	if err != nil {
 		if err == message.ErrNoRecordFound {
 			response.Failure(ctx, http.StatusBadRequest, message.ErrInvalidRepositoryId.Error(), message.ErrInvalidRepositoryId.Error())
@@ -83,3 +92,29 @@ func (ch CommitHandlers) GetTopCommitAuthors(ctx *gin.Context) {
 
 	response.Success(ctx, http.StatusOK, msg, dtos.AllAuthorCommitCountResponse(authors))
 }
+
+func (ch CommitHandlers) GetCommit(ctx *gin.Context) {
+	repositoryId := ctx.Param("repoId")
+	commitId := ctx.Param("commitId")
+
+	if repositoryId == "" || commitId == "" {
+		response.Failure(ctx, http.StatusBadRequest, "repoId and commitId are required", nil)
+		return
+	}
+
+	commit, err := ch.manageGitCommitUsecase.GetCommit(ctx, repositoryId, commitId)
+	if err != nil {
+		if err == message.ErrNoRecordFound {
+			response.Failure(ctx, http.StatusBadRequest, message.ErrInvalidRepositoryId.Error(), message.ErrInvalidRepositoryId.Error())
+			return
+		}
+		if err == message.ErrInvalidCommitId {
+			response.Failure(ctx, http.StatusNotFound, err.Error(), err.Error())
+			return
+		}
+		response.Failure(ctx, http.StatusInternalServerError, err.Error(), err.Error())
+		return
+	}
+
+	response.Success(ctx, http.StatusOK, "commit fetched successfully", dtos.CommitDetailResponse(*commit))
+}
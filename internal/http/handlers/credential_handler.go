@@ -0,0 +1,64 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/kenmobility/git-api-service/internal/http/dtos"
+	"github.com/kenmobility/git-api-service/internal/usecases"
+	"github.com/kenmobility/git-api-service/pkg/helpers"
+	"github.com/kenmobility/git-api-service/pkg/message"
+	"github.com/kenmobility/git-api-service/pkg/response"
+)
+
+type CredentialHandlers struct {
+	manageCredentialUsecase usecases.ManageCredentialUsecase
+}
+
+func NewCredentialHandler(manageCredentialUsecase usecases.ManageCredentialUsecase) *CredentialHandlers {
+	return &CredentialHandlers{
+		manageCredentialUsecase: manageCredentialUsecase,
+	}
+}
+
+func (ch CredentialHandlers) AddCredential(ctx *gin.Context) {
+	var input dtos.AddCredentialRequestDto
+
+	err := ctx.BindJSON(&input)
+	if err != nil {
+		response.Failure(ctx, http.StatusBadRequest, "invalid input", err)
+		return
+	}
+
+	inputErrors := helpers.ValidateInput(input)
+	if inputErrors != nil {
+		response.Failure(ctx, http.StatusBadRequest, message.ErrInvalidInput.Error(), inputErrors)
+		return
+	}
+
+	credential, err := ch.manageCredentialUsecase.AddCredential(ctx, input.Name, input.Token)
+	if err != nil {
+		if err == message.ErrCredentialsNotConfigured {
+			response.Failure(ctx, http.StatusServiceUnavailable, err.Error(), err.Error())
+			return
+		}
+		if err == message.ErrCredentialAlreadyAdded {
+			response.Failure(ctx, http.StatusBadRequest, err.Error(), err.Error())
+			return
+		}
+		response.Failure(ctx, http.StatusInternalServerError, err.Error(), err.Error())
+		return
+	}
+
+	response.Success(ctx, http.StatusCreated, "credential successfully stored", dtos.CredentialResponse(*credential))
+}
+
+func (ch CredentialHandlers) FetchAllCredentials(ctx *gin.Context) {
+	credentials, err := ch.manageCredentialUsecase.GetAll(ctx)
+	if err != nil {
+		response.Failure(ctx, http.StatusInternalServerError, err.Error(), err.Error())
+		return
+	}
+
+	response.Success(ctx, http.StatusOK, "successfully fetched credentials", dtos.AllCredentialResponse(credentials))
+}
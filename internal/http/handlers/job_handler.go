@@ -0,0 +1,66 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/kenmobility/git-api-service/internal/http/dtos"
+	"github.com/kenmobility/git-api-service/internal/usecases"
+	"github.com/kenmobility/git-api-service/pkg/message"
+	"github.com/kenmobility/git-api-service/pkg/response"
+)
+
+type JobHandlers struct {
+	manageJobUsecase usecases.ManageJobUsecase
+}
+
+func NewJobHandler(manageJobUsecase usecases.ManageJobUsecase) *JobHandlers {
+	return &JobHandlers{
+		manageJobUsecase: manageJobUsecase,
+	}
+}
+
+func (jh JobHandlers) FetchAllJobs(ctx *gin.Context) {
+	query := getPagingInfo(ctx)
+
+	jobs, pagingInfo, err := jh.manageJobUsecase.GetAllJobs(ctx, ctx.Query("status"), dtos.PagingDataFromPagingDto(query))
+	if err != nil {
+		if err == message.ErrInvalidJobStatus {
+			response.Failure(ctx, http.StatusBadRequest, err.Error(), err.Error())
+			return
+		}
+		response.Failure(ctx, http.StatusInternalServerError, err.Error(), err.Error())
+		return
+	}
+
+	jobsResp := dtos.AllJobResponse{
+		Jobs:     dtos.JobsResponse(jobs),
+		PageInfo: dtos.PagingInfoResponse(*pagingInfo),
+	}
+
+	response.Success(ctx, http.StatusOK, "successfully fetched jobs", jobsResp)
+}
+
+func (jh JobHandlers) RequeueJob(ctx *gin.Context) {
+	jobId := ctx.Param("jobId")
+	if jobId == "" {
+		response.Failure(ctx, http.StatusBadRequest, "jobId is required", nil)
+		return
+	}
+
+	job, err := jh.manageJobUsecase.RequeueJob(ctx, jobId)
+	if err != nil {
+		if err == message.ErrNoRecordFound {
+			response.Failure(ctx, http.StatusNotFound, "job not found", err.Error())
+			return
+		}
+		if err == message.ErrJobNotDead || err == message.ErrJobAlreadyQueued {
+			response.Failure(ctx, http.StatusBadRequest, err.Error(), err.Error())
+			return
+		}
+		response.Failure(ctx, http.StatusInternalServerError, err.Error(), err.Error())
+		return
+	}
+
+	response.Success(ctx, http.StatusOK, "job successfully requeued", dtos.JobResponse(*job))
+}
@@ -2,6 +2,7 @@ package handlers
 
 import (
 	"strconv"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/kenmobility/git-api-service/internal/http/dtos"
@@ -22,3 +23,21 @@ func getPagingInfo(c *gin.Context) dtos.APIPagingDto {
 
 	return paging
 }
+
+// getTimeQuery parses the RFC3339 time of the query parameter, the zero time when it is not given
+func getTimeQuery(c *gin.Context, key string) (time.Time, error) {
+	value := c.Query(key)
+	if value == "" {
+		return time.Time{}, nil
+	}
+	return time.Parse(time.RFC3339, value)
+}
+
+// getBoolQuery parses the boolean query parameter, false when it is not given
+func getBoolQuery(c *gin.Context, key string) (bool, error) {
+	value := c.Query(key)
+	if value == "" {
+		return false, nil
+	}
+	return strconv.ParseBool(value)
+}
@@ -0,0 +1,63 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/kenmobility/git-api-service/internal/domain"
+	"github.com/kenmobility/git-api-service/internal/http/dtos"
+	"github.com/kenmobility/git-api-service/internal/usecases"
+	"github.com/kenmobility/git-api-service/pkg/message"
+	"github.com/kenmobility/git-api-service/pkg/response"
+)
+
+type PullRequestHandlers struct {
+	managePullRequestUsecase usecases.ManagePullRequestUsecase
+}
+
+func NewPullRequestHandler(managePullRequestUsecase usecases.ManagePullRequestUsecase) *PullRequestHandlers {
+	return &PullRequestHandlers{
+		managePullRequestUsecase: managePullRequestUsecase,
+	}
+}
+
+func (ph PullRequestHandlers) GetPullRequestsByRepositoryId(ctx *gin.Context) {
+	query := getPagingInfo(ctx)
+
+	repositoryId := ctx.Param("repoId")
+
+	if repositoryId == "" {
+		response.Failure(ctx, http.StatusBadRequest, "repoId is required", nil)
+		return
+	}
+
+	repoName, pullRequests, pagingInfo, err := ph.managePullRequestUsecase.GetPullRequestsByRepository(ctx, repositoryId, domain.PullRequestFilter{State: ctx.Query("state")}, dtos.PagingDataFromPagingDto(query))
+	if err != nil {
+		if err == message.ErrNoRecordFound {
+			response.Failure(ctx, http.StatusBadRequest, message.ErrInvalidRepositoryId.Error(), message.ErrInvalidRepositoryId.Error())
+			return
+		}
+		if err == message.ErrInvalidPullRequestState {
+			response.Failure(ctx, http.StatusBadRequest, err.Error(), err.Error())
+			return
+		}
+		response.Failure(ctx, http.StatusInternalServerError, err.Error(), err.Error())
+		return
+	}
+
+	if len(pullRequests) == 0 {
+		msg := fmt.Sprintf("No pull requests fetched yet for %s repository...", *repoName)
+		response.Success(ctx, http.StatusOK, msg, pullRequests)
+		return
+	}
+
+	pullRequestsResp := dtos.AllPullRequestResponse{
+		PullRequests: dtos.PullRequestsResponse(pullRequests),
+		PageInfo:     dtos.PagingInfoResponse(*pagingInfo),
+	}
+
+	msg := fmt.Sprintf("%s repository pull requests fetched successfully", *repoName)
+
+	response.Success(ctx, http.StatusOK, msg, pullRequestsResp)
+}
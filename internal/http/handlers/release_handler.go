@@ -0,0 +1,94 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/kenmobility/git-api-service/internal/http/dtos"
+	"github.com/kenmobility/git-api-service/internal/usecases"
+	"github.com/kenmobility/git-api-service/pkg/message"
+	"github.com/kenmobility/git-api-service/pkg/response"
+)
+
+type ReleaseHandlers struct {
+	manageReleaseUsecase usecases.ManageReleaseUsecase
+}
+
+func NewReleaseHandler(manageReleaseUsecase usecases.ManageReleaseUsecase) *ReleaseHandlers {
+	return &ReleaseHandlers{
+		manageReleaseUsecase: manageReleaseUsecase,
+	}
+}
+
+func (rh ReleaseHandlers) GetTagsByRepositoryId(ctx *gin.Context) {
+	query := getPagingInfo(ctx)
+
+	repositoryId := ctx.Param("repoId")
+
+	if repositoryId == "" {
+		response.Failure(ctx, http.StatusBadRequest, "repoId is required", nil)
+		return
+	}
+
+	repoName, tags, pagingInfo, err := rh.manageReleaseUsecase.GetTagsByRepository(ctx, repositoryId, dtos.PagingDataFromPagingDto(query))
+	if err != nil {
+		if err == message.ErrNoRecordFound {
+			response.Failure(ctx, http.StatusBadRequest, message.ErrInvalidRepositoryId.Error(), message.ErrInvalidRepositoryId.Error())
+			return
+		}
+		response.Failure(ctx, http.StatusInternalServerError, err.Error(), err.Error())
+		return
+	}
+
+	if len(tags) == 0 {
+		msg := fmt.Sprintf("No tags fetched yet for %s repository...", *repoName)
+		response.Success(ctx, http.StatusOK, msg, tags)
+		return
+	}
+
+	tagsResp := dtos.AllTagResponse{
+		Tags:     dtos.TagsResponse(tags),
+		PageInfo: dtos.PagingInfoResponse(*pagingInfo),
+	}
+
+	msg := fmt.Sprintf("%s repository tags fetched successfully", *repoName)
+
+	response.Success(ctx, http.StatusOK, msg, tagsResp)
+}
+
+func (rh ReleaseHandlers) GetReleasesByRepositoryId(ctx *gin.Context) {
+	query := getPagingInfo(ctx)
+
+	repositoryId := ctx.Param("repoId")
+
+	if repositoryId == "" {
+		response.Failure(ctx, http.StatusBadRequest, "repoId is required", nil)
+		return
+	}
+
+	repoName, releases, pagingInfo, err := rh.manageReleaseUsecase.GetReleasesByRepository(ctx, repositoryId, dtos.PagingDataFromPagingDto(query))
+	if err != nil {
+		if err == message.ErrNoRecordFound {
+			response.Failure(ctx, http.StatusBadRequest, message.ErrInvalidRepositoryId.Error(), message.ErrInvalidRepositoryId.Error())
+			return
+		}
+		response.Failure(ctx, http.StatusInternalServerError, err.Error(), err.Error())
+		return
+	}
+
+	if len(releases) == 0 {
+		msg := fmt.Sprintf("No releases fetched yet for %s repository...", *repoName)
+		response.Success(ctx, http.StatusOK, msg, releases)
+		return
+	}
+
+	releasesResp := dtos.AllReleaseResponse{
+		Releases: dtos.ReleasesResponse(releases),
+		PageInfo: dtos.PagingInfoResponse(*pagingInfo),
+	}
+
+	msg := fmt.Sprintf("%s repository releases fetched successfully", *repoName)
+
+	response.Success(ctx, http.StatusOK, msg, releasesResp)
+}
@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
@@ -36,14 +37,23 @@ func (rh RepositoryHandlers) AddRepository(ctx *gin.Context) {
 		return
 	}
 
-	repo, err := rh.gitRepositoryUsecase.StartIndexing(ctx, input.Name)
+	repo, err := rh.gitRepositoryUsecase.StartIndexing(ctx, input.Name, input.Branches, input.Credential, input.Token)
 	if err != nil {
-		if err == message.ErrRepoAlreadyAdded {
+		if err == message.ErrRepoAlreadyAdded || err == message.ErrInvalidRepositoryName || err == message.ErrUnsupportedGitHost ||
+			err == message.ErrLocalRepositoryOutsideRoot ||
+			err == message.ErrGitHubAppNotInstalled || err == message.ErrInvalidBranchPattern ||
+			err == message.ErrRepositoryNotFound || err == message.ErrRepositoryInaccessible ||
+			err == message.ErrAmbiguousCredential || err == message.ErrCredentialNotFound || err == message.ErrCredentialsNotSupported {
 			response.Failure(ctx, http.StatusBadRequest, err.Error(), err.Error())
 			return
 		}
 
-		if err == message.ErrRateLimitExceeded {
+		if err == message.ErrCredentialsNotConfigured {
+			response.Failure(ctx, http.StatusServiceUnavailable, err.Error(), err.Error())
+			return
+		}
+
+		if errors.Is(err, message.ErrRateLimitExceeded) {
 			response.Failure(ctx, http.StatusForbidden, err.Error(), err.Error())
 			return
 		}
@@ -90,3 +100,137 @@ func (rh RepositoryHandlers) FetchRepository(ctx *gin.Context) {
 
 	response.Success(ctx, http.StatusOK, "successfully fetched repository", dtos.RepoMetadataResponse(*repo))
 }
+
+func (rh RepositoryHandlers) FetchRateLimits(ctx *gin.Context) {
+	rateLimits := rh.gitRepositoryUsecase.RateLimits(ctx)
+
+	response.Success(ctx, http.StatusOK, "successfully fetched rate limits", dtos.AllRateLimitResponse(rateLimits))
+}
+
+func (rh RepositoryHandlers) FetchRepositoryProgress(ctx *gin.Context) {
+	repositoryId := ctx.Param("repoId")
+	if repositoryId == "" {
+		response.Failure(ctx, http.StatusBadRequest, "repoId is required", nil)
+		return
+	}
+
+	repo, err := rh.gitRepositoryUsecase.GetById(ctx, repositoryId)
+	if err != nil {
+		if err == message.ErrNoRecordFound {
+			response.Failure(ctx, http.StatusBadRequest, message.ErrInvalidRepositoryId.Error(), message.ErrInvalidRepositoryId.Error())
+			return
+		}
+		response.Failure(ctx, http.StatusInternalServerError, err.Error(), err.Error())
+		return
+	}
+
+	response.Success(ctx, http.StatusOK, "successfully fetched repository indexing progress", dtos.IndexingProgressResponse(*repo))
+}
+
+func (rh RepositoryHandlers) UpdateTrackedBranches(ctx *gin.Context) {
+	repositoryId := ctx.Param("repoId")
+	if repositoryId == "" {
+		response.Failure(ctx, http.StatusBadRequest, "repoId is required", nil)
+		return
+	}
+
+	var input dtos.UpdateTrackedBranchesRequestDto
+
+	err := ctx.BindJSON(&input)
+	if err != nil {
+		response.Failure(ctx, http.StatusBadRequest, "invalid input", err)
+		return
+	}
+
+	repo, err := rh.gitRepositoryUsecase.UpdateTrackedBranches(ctx, repositoryId, input.Branches)
+	if err != nil {
+		if err == message.ErrNoRecordFound {
+			response.Failure(ctx, http.StatusBadRequest, message.ErrInvalidRepositoryId.Error(), message.ErrInvalidRepositoryId.Error())
+			return
+		}
+		if err == message.ErrInvalidBranchPattern {
+			response.Failure(ctx, http.StatusBadRequest, err.Error(), err.Error())
+			return
+		}
+		response.Failure(ctx, http.StatusInternalServerError, err.Error(), err.Error())
+		return
+	}
+
+	response.Success(ctx, http.StatusOK, "tracked branches updated, matching branches are being indexed...", dtos.RepoMetadataResponse(*repo))
+}
+
+func (rh RepositoryHandlers) FetchBranches(ctx *gin.Context) {
+	repositoryId := ctx.Param("repoId")
+	if repositoryId == "" {
+		response.Failure(ctx, http.StatusBadRequest, "repoId is required", nil)
+		return
+	}
+
+	branches, err := rh.gitRepositoryUsecase.GetBranches(ctx, repositoryId)
+	if err != nil {
+		if err == message.ErrNoRecordFound {
+			response.Failure(ctx, http.StatusBadRequest, message.ErrInvalidRepositoryId.Error(), message.ErrInvalidRepositoryId.Error())
+			return
+		}
+		response.Failure(ctx, http.StatusInternalServerError, err.Error(), err.Error())
+		return
+	}
+
+	response.Success(ctx, http.StatusOK, "successfully fetched indexed branches", dtos.AllBranchResponse(branches))
+}
+
+func (rh RepositoryHandlers) FetchMetadataHistory(ctx *gin.Context) {
+	repositoryId := ctx.Param("repoId")
+	if repositoryId == "" {
+		response.Failure(ctx, http.StatusBadRequest, "repoId is required", nil)
+		return
+	}
+
+	since, err := getTimeQuery(ctx, "since")
+	if err != nil {
+		response.Failure(ctx, http.StatusBadRequest, message.ErrInvalidTimeRange.Error(), err.Error())
+		return
+	}
+
+	until, err := getTimeQuery(ctx, "until")
+	if err != nil {
+		response.Failure(ctx, http.StatusBadRequest, message.ErrInvalidTimeRange.Error(), err.Error())
+		return
+	}
+
+	repo, snapshots, err := rh.gitRepositoryUsecase.GetMetadataHistory(ctx, repositoryId, since, until)
+	if err != nil {
+		if err == message.ErrNoRecordFound {
+			response.Failure(ctx, http.StatusBadRequest, message.ErrInvalidRepositoryId.Error(), message.ErrInvalidRepositoryId.Error())
+			return
+		}
+		if err == message.ErrInvalidTimeRange {
+			response.Failure(ctx, http.StatusBadRequest, err.Error(), err.Error())
+			return
+		}
+		response.Failure(ctx, http.StatusInternalServerError, err.Error(), err.Error())
+		return
+	}
+
+	response.Success(ctx, http.StatusOK, "successfully fetched repository metadata history", dtos.MetadataHistoryResponse(*repo, snapshots))
+}
+
+func (rh RepositoryHandlers) FetchHistoryRewrites(ctx *gin.Context) {
+	repositoryId := ctx.Param("repoId")
+	if repositoryId == "" {
+		response.Failure(ctx, http.StatusBadRequest, "repoId is required", nil)
+		return
+	}
+
+	repo, rewrites, err := rh.gitRepositoryUsecase.GetHistoryRewrites(ctx, repositoryId)
+	if err != nil {
+		if err == message.ErrNoRecordFound {
+			response.Failure(ctx, http.StatusBadRequest, message.ErrInvalidRepositoryId.Error(), message.ErrInvalidRepositoryId.Error())
+			return
+		}
+		response.Failure(ctx, http.StatusInternalServerError, err.Error(), err.Error())
+		return
+	}
+
+	response.Success(ctx, http.StatusOK, "successfully fetched repository history rewrites", dtos.HistoryRewritesResponse(*repo, rewrites))
+}
@@ -0,0 +1,62 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/kenmobility/git-api-service/internal/usecases"
+	"github.com/kenmobility/git-api-service/pkg/message"
+	"github.com/kenmobility/git-api-service/pkg/response"
+)
+
+type WebhookHandlers struct {
+	webhookUsecase usecases.WebhookUsecase
+}
+
+func NewWebhookHandler(webhookUsecase usecases.WebhookUsecase) *WebhookHandlers {
+	return &WebhookHandlers{
+		webhookUsecase: webhookUsecase,
+	}
+}
+
+func (wh WebhookHandlers) GitHubWebhook(ctx *gin.Context) {
+	event := ctx.GetHeader("X-GitHub-Event")
+	deliveryID := ctx.GetHeader("X-GitHub-Delivery")
+
+	if event == "" || deliveryID == "" {
+		response.Failure(ctx, http.StatusBadRequest, "X-GitHub-Event and X-GitHub-Delivery headers are required", nil)
+		return
+	}
+
+	body, err := ctx.GetRawData()
+	if err != nil {
+		response.Failure(ctx, http.StatusBadRequest, message.ErrInvalidInput.Error(), err.Error())
+		return
+	}
+
+	saved, err := wh.webhookUsecase.HandleGitHubEvent(ctx, event, deliveryID, ctx.GetHeader("X-Hub-Signature-256"), body)
+	if err != nil {
+		switch err {
+		case message.ErrWebhookNotConfigured:
+			response.Failure(ctx, http.StatusServiceUnavailable, err.Error(), err.Error())
+		case message.ErrInvalidWebhookSignature:
+			response.Failure(ctx, http.StatusUnauthorized, err.Error(), err.Error())
+		case message.ErrInvalidInput:
+			response.Failure(ctx, http.StatusBadRequest, err.Error(), err.Error())
+		case message.ErrRepositoryNotTracked:
+			response.Failure(ctx, http.StatusNotFound, err.Error(), err.Error())
+		case message.ErrUnsupportedWebhookEvent:
+			response.Success(ctx, http.StatusAccepted, err.Error(), nil)
+		case message.ErrDuplicateWebhookDelivery:
+			response.Success(ctx, http.StatusOK, err.Error(), nil)
+		default:
+			response.Failure(ctx, http.StatusInternalServerError, err.Error(), err.Error())
+		}
+		return
+	}
+
+	msg := fmt.Sprintf("%s event processed successfully, %d commits saved", event, saved)
+
+	response.Success(ctx, http.StatusOK, msg, nil)
+}
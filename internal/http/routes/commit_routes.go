@@ -7,5 +7,6 @@ import (
 
 func CommitRoutes(r *gin.Engine, ch *handlers.CommitHandlers) {
 	r.GET("/repos/:repoId/commits", ch.GetCommitsByRepositoryId)
+	r.GET("/repos/:repoId/commits/:commitId", ch.GetCommit)
 	r.GET("/repos/:repoId/top-authors", ch.GetTopCommitAuthors)
 }
@@ -0,0 +1,11 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/kenmobility/git-api-service/internal/http/handlers"
+)
+
+func CredentialRoutes(r *gin.Engine, ch *handlers.CredentialHandlers) {
+	r.POST("/credentials", ch.AddCredential)
+	r.GET("/credentials", ch.FetchAllCredentials)
+}
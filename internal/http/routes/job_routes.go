@@ -0,0 +1,11 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/kenmobility/git-api-service/internal/http/handlers"
+)
+
+func JobRoutes(r *gin.Engine, jh *handlers.JobHandlers) {
+	r.GET("/jobs", jh.FetchAllJobs)
+	r.POST("/jobs/:jobId/requeue", jh.RequeueJob)
+}
@@ -0,0 +1,10 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/kenmobility/git-api-service/internal/http/handlers"
+)
+
+func PullRequestRoutes(r *gin.Engine, ph *handlers.PullRequestHandlers) {
+	r.GET("/repos/:repoId/pulls", ph.GetPullRequestsByRepositoryId)
+}
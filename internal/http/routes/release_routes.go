@@ -0,0 +1,11 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/kenmobility/git-api-service/internal/http/handlers"
+)
+
+func ReleaseRoutes(r *gin.Engine, rh *handlers.ReleaseHandlers) {
+	r.GET("/repos/:repoId/tags", rh.GetTagsByRepositoryId)
+	r.GET("/repos/:repoId/releases", rh.GetReleasesByRepositoryId)
+}
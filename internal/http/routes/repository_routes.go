@@ -9,4 +9,10 @@ func RepositoryRoutes(r *gin.Engine, rh *handlers.RepositoryHandlers) {
 	r.POST("/repository", rh.AddRepository)
 	r.GET("/repositories", rh.FetchAllRepositories)
 	r.GET("/repository/:repoId", rh.FetchRepository)
+	r.GET("/repository/:repoId/progress", rh.FetchRepositoryProgress)
+	r.PUT("/repository/:repoId/branches", rh.UpdateTrackedBranches)
+	r.GET("/repository/:repoId/branches", rh.FetchBranches)
+	r.GET("/repository/:repoId/metadata-history", rh.FetchMetadataHistory)
+	r.GET("/repository/:repoId/history-rewrites", rh.FetchHistoryRewrites)
+	r.GET("/rate-limit", rh.FetchRateLimits)
 }
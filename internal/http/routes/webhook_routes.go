@@ -0,0 +1,10 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/kenmobility/git-api-service/internal/http/handlers"
+)
+
+func WebhookRoutes(r *gin.Engine, wh *handlers.WebhookHandlers) {
+	r.POST("/webhooks/github", wh.GitHubWebhook)
+}
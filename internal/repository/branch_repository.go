@@ -0,0 +1,19 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/kenmobility/git-api-service/internal/domain"
+)
+
+type BranchRepository interface {
+	BranchesByRepository(ctx context.Context, repo domain.RepoMetadata) ([]domain.Branch, error)
+	SaveBranch(ctx context.Context, repo domain.RepoMetadata, branch domain.Branch) error
+	AddCommitsToBranch(ctx context.Context, repo domain.RepoMetadata, branch string, commitIDs []string) error
+	CountCommitsInBranch(ctx context.Context, repo domain.RepoMetadata, branch string, commitIDs []string) (int64, error)
+	CommitIDsInBranch(ctx context.Context, repo domain.RepoMetadata, branch string) ([]string, error)
+	RemoveCommitsFromBranch(ctx context.Context, repo domain.RepoMetadata, branch string, commitIDs []string, orphanedAt time.Time) (int64, error)
+	SaveHistoryRewrite(ctx context.Context, repo domain.RepoMetadata, rewrite domain.HistoryRewrite) error
+	HistoryRewritesByRepository(ctx context.Context, repo domain.RepoMetadata) ([]domain.HistoryRewrite, error)
+}
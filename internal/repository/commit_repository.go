@@ -8,7 +8,10 @@ import (
 
 type CommitRepository interface {
 	SaveCommit(ctx context.Context, commit domain.Commit) (*domain.Commit, error)
-	GetByCommitID(ctx context.Context, commitID string) (*domain.Commit, error)
-	AllCommitsByRepository(ctx context.Context, repoMetadata domain.RepoMetadata, query domain.APIPagingData) ([]domain.Commit, *domain.PagingInfo, error)
+	GetByCommitID(ctx context.Context, repo domain.RepoMetadata, commitID string) (*domain.Commit, error)
+	AllCommitsByRepository(ctx context.Context, repoMetadata domain.RepoMetadata, filter domain.CommitFilter, query domain.APIPagingData) ([]domain.Commit, *domain.PagingInfo, error)
 	TopCommitAuthorsByRepository(ctx context.Context, repo domain.RepoMetadata, limit int) ([]domain.AuthorCommitCount, error)
+	CommitWithFiles(ctx context.Context, repo domain.RepoMetadata, commitID string) (*domain.Commit, error)
+	UnenrichedCommits(ctx context.Context, repo domain.RepoMetadata, limit int) ([]domain.Commit, error)
+	SaveCommitChanges(ctx context.Context, commit domain.Commit) error
 }
@@ -0,0 +1,14 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/kenmobility/git-api-service/internal/domain"
+)
+
+type CredentialRepository interface {
+	SaveCredential(ctx context.Context, credential domain.Credential) (*domain.Credential, error)
+	CredentialByPublicId(ctx context.Context, publicId string) (*domain.Credential, error)
+	CredentialByName(ctx context.Context, name string) (*domain.Credential, error)
+	AllCredentials(ctx context.Context) ([]domain.Credential, error)
+}
@@ -0,0 +1,12 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/kenmobility/git-api-service/internal/domain"
+)
+
+type HTTPValidatorRepository interface {
+	ValidatorByEndpoint(ctx context.Context, endpoint string) (*domain.HTTPValidator, error)
+	SaveValidator(ctx context.Context, validator domain.HTTPValidator) error
+}
@@ -0,0 +1,21 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/kenmobility/git-api-service/internal/domain"
+)
+
+type JobRepository interface {
+	EnqueueJob(ctx context.Context, job domain.Job) error
+	ClaimJob(ctx context.Context, visibilityTimeout time.Duration) (*domain.Job, error)
+	ExtendJobLock(ctx context.Context, job domain.Job, lockedUntil time.Time) error
+	CompleteJob(ctx context.Context, job domain.Job) error
+	RescheduleJob(ctx context.Context, job domain.Job, runAt time.Time) error
+	RetryJob(ctx context.Context, job domain.Job, runAt time.Time, lastError string) error
+	DeadLetterJob(ctx context.Context, job domain.Job, lastError string) error
+	ReleaseJob(ctx context.Context, job domain.Job, runAt time.Time) error
+	RequeueJob(ctx context.Context, publicID string) (*domain.Job, error)
+	AllJobs(ctx context.Context, status string, query domain.APIPagingData) ([]domain.Job, *domain.PagingInfo, error)
+}
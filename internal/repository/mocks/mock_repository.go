@@ -12,6 +12,7 @@ package repo_mocks
 import (
 	context "context"
 	reflect "reflect"
+	time "time"
 
 	domain "github.com/kenmobility/git-api-service/internal/domain"
 	gomock "go.uber.org/mock/gomock"
@@ -40,10 +41,39 @@ func (m *MockRepository) EXPECT() *MockRepositoryMockRecorder {
 	return m.recorder
 }
 
+// AcquireRepoLease mocks base method.
+func (m *MockRepository) AcquireRepoLease(arg0 context.Context, arg1, arg2 string, arg3 time.Duration) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AcquireRepoLease", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AcquireRepoLease indicates an expected call of AcquireRepoLease.
+func (mr *MockRepositoryMockRecorder) AcquireRepoLease(arg0, arg1, arg2, arg3 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AcquireRepoLease", reflect.TypeOf((*MockRepository)(nil).AcquireRepoLease), arg0, arg1, arg2, arg3)
+}
+
+// AddCommitsToBranch mocks base method.
+func (m *MockRepository) AddCommitsToBranch(arg0 context.Context, arg1 domain.RepoMetadata, arg2 string, arg3 []string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddCommitsToBranch", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddCommitsToBranch indicates an expected call of AddCommitsToBranch.
+func (mr *MockRepositoryMockRecorder) AddCommitsToBranch(arg0, arg1, arg2, arg3 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddCommitsToBranch", reflect.TypeOf((*MockRepository)(nil).AddCommitsToBranch), arg0, arg1, arg2, arg3)
+}
+
 // AllCommitsByRepository mocks base method.
-func (m *MockRepository) AllCommitsByRepository(arg0 context.Context, arg1 domain.RepoMetadata, arg2 domain.APIPagingData) ([]domain.Commit, *domain.PagingInfo, error) {
+func (m *MockRepository) AllCommitsByRepository(arg0 context.Context, arg1 domain.RepoMetadata, arg2 domain.CommitFilter, arg3 domain.APIPagingData) ([]domain.Commit, *domain.PagingInfo, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "AllCommitsByRepository", arg0, arg1, arg2)
+	ret := m.ctrl.Call(m, "AllCommitsByRepository", arg0, arg1, arg2, arg3)
 	ret0, _ := ret[0].([]domain.Commit)
 	ret1, _ := ret[1].(*domain.PagingInfo)
 	ret2, _ := ret[2].(error)
@@ -51,9 +81,40 @@ func (m *MockRepository) AllCommitsByRepository(arg0 context.Context, arg1 domai
 }
 
 // AllCommitsByRepository indicates an expected call of AllCommitsByRepository.
-func (mr *MockRepositoryMockRecorder) AllCommitsByRepository(arg0, arg1, arg2 any) *gomock.Call {
+func (mr *MockRepositoryMockRecorder) AllCommitsByRepository(arg0, arg1, arg2, arg3 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AllCommitsByRepository", reflect.TypeOf((*MockRepository)(nil).AllCommitsByRepository), arg0, arg1, arg2, arg3)
+}
+
+// AllCredentials mocks base method.
+func (m *MockRepository) AllCredentials(arg0 context.Context) ([]domain.Credential, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AllCredentials", arg0)
+	ret0, _ := ret[0].([]domain.Credential)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AllCredentials indicates an expected call of AllCredentials.
+func (mr *MockRepositoryMockRecorder) AllCredentials(arg0 any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AllCommitsByRepository", reflect.TypeOf((*MockRepository)(nil).AllCommitsByRepository), arg0, arg1, arg2)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AllCredentials", reflect.TypeOf((*MockRepository)(nil).AllCredentials), arg0)
+}
+
+// AllJobs mocks base method.
+func (m *MockRepository) AllJobs(arg0 context.Context, arg1 string, arg2 domain.APIPagingData) ([]domain.Job, *domain.PagingInfo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AllJobs", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]domain.Job)
+	ret1, _ := ret[1].(*domain.PagingInfo)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// AllJobs indicates an expected call of AllJobs.
+func (mr *MockRepositoryMockRecorder) AllJobs(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AllJobs", reflect.TypeOf((*MockRepository)(nil).AllJobs), arg0, arg1, arg2)
 }
 
 // AllRepoMetadata mocks base method.
@@ -71,34 +132,401 @@ func (mr *MockRepositoryMockRecorder) AllRepoMetadata(arg0 any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AllRepoMetadata", reflect.TypeOf((*MockRepository)(nil).AllRepoMetadata), arg0)
 }
 
+// BranchesByRepository mocks base method.
+func (m *MockRepository) BranchesByRepository(arg0 context.Context, arg1 domain.RepoMetadata) ([]domain.Branch, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BranchesByRepository", arg0, arg1)
+	ret0, _ := ret[0].([]domain.Branch)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// BranchesByRepository indicates an expected call of BranchesByRepository.
+func (mr *MockRepositoryMockRecorder) BranchesByRepository(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BranchesByRepository", reflect.TypeOf((*MockRepository)(nil).BranchesByRepository), arg0, arg1)
+}
+
+// ClaimDelivery mocks base method.
+func (m *MockRepository) ClaimDelivery(arg0 context.Context, arg1, arg2 string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClaimDelivery", arg0, arg1, arg2)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClaimDelivery indicates an expected call of ClaimDelivery.
+func (mr *MockRepositoryMockRecorder) ClaimDelivery(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClaimDelivery", reflect.TypeOf((*MockRepository)(nil).ClaimDelivery), arg0, arg1, arg2)
+}
+
+// ClaimJob mocks base method.
+func (m *MockRepository) ClaimJob(arg0 context.Context, arg1 time.Duration) (*domain.Job, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClaimJob", arg0, arg1)
+	ret0, _ := ret[0].(*domain.Job)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClaimJob indicates an expected call of ClaimJob.
+func (mr *MockRepositoryMockRecorder) ClaimJob(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClaimJob", reflect.TypeOf((*MockRepository)(nil).ClaimJob), arg0, arg1)
+}
+
+// CommitIDsInBranch mocks base method.
+func (m *MockRepository) CommitIDsInBranch(arg0 context.Context, arg1 domain.RepoMetadata, arg2 string) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CommitIDsInBranch", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CommitIDsInBranch indicates an expected call of CommitIDsInBranch.
+func (mr *MockRepositoryMockRecorder) CommitIDsInBranch(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CommitIDsInBranch", reflect.TypeOf((*MockRepository)(nil).CommitIDsInBranch), arg0, arg1, arg2)
+}
+
+// CommitWithFiles mocks base method.
+func (m *MockRepository) CommitWithFiles(arg0 context.Context, arg1 domain.RepoMetadata, arg2 string) (*domain.Commit, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CommitWithFiles", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*domain.Commit)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CommitWithFiles indicates an expected call of CommitWithFiles.
+func (mr *MockRepositoryMockRecorder) CommitWithFiles(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CommitWithFiles", reflect.TypeOf((*MockRepository)(nil).CommitWithFiles), arg0, arg1, arg2)
+}
+
+// CompleteJob mocks base method.
+func (m *MockRepository) CompleteJob(arg0 context.Context, arg1 domain.Job) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CompleteJob", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CompleteJob indicates an expected call of CompleteJob.
+func (mr *MockRepositoryMockRecorder) CompleteJob(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CompleteJob", reflect.TypeOf((*MockRepository)(nil).CompleteJob), arg0, arg1)
+}
+
+// CountCommitsInBranch mocks base method.
+func (m *MockRepository) CountCommitsInBranch(arg0 context.Context, arg1 domain.RepoMetadata, arg2 string, arg3 []string) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountCommitsInBranch", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountCommitsInBranch indicates an expected call of CountCommitsInBranch.
+func (mr *MockRepositoryMockRecorder) CountCommitsInBranch(arg0, arg1, arg2, arg3 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountCommitsInBranch", reflect.TypeOf((*MockRepository)(nil).CountCommitsInBranch), arg0, arg1, arg2, arg3)
+}
+
+// CredentialByName mocks base method.
+func (m *MockRepository) CredentialByName(arg0 context.Context, arg1 string) (*domain.Credential, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CredentialByName", arg0, arg1)
+	ret0, _ := ret[0].(*domain.Credential)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CredentialByName indicates an expected call of CredentialByName.
+func (mr *MockRepositoryMockRecorder) CredentialByName(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CredentialByName", reflect.TypeOf((*MockRepository)(nil).CredentialByName), arg0, arg1)
+}
+
+// CredentialByPublicId mocks base method.
+func (m *MockRepository) CredentialByPublicId(arg0 context.Context, arg1 string) (*domain.Credential, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CredentialByPublicId", arg0, arg1)
+	ret0, _ := ret[0].(*domain.Credential)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CredentialByPublicId indicates an expected call of CredentialByPublicId.
+func (mr *MockRepositoryMockRecorder) CredentialByPublicId(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CredentialByPublicId", reflect.TypeOf((*MockRepository)(nil).CredentialByPublicId), arg0, arg1)
+}
+
+// DeadLetterJob mocks base method.
+func (m *MockRepository) DeadLetterJob(arg0 context.Context, arg1 domain.Job, arg2 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeadLetterJob", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeadLetterJob indicates an expected call of DeadLetterJob.
+func (mr *MockRepositoryMockRecorder) DeadLetterJob(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeadLetterJob", reflect.TypeOf((*MockRepository)(nil).DeadLetterJob), arg0, arg1, arg2)
+}
+
+// EnqueueJob mocks base method.
+func (m *MockRepository) EnqueueJob(arg0 context.Context, arg1 domain.Job) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EnqueueJob", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// EnqueueJob indicates an expected call of EnqueueJob.
+func (mr *MockRepositoryMockRecorder) EnqueueJob(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EnqueueJob", reflect.TypeOf((*MockRepository)(nil).EnqueueJob), arg0, arg1)
+}
+
+// ExtendJobLock mocks base method.
+func (m *MockRepository) ExtendJobLock(arg0 context.Context, arg1 domain.Job, arg2 time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExtendJobLock", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ExtendJobLock indicates an expected call of ExtendJobLock.
+func (mr *MockRepositoryMockRecorder) ExtendJobLock(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExtendJobLock", reflect.TypeOf((*MockRepository)(nil).ExtendJobLock), arg0, arg1, arg2)
+}
+
 // GetByCommitID mocks base method.
-func (m *MockRepository) GetByCommitID(arg0 context.Context, arg1 string) (*domain.Commit, error) {
+func (m *MockRepository) GetByCommitID(arg0 context.Context, arg1 domain.RepoMetadata, arg2 string) (*domain.Commit, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetByCommitID", arg0, arg1)
+	ret := m.ctrl.Call(m, "GetByCommitID", arg0, arg1, arg2)
 	ret0, _ := ret[0].(*domain.Commit)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetByCommitID indicates an expected call of GetByCommitID.
-func (mr *MockRepositoryMockRecorder) GetByCommitID(arg0, arg1 any) *gomock.Call {
+func (mr *MockRepositoryMockRecorder) GetByCommitID(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByCommitID", reflect.TypeOf((*MockRepository)(nil).GetByCommitID), arg0, arg1, arg2)
+}
+
+// HistoryRewritesByRepository mocks base method.
+func (m *MockRepository) HistoryRewritesByRepository(arg0 context.Context, arg1 domain.RepoMetadata) ([]domain.HistoryRewrite, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HistoryRewritesByRepository", arg0, arg1)
+	ret0, _ := ret[0].([]domain.HistoryRewrite)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// HistoryRewritesByRepository indicates an expected call of HistoryRewritesByRepository.
+func (mr *MockRepositoryMockRecorder) HistoryRewritesByRepository(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HistoryRewritesByRepository", reflect.TypeOf((*MockRepository)(nil).HistoryRewritesByRepository), arg0, arg1)
+}
+
+// LatestPullRequestUpdate mocks base method.
+func (m *MockRepository) LatestPullRequestUpdate(arg0 context.Context, arg1 domain.RepoMetadata) (time.Time, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LatestPullRequestUpdate", arg0, arg1)
+	ret0, _ := ret[0].(time.Time)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LatestPullRequestUpdate indicates an expected call of LatestPullRequestUpdate.
+func (mr *MockRepositoryMockRecorder) LatestPullRequestUpdate(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LatestPullRequestUpdate", reflect.TypeOf((*MockRepository)(nil).LatestPullRequestUpdate), arg0, arg1)
+}
+
+// LinkPullRequestCommits mocks base method.
+func (m *MockRepository) LinkPullRequestCommits(arg0 context.Context, arg1 domain.RepoMetadata, arg2 int, arg3 []string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LinkPullRequestCommits", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// LinkPullRequestCommits indicates an expected call of LinkPullRequestCommits.
+func (mr *MockRepositoryMockRecorder) LinkPullRequestCommits(arg0, arg1, arg2, arg3 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LinkPullRequestCommits", reflect.TypeOf((*MockRepository)(nil).LinkPullRequestCommits), arg0, arg1, arg2, arg3)
+}
+
+// PullRequestsByCommits mocks base method.
+func (m *MockRepository) PullRequestsByCommits(arg0 context.Context, arg1 domain.RepoMetadata, arg2 []string) (map[string]domain.PullRequest, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PullRequestsByCommits", arg0, arg1, arg2)
+	ret0, _ := ret[0].(map[string]domain.PullRequest)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PullRequestsByCommits indicates an expected call of PullRequestsByCommits.
+func (mr *MockRepositoryMockRecorder) PullRequestsByCommits(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PullRequestsByCommits", reflect.TypeOf((*MockRepository)(nil).PullRequestsByCommits), arg0, arg1, arg2)
+}
+
+// PullRequestsByRepository mocks base method.
+func (m *MockRepository) PullRequestsByRepository(arg0 context.Context, arg1 domain.RepoMetadata, arg2 domain.PullRequestFilter, arg3 domain.APIPagingData) ([]domain.PullRequest, *domain.PagingInfo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PullRequestsByRepository", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].([]domain.PullRequest)
+	ret1, _ := ret[1].(*domain.PagingInfo)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// PullRequestsByRepository indicates an expected call of PullRequestsByRepository.
+func (mr *MockRepositoryMockRecorder) PullRequestsByRepository(arg0, arg1, arg2, arg3 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PullRequestsByRepository", reflect.TypeOf((*MockRepository)(nil).PullRequestsByRepository), arg0, arg1, arg2, arg3)
+}
+
+// RefreshRepoMetadata mocks base method.
+func (m *MockRepository) RefreshRepoMetadata(arg0 context.Context, arg1 domain.RepoMetadata) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RefreshRepoMetadata", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RefreshRepoMetadata indicates an expected call of RefreshRepoMetadata.
+func (mr *MockRepositoryMockRecorder) RefreshRepoMetadata(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RefreshRepoMetadata", reflect.TypeOf((*MockRepository)(nil).RefreshRepoMetadata), arg0, arg1)
+}
+
+// ReleaseDelivery mocks base method.
+func (m *MockRepository) ReleaseDelivery(arg0 context.Context, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReleaseDelivery", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ReleaseDelivery indicates an expected call of ReleaseDelivery.
+func (mr *MockRepositoryMockRecorder) ReleaseDelivery(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReleaseDelivery", reflect.TypeOf((*MockRepository)(nil).ReleaseDelivery), arg0, arg1)
+}
+
+// ReleaseJob mocks base method.
+func (m *MockRepository) ReleaseJob(arg0 context.Context, arg1 domain.Job, arg2 time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReleaseJob", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ReleaseJob indicates an expected call of ReleaseJob.
+func (mr *MockRepositoryMockRecorder) ReleaseJob(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReleaseJob", reflect.TypeOf((*MockRepository)(nil).ReleaseJob), arg0, arg1, arg2)
+}
+
+// ReleaseRepoLease mocks base method.
+func (m *MockRepository) ReleaseRepoLease(arg0 context.Context, arg1, arg2 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReleaseRepoLease", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ReleaseRepoLease indicates an expected call of ReleaseRepoLease.
+func (mr *MockRepositoryMockRecorder) ReleaseRepoLease(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReleaseRepoLease", reflect.TypeOf((*MockRepository)(nil).ReleaseRepoLease), arg0, arg1, arg2)
+}
+
+// ReleasesByRepository mocks base method.
+func (m *MockRepository) ReleasesByRepository(arg0 context.Context, arg1 domain.RepoMetadata, arg2 domain.APIPagingData) ([]domain.Release, *domain.PagingInfo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReleasesByRepository", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]domain.Release)
+	ret1, _ := ret[1].(*domain.PagingInfo)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ReleasesByRepository indicates an expected call of ReleasesByRepository.
+func (mr *MockRepositoryMockRecorder) ReleasesByRepository(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReleasesByRepository", reflect.TypeOf((*MockRepository)(nil).ReleasesByRepository), arg0, arg1, arg2)
+}
+
+// RemoveCommitsFromBranch mocks base method.
+func (m *MockRepository) RemoveCommitsFromBranch(arg0 context.Context, arg1 domain.RepoMetadata, arg2 string, arg3 []string, arg4 time.Time) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveCommitsFromBranch", arg0, arg1, arg2, arg3, arg4)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RemoveCommitsFromBranch indicates an expected call of RemoveCommitsFromBranch.
+func (mr *MockRepositoryMockRecorder) RemoveCommitsFromBranch(arg0, arg1, arg2, arg3, arg4 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveCommitsFromBranch", reflect.TypeOf((*MockRepository)(nil).RemoveCommitsFromBranch), arg0, arg1, arg2, arg3, arg4)
+}
+
+// RenameRepository mocks base method.
+func (m *MockRepository) RenameRepository(arg0 context.Context, arg1 domain.RepoMetadata, arg2 string) (*domain.RepoMetadata, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RenameRepository", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*domain.RepoMetadata)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RenameRepository indicates an expected call of RenameRepository.
+func (mr *MockRepositoryMockRecorder) RenameRepository(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RenameRepository", reflect.TypeOf((*MockRepository)(nil).RenameRepository), arg0, arg1, arg2)
+}
+
+// RenewRepoLease mocks base method.
+func (m *MockRepository) RenewRepoLease(arg0 context.Context, arg1, arg2 string, arg3 time.Duration) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RenewRepoLease", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RenewRepoLease indicates an expected call of RenewRepoLease.
+func (mr *MockRepositoryMockRecorder) RenewRepoLease(arg0, arg1, arg2, arg3 any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByCommitID", reflect.TypeOf((*MockRepository)(nil).GetByCommitID), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RenewRepoLease", reflect.TypeOf((*MockRepository)(nil).RenewRepoLease), arg0, arg1, arg2, arg3)
 }
 
 // RepoMetadataByName mocks base method.
-func (m *MockRepository) RepoMetadataByName(arg0 context.Context, arg1 string) (*domain.RepoMetadata, error) {
+func (m *MockRepository) RepoMetadataByName(arg0 context.Context, arg1, arg2 string) (*domain.RepoMetadata, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "RepoMetadataByName", arg0, arg1)
+	ret := m.ctrl.Call(m, "RepoMetadataByName", arg0, arg1, arg2)
 	ret0, _ := ret[0].(*domain.RepoMetadata)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // RepoMetadataByName indicates an expected call of RepoMetadataByName.
-func (mr *MockRepositoryMockRecorder) RepoMetadataByName(arg0, arg1 any) *gomock.Call {
+func (mr *MockRepositoryMockRecorder) RepoMetadataByName(arg0, arg1, arg2 any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RepoMetadataByName", reflect.TypeOf((*MockRepository)(nil).RepoMetadataByName), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RepoMetadataByName", reflect.TypeOf((*MockRepository)(nil).RepoMetadataByName), arg0, arg1, arg2)
 }
 
 // RepoMetadataByPublicId mocks base method.
@@ -116,6 +544,63 @@ func (mr *MockRepositoryMockRecorder) RepoMetadataByPublicId(arg0, arg1 any) *go
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RepoMetadataByPublicId", reflect.TypeOf((*MockRepository)(nil).RepoMetadataByPublicId), arg0, arg1)
 }
 
+// RequeueJob mocks base method.
+func (m *MockRepository) RequeueJob(arg0 context.Context, arg1 string) (*domain.Job, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RequeueJob", arg0, arg1)
+	ret0, _ := ret[0].(*domain.Job)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RequeueJob indicates an expected call of RequeueJob.
+func (mr *MockRepositoryMockRecorder) RequeueJob(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RequeueJob", reflect.TypeOf((*MockRepository)(nil).RequeueJob), arg0, arg1)
+}
+
+// RescheduleJob mocks base method.
+func (m *MockRepository) RescheduleJob(arg0 context.Context, arg1 domain.Job, arg2 time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RescheduleJob", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RescheduleJob indicates an expected call of RescheduleJob.
+func (mr *MockRepositoryMockRecorder) RescheduleJob(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RescheduleJob", reflect.TypeOf((*MockRepository)(nil).RescheduleJob), arg0, arg1, arg2)
+}
+
+// RetryJob mocks base method.
+func (m *MockRepository) RetryJob(arg0 context.Context, arg1 domain.Job, arg2 time.Time, arg3 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RetryJob", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RetryJob indicates an expected call of RetryJob.
+func (mr *MockRepositoryMockRecorder) RetryJob(arg0, arg1, arg2, arg3 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RetryJob", reflect.TypeOf((*MockRepository)(nil).RetryJob), arg0, arg1, arg2, arg3)
+}
+
+// SaveBranch mocks base method.
+func (m *MockRepository) SaveBranch(arg0 context.Context, arg1 domain.RepoMetadata, arg2 domain.Branch) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveBranch", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveBranch indicates an expected call of SaveBranch.
+func (mr *MockRepositoryMockRecorder) SaveBranch(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveBranch", reflect.TypeOf((*MockRepository)(nil).SaveBranch), arg0, arg1, arg2)
+}
+
 // SaveCommit mocks base method.
 func (m *MockRepository) SaveCommit(arg0 context.Context, arg1 domain.Commit) (*domain.Commit, error) {
 	m.ctrl.T.Helper()
@@ -131,6 +616,63 @@ func (mr *MockRepositoryMockRecorder) SaveCommit(arg0, arg1 any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveCommit", reflect.TypeOf((*MockRepository)(nil).SaveCommit), arg0, arg1)
 }
 
+// SaveCommitChanges mocks base method.
+func (m *MockRepository) SaveCommitChanges(arg0 context.Context, arg1 domain.Commit) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveCommitChanges", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveCommitChanges indicates an expected call of SaveCommitChanges.
+func (mr *MockRepositoryMockRecorder) SaveCommitChanges(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveCommitChanges", reflect.TypeOf((*MockRepository)(nil).SaveCommitChanges), arg0, arg1)
+}
+
+// SaveCredential mocks base method.
+func (m *MockRepository) SaveCredential(arg0 context.Context, arg1 domain.Credential) (*domain.Credential, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveCredential", arg0, arg1)
+	ret0, _ := ret[0].(*domain.Credential)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SaveCredential indicates an expected call of SaveCredential.
+func (mr *MockRepositoryMockRecorder) SaveCredential(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveCredential", reflect.TypeOf((*MockRepository)(nil).SaveCredential), arg0, arg1)
+}
+
+// SaveHistoryRewrite mocks base method.
+func (m *MockRepository) SaveHistoryRewrite(arg0 context.Context, arg1 domain.RepoMetadata, arg2 domain.HistoryRewrite) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveHistoryRewrite", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveHistoryRewrite indicates an expected call of SaveHistoryRewrite.
+func (mr *MockRepositoryMockRecorder) SaveHistoryRewrite(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveHistoryRewrite", reflect.TypeOf((*MockRepository)(nil).SaveHistoryRewrite), arg0, arg1, arg2)
+}
+
+// SavePullRequests mocks base method.
+func (m *MockRepository) SavePullRequests(arg0 context.Context, arg1 domain.RepoMetadata, arg2 []domain.PullRequest) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SavePullRequests", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SavePullRequests indicates an expected call of SavePullRequests.
+func (mr *MockRepositoryMockRecorder) SavePullRequests(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SavePullRequests", reflect.TypeOf((*MockRepository)(nil).SavePullRequests), arg0, arg1, arg2)
+}
+
 // SaveRepoMetadata mocks base method.
 func (m *MockRepository) SaveRepoMetadata(arg0 context.Context, arg1 domain.RepoMetadata) (*domain.RepoMetadata, error) {
 	m.ctrl.T.Helper()
@@ -146,6 +688,93 @@ func (mr *MockRepositoryMockRecorder) SaveRepoMetadata(arg0, arg1 any) *gomock.C
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveRepoMetadata", reflect.TypeOf((*MockRepository)(nil).SaveRepoMetadata), arg0, arg1)
 }
 
+// SaveSnapshot mocks base method.
+func (m *MockRepository) SaveSnapshot(arg0 context.Context, arg1 domain.RepoMetadata, arg2 domain.RepoMetadataSnapshot) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveSnapshot", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveSnapshot indicates an expected call of SaveSnapshot.
+func (mr *MockRepositoryMockRecorder) SaveSnapshot(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveSnapshot", reflect.TypeOf((*MockRepository)(nil).SaveSnapshot), arg0, arg1, arg2)
+}
+
+// SaveValidator mocks base method.
+func (m *MockRepository) SaveValidator(arg0 context.Context, arg1 domain.HTTPValidator) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveValidator", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveValidator indicates an expected call of SaveValidator.
+func (mr *MockRepositoryMockRecorder) SaveValidator(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveValidator", reflect.TypeOf((*MockRepository)(nil).SaveValidator), arg0, arg1)
+}
+
+// SnapshotsByRepository mocks base method.
+func (m *MockRepository) SnapshotsByRepository(arg0 context.Context, arg1 domain.RepoMetadata, arg2, arg3 time.Time) ([]domain.RepoMetadataSnapshot, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SnapshotsByRepository", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].([]domain.RepoMetadataSnapshot)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SnapshotsByRepository indicates an expected call of SnapshotsByRepository.
+func (mr *MockRepositoryMockRecorder) SnapshotsByRepository(arg0, arg1, arg2, arg3 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SnapshotsByRepository", reflect.TypeOf((*MockRepository)(nil).SnapshotsByRepository), arg0, arg1, arg2, arg3)
+}
+
+// SyncReleases mocks base method.
+func (m *MockRepository) SyncReleases(arg0 context.Context, arg1 domain.RepoMetadata, arg2 []domain.Release) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SyncReleases", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SyncReleases indicates an expected call of SyncReleases.
+func (mr *MockRepositoryMockRecorder) SyncReleases(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SyncReleases", reflect.TypeOf((*MockRepository)(nil).SyncReleases), arg0, arg1, arg2)
+}
+
+// SyncTags mocks base method.
+func (m *MockRepository) SyncTags(arg0 context.Context, arg1 domain.RepoMetadata, arg2 []domain.Tag) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SyncTags", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SyncTags indicates an expected call of SyncTags.
+func (mr *MockRepositoryMockRecorder) SyncTags(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SyncTags", reflect.TypeOf((*MockRepository)(nil).SyncTags), arg0, arg1, arg2)
+}
+
+// TagsByRepository mocks base method.
+func (m *MockRepository) TagsByRepository(arg0 context.Context, arg1 domain.RepoMetadata, arg2 domain.APIPagingData) ([]domain.Tag, *domain.PagingInfo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TagsByRepository", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]domain.Tag)
+	ret1, _ := ret[1].(*domain.PagingInfo)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// TagsByRepository indicates an expected call of TagsByRepository.
+func (mr *MockRepositoryMockRecorder) TagsByRepository(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TagsByRepository", reflect.TypeOf((*MockRepository)(nil).TagsByRepository), arg0, arg1, arg2)
+}
+
 // TopCommitAuthorsByRepository mocks base method.
 func (m *MockRepository) TopCommitAuthorsByRepository(arg0 context.Context, arg1 domain.RepoMetadata, arg2 int) ([]domain.AuthorCommitCount, error) {
 	m.ctrl.T.Helper()
@@ -161,18 +790,34 @@ func (mr *MockRepositoryMockRecorder) TopCommitAuthorsByRepository(arg0, arg1, a
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TopCommitAuthorsByRepository", reflect.TypeOf((*MockRepository)(nil).TopCommitAuthorsByRepository), arg0, arg1, arg2)
 }
 
-// UpdateFetchingStateForAllRepos mocks base method.
-func (m *MockRepository) UpdateFetchingStateForAllRepos(arg0 context.Context, arg1 bool) error {
+// UnenrichedCommits mocks base method.
+func (m *MockRepository) UnenrichedCommits(arg0 context.Context, arg1 domain.RepoMetadata, arg2 int) ([]domain.Commit, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "UpdateFetchingStateForAllRepos", arg0, arg1)
-	ret0, _ := ret[0].(error)
-	return ret0
+	ret := m.ctrl.Call(m, "UnenrichedCommits", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]domain.Commit)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
-// UpdateFetchingStateForAllRepos indicates an expected call of UpdateFetchingStateForAllRepos.
-func (mr *MockRepositoryMockRecorder) UpdateFetchingStateForAllRepos(arg0, arg1 any) *gomock.Call {
+// UnenrichedCommits indicates an expected call of UnenrichedCommits.
+func (mr *MockRepositoryMockRecorder) UnenrichedCommits(arg0, arg1, arg2 any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateFetchingStateForAllRepos", reflect.TypeOf((*MockRepository)(nil).UpdateFetchingStateForAllRepos), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UnenrichedCommits", reflect.TypeOf((*MockRepository)(nil).UnenrichedCommits), arg0, arg1, arg2)
+}
+
+// UnlinkedPullRequests mocks base method.
+func (m *MockRepository) UnlinkedPullRequests(arg0 context.Context, arg1 domain.RepoMetadata, arg2 int) ([]domain.PullRequest, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UnlinkedPullRequests", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]domain.PullRequest)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UnlinkedPullRequests indicates an expected call of UnlinkedPullRequests.
+func (mr *MockRepositoryMockRecorder) UnlinkedPullRequests(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UnlinkedPullRequests", reflect.TypeOf((*MockRepository)(nil).UnlinkedPullRequests), arg0, arg1, arg2)
 }
 
 // UpdateRepoMetadata mocks base method.
@@ -189,3 +834,33 @@ func (mr *MockRepositoryMockRecorder) UpdateRepoMetadata(arg0, arg1 any) *gomock
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateRepoMetadata", reflect.TypeOf((*MockRepository)(nil).UpdateRepoMetadata), arg0, arg1)
 }
+
+// UpdateTrackedBranches mocks base method.
+func (m *MockRepository) UpdateTrackedBranches(arg0 context.Context, arg1 string, arg2 []string) (*domain.RepoMetadata, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateTrackedBranches", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*domain.RepoMetadata)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateTrackedBranches indicates an expected call of UpdateTrackedBranches.
+func (mr *MockRepositoryMockRecorder) UpdateTrackedBranches(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateTrackedBranches", reflect.TypeOf((*MockRepository)(nil).UpdateTrackedBranches), arg0, arg1, arg2)
+}
+
+// ValidatorByEndpoint mocks base method.
+func (m *MockRepository) ValidatorByEndpoint(arg0 context.Context, arg1 string) (*domain.HTTPValidator, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ValidatorByEndpoint", arg0, arg1)
+	ret0, _ := ret[0].(*domain.HTTPValidator)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ValidatorByEndpoint indicates an expected call of ValidatorByEndpoint.
+func (mr *MockRepositoryMockRecorder) ValidatorByEndpoint(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ValidatorByEndpoint", reflect.TypeOf((*MockRepository)(nil).ValidatorByEndpoint), arg0, arg1)
+}
@@ -0,0 +1,85 @@
+package postgres
+
+import (
+	"time"
+
+	"github.com/kenmobility/git-api-service/internal/domain"
+)
+
+// Branch represents the Postgres model for the branches table, the tracked branches of the repositories.
+type Branch struct {
+	ID             uint   `gorm:"primarykey"`
+	RepositoryHost string `gorm:"type:varchar;uniqueIndex:idx_branches_repository_branch"`
+	RepositoryName string `gorm:"type:varchar(100);uniqueIndex:idx_branches_repository_branch"`
+	Name           string `gorm:"type:varchar;uniqueIndex:idx_branches_repository_branch"`
+	HeadSHA        string `gorm:"column:head_sha;type:varchar(100)"`
+	IndexedAt      time.Time
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+}
+
+// CommitBranch represents the Postgres model for the commit_branches table, the tracked branches containing each commit.
+type CommitBranch struct {
+	ID             uint   `gorm:"primarykey"`
+	CommitID       string `gorm:"type:varchar(100);uniqueIndex:idx_commit_branches_membership"`
+	RepositoryHost string `gorm:"type:varchar;uniqueIndex:idx_commit_branches_membership;index:idx_commit_branches_branch"`
+	RepositoryName string `gorm:"type:varchar(100);uniqueIndex:idx_commit_branches_membership;index:idx_commit_branches_branch"`
+	Branch         string `gorm:"type:varchar;uniqueIndex:idx_commit_branches_membership;index:idx_commit_branches_branch"`
+	CreatedAt      time.Time
+}
+
+// HistoryRewrite represents the Postgres model for the history_rewrites table, the rewrites found in the history of the branches.
+type HistoryRewrite struct {
+	ID              uint   `gorm:"primarykey"`
+	RepositoryHost  string `gorm:"type:varchar;index:idx_history_rewrites_repository"`
+	RepositoryName  string `gorm:"type:varchar(100);index:idx_history_rewrites_repository"`
+	Branch          string `gorm:"type:varchar"`
+	PreviousHeadSHA string `gorm:"column:previous_head_sha;type:varchar(100)"`
+	HeadSHA         string `gorm:"column:head_sha;type:varchar(100)"`
+	OrphanedCommits int64
+	DetectedAt      time.Time
+}
+
+// ToDomain converts a Postgres Branch object to domain entity Branch.
+func (pb *Branch) ToDomain() *domain.Branch {
+	return &domain.Branch{
+		Name:      pb.Name,
+		HeadSHA:   pb.HeadSHA,
+		IndexedAt: pb.IndexedAt,
+	}
+}
+
+// FromDomainBranch returns a Postgres Branch object of the repository from domain entity Branch.
+func FromDomainBranch(r *domain.RepoMetadata, b *domain.Branch) *Branch {
+	return &Branch{
+		RepositoryHost: r.Host,
+		RepositoryName: r.Name,
+		Name:           b.Name,
+		HeadSHA:        b.HeadSHA,
+		IndexedAt:      b.IndexedAt,
+	}
+}
+
+// ToDomain converts a Postgres HistoryRewrite object to domain entity HistoryRewrite.
+func (ph *HistoryRewrite) ToDomain() *domain.HistoryRewrite {
+	return &domain.HistoryRewrite{
+		Branch:          ph.Branch,
+		PreviousHeadSHA: ph.PreviousHeadSHA,
+		HeadSHA:         ph.HeadSHA,
+		OrphanedCommits: ph.OrphanedCommits,
+		DetectedAt:      ph.DetectedAt,
+	}
+}
+
+// FromDomainHistoryRewrite returns a Postgres HistoryRewrite object of the repository from domain entity HistoryRewrite.
+func FromDomainHistoryRewrite(r *domain.RepoMetadata, h *domain.HistoryRewrite) *HistoryRewrite {
+	return &HistoryRewrite{
+		RepositoryHost:  r.Host,
+		RepositoryName:  r.Name,
+		Branch:          h.Branch,
+		PreviousHeadSHA: h.PreviousHeadSHA,
+		HeadSHA:         h.HeadSHA,
+		OrphanedCommits: h.OrphanedCommits,
+		DetectedAt:      h.DetectedAt,
+	}
+}
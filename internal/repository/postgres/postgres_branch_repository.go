@@ -0,0 +1,180 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"github.com/kenmobility/git-api-service/internal/domain"
+	"github.com/kenmobility/git-api-service/internal/repository"
+	"github.com/kenmobility/git-api-service/pkg/message"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// branchCommitBatchSize is how many commits are removed from a branch per statement, keeping under the parameter limit of Postgres
+const branchCommitBatchSize = 1000
+
+type PostgresBranchRepository struct {
+	DB *gorm.DB
+}
+
+func NewPostgresBranchRepository(db *gorm.DB) repository.BranchRepository {
+	return &PostgresBranchRepository{DB: db}
+}
+
+// BranchesByRepository fetches the tracked branches of the repository
+func (r *PostgresBranchRepository) BranchesByRepository(ctx context.Context, repo domain.RepoMetadata) ([]domain.Branch, error) {
+	if ctx.Err() == context.Canceled {
+		return nil, message.ErrContextCancelled
+	}
+
+	var dbBranches []Branch
+	err := r.DB.WithContext(ctx).
+		Where("repository_host = ? AND repository_name = ?", repo.Host, repo.Name).
+		Order("name").
+		Find(&dbBranches).Error
+	if err != nil {
+		return nil, err
+	}
+
+	branches := make([]domain.Branch, 0, len(dbBranches))
+	for _, b := range dbBranches {
+		branches = append(branches, *b.ToDomain())
+	}
+	return branches, nil
+}
+
+// SaveBranch stores the indexing state of a tracked branch, replacing the one stored before
+func (r *PostgresBranchRepository) SaveBranch(ctx context.Context, repo domain.RepoMetadata, branch domain.Branch) error {
+	if ctx.Err() == context.Canceled {
+		return message.ErrContextCancelled
+	}
+
+	dbBranch := FromDomainBranch(&repo, &branch)
+
+	return r.DB.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "repository_host"}, {Name: "repository_name"}, {Name: "name"}},
+		DoUpdates: clause.AssignmentColumns([]string{"head_sha", "indexed_at", "updated_at"}),
+	}).Create(dbBranch).Error
+}
+
+// AddCommitsToBranch records that the branch contains the commits, commits recorded before are skipped
+func (r *PostgresBranchRepository) AddCommitsToBranch(ctx context.Context, repo domain.RepoMetadata, branch string, commitIDs []string) error {
+	if ctx.Err() == context.Canceled {
+		return message.ErrContextCancelled
+	}
+
+	if len(commitIDs) == 0 {
+		return nil
+	}
+
+	memberships := make([]CommitBranch, 0, len(commitIDs))
+	for _, commitID := range commitIDs {
+		memberships = append(memberships, CommitBranch{
+			CommitID:       commitID,
+			RepositoryHost: repo.Host,
+			RepositoryName: repo.Name,
+			Branch:         branch,
+		})
+	}
+
+	return r.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&memberships).Error; err != nil {
+			return err
+		}
+
+		// commits brought back into a branch, eg by a force-push undoing a rewrite, are reachable again
+		return tx.Model(&Commit{}).
+			Where("repository_host = ? AND repository_name = ? AND commit_id IN ? AND orphaned_at IS NOT NULL", repo.Host, repo.Name, commitIDs).
+			Update("orphaned_at", nil).Error
+	})
+}
+
+// CountCommitsInBranch counts how many of the commits were recorded in the branch
+func (r *PostgresBranchRepository) CountCommitsInBranch(ctx context.Context, repo domain.RepoMetadata, branch string, commitIDs []string) (int64, error) {
+	if ctx.Err() == context.Canceled {
+		return 0, message.ErrContextCancelled
+	}
+
+	var count int64
+	err := r.DB.WithContext(ctx).Model(&CommitBranch{}).
+		Where("repository_host = ? AND repository_name = ? AND branch = ? AND commit_id IN ?", repo.Host, repo.Name, branch, commitIDs).
+		Count(&count).Error
+	return count, err
+}
+
+// CommitIDsInBranch fetches the ids of the commits recorded in the branch
+func (r *PostgresBranchRepository) CommitIDsInBranch(ctx context.Context, repo domain.RepoMetadata, branch string) ([]string, error) {
+	if ctx.Err() == context.Canceled {
+		return nil, message.ErrContextCancelled
+	}
+
+	var commitIDs []string
+	err := r.DB.WithContext(ctx).Model(&CommitBranch{}).
+		Where("repository_host = ? AND repository_name = ? AND branch = ?", repo.Host, repo.Name, branch).
+		Pluck("commit_id", &commitIDs).Error
+	return commitIDs, err
+}
+
+// RemoveCommitsFromBranch removes the commits from the branch, marking the ones left in no branch orphaned at the time,
+// and returns how many commits were orphaned
+func (r *PostgresBranchRepository) RemoveCommitsFromBranch(ctx context.Context, repo domain.RepoMetadata, branch string, commitIDs []string, orphanedAt time.Time) (int64, error) {
+	if ctx.Err() == context.Canceled {
+		return 0, message.ErrContextCancelled
+	}
+
+	var orphaned int64
+	err := r.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for start := 0; start < len(commitIDs); start += branchCommitBatchSize {
+			batch := commitIDs[start:min(start+branchCommitBatchSize, len(commitIDs))]
+
+			err := tx.Where("repository_host = ? AND repository_name = ? AND branch = ? AND commit_id IN ?", repo.Host, repo.Name, branch, batch).
+				Delete(&CommitBranch{}).Error
+			if err != nil {
+				return err
+			}
+
+			result := tx.Model(&Commit{}).
+				Where("repository_host = ? AND repository_name = ? AND commit_id IN ? AND orphaned_at IS NULL", repo.Host, repo.Name, batch).
+				Where("NOT EXISTS (SELECT 1 FROM commit_branches cb WHERE cb.commit_id = commits.commit_id AND cb.repository_host = ? AND cb.repository_name = ?)", repo.Host, repo.Name).
+				Update("orphaned_at", orphanedAt)
+			if result.Error != nil {
+				return result.Error
+			}
+			orphaned += result.RowsAffected
+		}
+		return nil
+	})
+	return orphaned, err
+}
+
+// SaveHistoryRewrite stores a rewrite found in the history of a branch of the repository
+func (r *PostgresBranchRepository) SaveHistoryRewrite(ctx context.Context, repo domain.RepoMetadata, rewrite domain.HistoryRewrite) error {
+	if ctx.Err() == context.Canceled {
+		return message.ErrContextCancelled
+	}
+
+	return r.DB.WithContext(ctx).Create(FromDomainHistoryRewrite(&repo, &rewrite)).Error
+}
+
+// HistoryRewritesByRepository fetches the rewrites found in the history of the branches of the repository, latest first
+func (r *PostgresBranchRepository) HistoryRewritesByRepository(ctx context.Context, repo domain.RepoMetadata) ([]domain.HistoryRewrite, error) {
+	if ctx.Err() == context.Canceled {
+		return nil, message.ErrContextCancelled
+	}
+
+	var dbRewrites []HistoryRewrite
+	err := r.DB.WithContext(ctx).
+		Where("repository_host = ? AND repository_name = ?", repo.Host, repo.Name).
+		Order("detected_at DESC").
+		Find(&dbRewrites).Error
+	if err != nil {
+		return nil, err
+	}
+
+	rewrites := make([]domain.HistoryRewrite, 0, len(dbRewrites))
+	for _, h := range dbRewrites {
+		rewrites = append(rewrites, *h.ToDomain())
+	}
+	return rewrites, nil
+}
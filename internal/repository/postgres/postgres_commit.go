@@ -6,39 +6,142 @@ import (
 	"github.com/kenmobility/git-api-service/internal/domain"
 )
 
-// Commit represents the GORM model for the commits table.
+// Commit represents the GORM model for the commits table. A commit is unique within its repository, the same
+// commit is stored once per repository mirroring or forking another one.
 type Commit struct {
 	ID             uint   `gorm:"primaryKey"`
-	CommitID       string `gorm:"type:varchar(100);uniqueIndex"`
+	CommitID       string `gorm:"type:varchar(100);index;uniqueIndex:idx_commits_repository_commit,priority:3"`
 	Message        string `gorm:"type:varchar"`
 	Author         string `gorm:"type:varchar"`
+	AuthorEmail    string `gorm:"type:varchar"`
+	AuthorLogin    string `gorm:"type:varchar;index"`
+	AuthorAvatar   string `gorm:"type:varchar"`
 	Date           time.Time
-	URL            string `gorm:"type:varchar"`
-	RepositoryName string `gorm:"type:varchar(100);index"`
+	CommitterName  string `gorm:"type:varchar"`
+	CommitterEmail string `gorm:"type:varchar"`
+	CommitterLogin string `gorm:"type:varchar"`
+	CommitterDate  time.Time
+	ParentSHAs     []string `gorm:"column:parent_shas;type:text;serializer:json"`
+	IsMerge        bool     `gorm:"index"`
+	URL            string   `gorm:"type:varchar"`
+	RepositoryName string   `gorm:"type:varchar(100);index;uniqueIndex:idx_commits_repository_commit,priority:2"`
+	RepositoryHost string   `gorm:"type:varchar;index;uniqueIndex:idx_commits_repository_commit,priority:1"`
+	Additions      int
+	Deletions      int
+	TotalChanges   int
+	EnrichedAt     *time.Time   `gorm:"index"`
+	OrphanedAt     *time.Time   `gorm:"index"`
+	Files          []CommitFile `gorm:"foreignKey:RepositoryHost,RepositoryName,CommitSHA;references:RepositoryHost,RepositoryName,CommitID;constraint:OnDelete:CASCADE"`
 	CreatedAt      time.Time
 	UpdatedAt      time.Time
 }
 
+// CommitFile represents the GORM model for the commit_files table, the files changed by a commit.
+type CommitFile struct {
+	ID               uint   `gorm:"primaryKey"`
+	CommitSHA        string `gorm:"type:varchar(100);index"`
+	RepositoryHost   string `gorm:"type:varchar"`
+	RepositoryName   string `gorm:"type:varchar(100)"`
+	Path             string `gorm:"type:varchar"`
+	Status           string `gorm:"type:varchar(20)"`
+	Additions        int
+	Deletions        int
+	PreviousFilename string `gorm:"type:varchar"`
+}
+
 // ToDomain converts a PostgresCommit to a generic domain entity Commit.
 func (pc *Commit) ToDomain() *domain.Commit {
-	return &domain.Commit{
-		CommitID:       pc.CommitID,
-		Message:        pc.Message,
-		Author:         pc.Author,
-		Date:           pc.Date,
-		URL:            pc.URL,
-		RepositoryName: pc.RepositoryName,
+	commit := &domain.Commit{
+		CommitID:        pc.CommitID,
+		Message:         pc.Message,
+		Author:          pc.Author,
+		AuthorEmail:     pc.AuthorEmail,
+		AuthorLogin:     pc.AuthorLogin,
+		AuthorAvatarURL: pc.AuthorAvatar,
+		Date:            pc.Date,
+		CommitterName:   pc.CommitterName,
+		CommitterEmail:  pc.CommitterEmail,
+		CommitterLogin:  pc.CommitterLogin,
+		CommitterDate:   pc.CommitterDate,
+		ParentSHAs:      pc.ParentSHAs,
+		IsMerge:         pc.IsMerge,
+		URL:             pc.URL,
+		RepositoryName:  pc.RepositoryName,
+		RepositoryHost:  pc.RepositoryHost,
+		Additions:       pc.Additions,
+		Deletions:       pc.Deletions,
+		TotalChanges:    pc.TotalChanges,
+		CreatedAt:       pc.CreatedAt,
+		UpdatedAt:       pc.UpdatedAt,
+	}
+	if pc.EnrichedAt != nil {
+		commit.EnrichedAt = *pc.EnrichedAt
+	}
+	if pc.OrphanedAt != nil {
+		commit.OrphanedAt = *pc.OrphanedAt
+	}
+
+	for _, f := range pc.Files {
+		commit.Files = append(commit.Files, domain.CommitFile{
+			Path:         f.Path,
+			Status:       f.Status,
+			Additions:    f.Additions,
+			Deletions:    f.Deletions,
+			PreviousPath: f.PreviousFilename,
+		})
 	}
+	return commit
 }
 
 // FromDomain creates a PostgresCommit from a generic domain entity Commit.
 func FromDomainCommit(c *domain.Commit) *Commit {
-	return &Commit{
+	commit := &Commit{
 		CommitID:       c.CommitID,
 		Message:        c.Message,
 		Author:         c.Author,
+		AuthorEmail:    c.AuthorEmail,
+		AuthorLogin:    c.AuthorLogin,
+		AuthorAvatar:   c.AuthorAvatarURL,
 		Date:           c.Date,
+		CommitterName:  c.CommitterName,
+		CommitterEmail: c.CommitterEmail,
+		CommitterLogin: c.CommitterLogin,
+		CommitterDate:  c.CommitterDate,
+		ParentSHAs:     c.ParentSHAs,
+		IsMerge:        c.IsMerge,
 		URL:            c.URL,
 		RepositoryName: c.RepositoryName,
+		RepositoryHost: c.RepositoryHost,
+		Additions:      c.Additions,
+		Deletions:      c.Deletions,
+		TotalChanges:   c.TotalChanges,
+	}
+	if !c.EnrichedAt.IsZero() {
+		enrichedAt := c.EnrichedAt
+		commit.EnrichedAt = &enrichedAt
+	}
+	if !c.OrphanedAt.IsZero() {
+		orphanedAt := c.OrphanedAt
+		commit.OrphanedAt = &orphanedAt
+	}
+
+	commit.Files = fromDomainCommitFiles(c, c.Files)
+	return commit
+}
+
+func fromDomainCommitFiles(c *domain.Commit, files []domain.CommitFile) []CommitFile {
+	var commitFiles []CommitFile
+	for _, f := range files {
+		commitFiles = append(commitFiles, CommitFile{
+			CommitSHA:        c.CommitID,
+			RepositoryHost:   c.RepositoryHost,
+			RepositoryName:   c.RepositoryName,
+			Path:             f.Path,
+			Status:           f.Status,
+			Additions:        f.Additions,
+			Deletions:        f.Deletions,
+			PreviousFilename: f.PreviousPath,
+		})
 	}
+	return commitFiles
 }
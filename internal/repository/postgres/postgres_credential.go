@@ -0,0 +1,36 @@
+package postgres
+
+import (
+	"time"
+
+	"github.com/kenmobility/git-api-service/internal/domain"
+)
+
+// Credential represents the Postgres model for the credentials table, the tokens of the repositories encrypted at rest.
+type Credential struct {
+	ID             uint   `gorm:"primarykey"`
+	PublicID       string `gorm:"type:varchar;uniqueIndex"`
+	Name           string `gorm:"type:varchar;uniqueIndex:idx_credentials_name,where:name <> ''"`
+	EncryptedToken string `gorm:"type:text"`
+	CreatedAt      time.Time
+}
+
+// ToDomain converts a Postgres Credential object to domain entity Credential with its decrypted token.
+func (pc *Credential) ToDomain(token string) *domain.Credential {
+	return &domain.Credential{
+		PublicID:  pc.PublicID,
+		Name:      pc.Name,
+		Token:     token,
+		CreatedAt: pc.CreatedAt,
+	}
+}
+
+// FromDomainCredential returns a Postgres Credential object from domain entity Credential with its encrypted token.
+func FromDomainCredential(c *domain.Credential, encryptedToken string) *Credential {
+	return &Credential{
+		PublicID:       c.PublicID,
+		Name:           c.Name,
+		EncryptedToken: encryptedToken,
+		CreatedAt:      c.CreatedAt,
+	}
+}
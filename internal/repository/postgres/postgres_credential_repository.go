@@ -0,0 +1,93 @@
+package postgres
+
+import (
+	"context"
+
+	"github.com/kenmobility/git-api-service/internal/domain"
+	"github.com/kenmobility/git-api-service/internal/repository"
+	"github.com/kenmobility/git-api-service/pkg/message"
+	"github.com/kenmobility/git-api-service/pkg/secrets"
+	"gorm.io/gorm"
+)
+
+type PostgresCredentialRepository struct {
+	DB     *gorm.DB
+	Cipher *secrets.Cipher
+}
+
+// NewPostgresCredentialRepository returns a repository storing the tokens of the credentials encrypted with cipher,
+// which is nil when no encryption key is configured so that credentials are neither stored nor read
+func NewPostgresCredentialRepository(db *gorm.DB, cipher *secrets.Cipher) repository.CredentialRepository {
+	return &PostgresCredentialRepository{DB: db, Cipher: cipher}
+}
+
+// SaveCredential stores the credential with its token encrypted
+func (r *PostgresCredentialRepository) SaveCredential(ctx context.Context, credential domain.Credential) (*domain.Credential, error) {
+	if ctx.Err() == context.Canceled {
+		return nil, message.ErrContextCancelled
+	}
+	if r.Cipher == nil {
+		return nil, message.ErrCredentialsNotConfigured
+	}
+
+	encryptedToken, err := r.Cipher.Encrypt(credential.Token)
+	if err != nil {
+		return nil, err
+	}
+
+	dbCredential := FromDomainCredential(&credential, encryptedToken)
+	if err := r.DB.WithContext(ctx).Create(dbCredential).Error; err != nil {
+		return nil, err
+	}
+	return dbCredential.ToDomain(credential.Token), nil
+}
+
+func (r *PostgresCredentialRepository) CredentialByPublicId(ctx context.Context, publicId string) (*domain.Credential, error) {
+	return r.credentialWhere(ctx, "public_id = ?", publicId)
+}
+
+func (r *PostgresCredentialRepository) CredentialByName(ctx context.Context, name string) (*domain.Credential, error) {
+	return r.credentialWhere(ctx, "name = ?", name)
+}
+
+// AllCredentials fetches the named credentials without their tokens, unnamed ones belong to a single repository and are not listed
+func (r *PostgresCredentialRepository) AllCredentials(ctx context.Context) ([]domain.Credential, error) {
+	if ctx.Err() == context.Canceled {
+		return nil, message.ErrContextCancelled
+	}
+
+	var dbCredentials []Credential
+	if err := r.DB.WithContext(ctx).Where("name <> ''").Order("name").Find(&dbCredentials).Error; err != nil {
+		return nil, err
+	}
+
+	credentials := make([]domain.Credential, 0, len(dbCredentials))
+	for _, c := range dbCredentials {
+		credentials = append(credentials, *c.ToDomain(""))
+	}
+	return credentials, nil
+}
+
+// credentialWhere fetches the credential matching the condition and decrypts its token
+func (r *PostgresCredentialRepository) credentialWhere(ctx context.Context, query string, args ...any) (*domain.Credential, error) {
+	if ctx.Err() == context.Canceled {
+		return nil, message.ErrContextCancelled
+	}
+	if r.Cipher == nil {
+		return nil, message.ErrCredentialsNotConfigured
+	}
+
+	var dbCredential Credential
+	if err := r.DB.WithContext(ctx).Where(query, args...).Find(&dbCredential).Error; err != nil {
+		return nil, err
+	}
+	if dbCredential.ID == 0 {
+		return nil, message.ErrCredentialNotFound
+	}
+
+	token, err := r.Cipher.Decrypt(dbCredential.EncryptedToken)
+	if err != nil {
+		return nil, err
+	}
+	return dbCredential.ToDomain(token), nil
+}
@@ -22,13 +22,13 @@ func NewPostgresGitCommitRepository(db *gorm.DB) repository.CommitRepository {
 	}
 }
 
-// GetByCommitID fetches a commit using commit ID
-func (gc *PostgresGitCommitRepository) GetByCommitID(ctx context.Context, commitID string) (*domain.Commit, error) {
+// GetByCommitID fetches a commit of the repository using commit ID
+func (gc *PostgresGitCommitRepository) GetByCommitID(ctx context.Context, repo domain.RepoMetadata, commitID string) (*domain.Commit, error) {
 	if ctx.Err() == context.Canceled {
 		return nil, message.ErrContextCancelled
 	}
 	var commit Commit
-	err := gc.DB.WithContext(ctx).Where("commit_id = ?", commitID).Find(&commit).Error
+	err := gc.DB.WithContext(ctx).Where("repository_host = ? AND repository_name = ? AND commit_id = ?", repo.Host, repo.Name, commitID).Find(&commit).Error
 
 	if commit.ID == 0 {
 		return nil, message.ErrNoRecordFound
@@ -47,7 +47,7 @@ func (gc *PostgresGitCommitRepository) SaveCommit(ctx context.Context, commit do
 	tx := gc.DB.WithContext(ctx).Create(&dbCommit)
 
 	if tx.Error != nil {
-		if strings.Contains(tx.Error.Error(), `duplicate key value violates unique constraint "idx_commits_commit_id"`) {
+		if strings.Contains(tx.Error.Error(), `duplicate key value violates unique constraint "idx_commits_repository_commit"`) {
 			log.Warn().Msgf("already saved commit-id:%s", commit.CommitID)
 			return nil, tx.Error
 		} else {
@@ -58,15 +58,25 @@ func (gc *PostgresGitCommitRepository) SaveCommit(ctx context.Context, commit do
 	return dbCommit.ToDomain(), nil
 }
 
-// AllCommitsByRepository fetches all stores commits by repository name
-func (gc *PostgresGitCommitRepository) AllCommitsByRepository(ctx context.Context, r domain.RepoMetadata, query domain.APIPagingData) ([]domain.Commit, *domain.PagingInfo, error) {
+// AllCommitsByRepository fetches all stores commits by repository name, narrowed to the commits of a branch by the filter,
+// orphaned commits are left out unless the filter includes them
+func (gc *PostgresGitCommitRepository) AllCommitsByRepository(ctx context.Context, r domain.RepoMetadata, filter domain.CommitFilter, query domain.APIPagingData) ([]domain.Commit, *domain.PagingInfo, error) {
 	var dbCommits []Commit
 
 	var count, queryCount int64
 
 	queryInfo, offset := repository.GetQueryPaginationData(query)
 
-	db := gc.DB.WithContext(ctx).Model(&Commit{}).Where(&Commit{RepositoryName: r.Name})
+	db := gc.DB.WithContext(ctx).Model(&Commit{}).Where(&Commit{RepositoryName: r.Name, RepositoryHost: r.Host})
+
+	if filter.Branch != "" {
+		db = db.Where("EXISTS (SELECT 1 FROM commit_branches cb WHERE cb.commit_id = commits.commit_id AND cb.repository_host = ? AND cb.repository_name = ? AND cb.branch = ?)",
+			r.Host, r.Name, filter.Branch)
+	}
+
+	if !filter.IncludeOrphaned {
+		db = db.Where("commits.orphaned_at IS NULL")
+	}
 
 	db.Count(&count)
 
@@ -89,9 +99,9 @@ func (gc *PostgresGitCommitRepository) AllCommitsByRepository(ctx context.Contex
 
 func (gc *PostgresGitCommitRepository) TopCommitAuthorsByRepository(ctx context.Context, repo domain.RepoMetadata, limit int) ([]domain.AuthorCommitCount, error) {
 	var results []domain.AuthorCommitCount
-	err := gc.DB.WithContext(ctx).Model(&domain.Commit{}).
+	err := gc.DB.WithContext(ctx).Model(&Commit{}).
 		Select("author, COUNT(author) as commit_count").
-		Where("repository_name = ?", repo.Name).
+		Where("repository_name = ? AND repository_host = ?", repo.Name, repo.Host).
 		Group("author").
 		Order("commit_count DESC").
 		Limit(limit).
@@ -100,6 +110,99 @@ func (gc *PostgresGitCommitRepository) TopCommitAuthorsByRepository(ctx context.
 	return results, err
 }
 
+// CommitWithFiles fetches a commit of the repository along with the files it changed and the tracked branches containing it
+func (gc *PostgresGitCommitRepository) CommitWithFiles(ctx context.Context, repo domain.RepoMetadata, commitID string) (*domain.Commit, error) {
+	if ctx.Err() == context.Canceled {
+		return nil, message.ErrContextCancelled
+	}
+
+	var commit Commit
+	err := gc.DB.WithContext(ctx).Preload("Files", func(db *gorm.DB) *gorm.DB {
+		return db.Order("commit_files.id")
+	}).Where("commit_id = ? AND repository_name = ? AND repository_host = ?", commitID, repo.Name, repo.Host).Find(&commit).Error
+	if err != nil {
+		return nil, err
+	}
+
+	if commit.ID == 0 {
+		return nil, message.ErrNoRecordFound
+	}
+
+	var branches []string
+	err = gc.DB.WithContext(ctx).Model(&CommitBranch{}).
+		Where("commit_id = ? AND repository_host = ? AND repository_name = ?", commitID, repo.Host, repo.Name).
+		Order("branch").
+		Pluck("branch", &branches).Error
+	if err != nil {
+		return nil, err
+	}
+
+	domainCommit := commit.ToDomain()
+	domainCommit.Branches = branches
+	return domainCommit, nil
+}
+
+// UnenrichedCommits fetches up to limit commits of the repository whose changes were not fetched yet, newest first
+func (gc *PostgresGitCommitRepository) UnenrichedCommits(ctx context.Context, repo domain.RepoMetadata, limit int) ([]domain.Commit, error) {
+	var dbCommits []Commit
+
+	err := gc.DB.WithContext(ctx).
+		Where("repository_name = ? AND repository_host = ? AND enriched_at IS NULL", repo.Name, repo.Host).
+		Order("date DESC").
+		Limit(limit).
+		Find(&dbCommits).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return domainCommits(dbCommits), nil
+}
+
+// SaveCommitChanges stores the change statistics of the commit and replaces the files it changed. The parents,
+// author avatar and committer date are stored as well when known, commits saved from push webhooks have none of them
+func (gc *PostgresGitCommitRepository) SaveCommitChanges(ctx context.Context, commit domain.Commit) error {
+	if ctx.Err() == context.Canceled {
+		return message.ErrContextCancelled
+	}
+
+	dbCommit := FromDomainCommit(&commit)
+
+	return gc.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(&Commit{}).Where("repository_host = ? AND repository_name = ? AND commit_id = ?", commit.RepositoryHost, commit.RepositoryName, commit.CommitID).Updates(map[string]any{
+			"additions":     dbCommit.Additions,
+			"deletions":     dbCommit.Deletions,
+			"total_changes": dbCommit.TotalChanges,
+			"enriched_at":   dbCommit.EnrichedAt,
+		}).Error
+		if err != nil {
+			return err
+		}
+
+		if len(dbCommit.ParentSHAs) > 0 {
+			err := tx.Model(&Commit{}).Where("repository_host = ? AND repository_name = ? AND commit_id = ?", commit.RepositoryHost, commit.RepositoryName, commit.CommitID).
+				Select("parent_shas", "is_merge", "author_avatar", "committer_date").
+				Updates(&Commit{
+					ParentSHAs:    dbCommit.ParentSHAs,
+					IsMerge:       dbCommit.IsMerge,
+					AuthorAvatar:  dbCommit.AuthorAvatar,
+					CommitterDate: dbCommit.CommitterDate,
+				}).Error
+			if err != nil {
+				return err
+			}
+		}
+
+		if err := tx.Where("repository_host = ? AND repository_name = ? AND commit_sha = ?", commit.RepositoryHost, commit.RepositoryName, commit.CommitID).Delete(&CommitFile{}).Error; err != nil {
+			return err
+		}
+
+		if len(dbCommit.Files) == 0 {
+			return nil
+		}
+		return tx.CreateInBatches(dbCommit.Files, 100).Error
+	})
+}
+
 func domainCommits(dbCommits []Commit) []domain.Commit {
 	if len(dbCommits) == 0 {
 		return []domain.Commit{}
@@ -107,19 +210,8 @@ func domainCommits(dbCommits []Commit) []domain.Commit {
 
 	domainCommits := make([]domain.Commit, 0, len(dbCommits))
 
-	for _, c := range dbCommits {
-		cr := domain.Commit{
-			CommitID:       c.CommitID,
-			Message:        c.Message,
-			Author:         c.Author,
-			Date:           c.Date,
-			URL:            c.URL,
-			RepositoryName: c.RepositoryName,
-			CreatedAt:      c.CreatedAt,
-			UpdatedAt:      c.UpdatedAt,
-		}
-
-		domainCommits = append(domainCommits, cr)
+	for i := range dbCommits {
+		domainCommits = append(domainCommits, *dbCommits[i].ToDomain())
 	}
 
 	return domainCommits
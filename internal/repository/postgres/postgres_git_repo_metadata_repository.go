@@ -2,6 +2,7 @@ package postgres
 
 import (
 	"context"
+	"strings"
 
 	"github.com/kenmobility/git-api-service/internal/domain"
 	"github.com/kenmobility/git-api-service/internal/repository"
@@ -10,6 +11,13 @@ import (
 	"gorm.io/gorm"
 )
 
+// refreshedColumns are the repository metadata columns kept up to date by RefreshRepoMetadata
+var refreshedColumns = []string{"description", "url", "language", "default_branch",
+	"forks_count", "stars_count", "open_issues_count", "watchers_count", "status", "status_changed_at"}
+
+// renamedModels are the models keeping the name of their repository, rewritten by RenameRepository
+var renamedModels = []any{&Commit{}, &Branch{}, &CommitBranch{}, &Tag{}, &Release{}, &PullRequest{}, &PullRequestCommit{}, &RepoSnapshot{}, &HistoryRewrite{}}
+
 type PostgresGitRepoMetadataRepository struct {
 	DB *gorm.DB
 }
@@ -23,6 +31,10 @@ func (r *PostgresGitRepoMetadataRepository) SaveRepoMetadata(ctx context.Context
 
 	err := r.DB.WithContext(ctx).Create(dbRepository).Error
 	if err != nil {
+		// the repository was added concurrently, eg by another instance seeding the default repository
+		if strings.Contains(err.Error(), `duplicate key value violates unique constraint "idx_repositories_host_name"`) {
+			return nil, message.ErrRepoAlreadyAdded
+		}
 		return nil, err
 	}
 
@@ -43,12 +55,12 @@ func (r *PostgresGitRepoMetadataRepository) RepoMetadataByPublicId(ctx context.C
 	return repo.ToDomain(), err
 }
 
-func (r *PostgresGitRepoMetadataRepository) RepoMetadataByName(ctx context.Context, name string) (*domain.RepoMetadata, error) {
+func (r *PostgresGitRepoMetadataRepository) RepoMetadataByName(ctx context.Context, host string, name string) (*domain.RepoMetadata, error) {
 	if ctx.Err() == context.Canceled {
 		return nil, message.ErrContextCancelled
 	}
 	var repo Repository
-	err := r.DB.WithContext(ctx).Where("name = ?", name).Find(&repo).Error
+	err := r.DB.WithContext(ctx).Where("host = ? AND name = ?", host, name).Find(&repo).Error
 	if repo.ID == 0 {
 		return nil, message.ErrNoRecordFound
 	}
@@ -78,7 +90,12 @@ func (r *PostgresGitRepoMetadataRepository) UpdateRepoMetadata(ctx context.Conte
 	}
 	dbRepo := FromDomainRepo(&repo)
 
-	err := r.DB.WithContext(ctx).Model(&Repository{}).Where(&Repository{PublicID: repo.PublicID}).Updates(&dbRepo).Error
+	// every column is written, so that zero values such as a cleared cursor or isFetching=false are persisted too,
+	// except the name, the tracked branches and the refreshed metadata which are only changed by RenameRepository,
+	// UpdateTrackedBranches and RefreshRepoMetadata while indexing goes on
+	omitted := append([]string{"id", "public_id", "created_at", "name", "tracked_branches"}, refreshedColumns...)
+	err := r.DB.WithContext(ctx).Model(&Repository{}).Where(&Repository{PublicID: repo.PublicID}).
+		Select("*").Omit(omitted...).Updates(&dbRepo).Error
 	if err != nil {
 		log.Error().Msgf("Persistence::UpdateRepoMetadaa error: %v, (%v)", err.Error(), err.Error())
 		return nil, err
@@ -87,9 +104,64 @@ func (r *PostgresGitRepoMetadataRepository) UpdateRepoMetadata(ctx context.Conte
 	return dbRepo.ToDomain(), nil
 }
 
-func (r *PostgresGitRepoMetadataRepository) UpdateFetchingStateForAllRepos(ctx context.Context, isFetching bool) error {
-	return r.DB.WithContext(ctx).Model(&Repository{}).
-		Where("is_fetching = ?", true).
-		Update("is_fetching", isFetching).
-		Error
+// UpdateTrackedBranches replaces the tracked branches of the repository
+func (r *PostgresGitRepoMetadataRepository) UpdateTrackedBranches(ctx context.Context, publicId string, branches []string) (*domain.RepoMetadata, error) {
+	if ctx.Err() == context.Canceled {
+		return nil, message.ErrContextCancelled
+	}
+
+	// updating from a struct applies the json serializer of the column
+	err := r.DB.WithContext(ctx).Model(&Repository{}).Where(&Repository{PublicID: publicId}).
+		Select("tracked_branches").Updates(&Repository{TrackedBranches: branches}).Error
+	if err != nil {
+		log.Error().Msgf("Persistence::UpdateTrackedBranches error: %v, (%v)", err.Error(), err.Error())
+		return nil, err
+	}
+
+	return r.RepoMetadataByPublicId(ctx, publicId)
+}
+
+// RefreshRepoMetadata updates the metadata of the repository fetched again from its git host
+func (r *PostgresGitRepoMetadataRepository) RefreshRepoMetadata(ctx context.Context, repo domain.RepoMetadata) error {
+	if ctx.Err() == context.Canceled {
+		return message.ErrContextCancelled
+	}
+
+	err := r.DB.WithContext(ctx).Model(&Repository{}).Where(&Repository{PublicID: repo.PublicID}).
+		Select(refreshedColumns).Updates(FromDomainRepo(&repo)).Error
+	if err != nil {
+		log.Error().Msgf("Persistence::RefreshRepoMetadata error: %v, (%v)", err.Error(), err.Error())
+		return err
+	}
+	return nil
+}
+
+// RenameRepository renames the repository after it was renamed or transferred on its git host, along with
+// its commits, branches, tags, releases, pull requests and snapshots
+func (r *PostgresGitRepoMetadataRepository) RenameRepository(ctx context.Context, repo domain.RepoMetadata, name string) (*domain.RepoMetadata, error) {
+	if ctx.Err() == context.Canceled {
+		return nil, message.ErrContextCancelled
+	}
+
+	err := r.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(&Repository{}).Where(&Repository{PublicID: repo.PublicID}).Update("name", name).Error
+		if err != nil {
+			return err
+		}
+
+		for _, model := range renamedModels {
+			err := tx.Model(model).Where("repository_host = ? AND repository_name = ?", repo.Host, repo.Name).
+				Update("repository_name", name).Error
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		log.Error().Msgf("Persistence::RenameRepository error: %v, (%v)", err.Error(), err.Error())
+		return nil, err
+	}
+
+	return r.RepoMetadataByPublicId(ctx, repo.PublicID)
 }
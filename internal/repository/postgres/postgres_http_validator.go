@@ -0,0 +1,37 @@
+package postgres
+
+import (
+	"time"
+
+	"github.com/kenmobility/git-api-service/internal/domain"
+)
+
+// HTTPValidator represents the Postgres model for the http_validators table.
+type HTTPValidator struct {
+	ID           uint   `gorm:"primarykey"`
+	Endpoint     string `gorm:"type:text;uniqueIndex"`
+	ETag         string `gorm:"column:etag;type:varchar"`
+	LastModified string `gorm:"type:varchar"`
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+}
+
+// ToDomain converts a Postgres HTTPValidator object to domain entity HTTPValidator.
+func (pv *HTTPValidator) ToDomain() *domain.HTTPValidator {
+	return &domain.HTTPValidator{
+		Endpoint:     pv.Endpoint,
+		ETag:         pv.ETag,
+		LastModified: pv.LastModified,
+		UpdatedAt:    pv.UpdatedAt,
+	}
+}
+
+// FromDomainHTTPValidator returns a Postgres HTTPValidator object from domain entity HTTPValidator.
+func FromDomainHTTPValidator(v *domain.HTTPValidator) *HTTPValidator {
+	return &HTTPValidator{
+		Endpoint:     v.Endpoint,
+		ETag:         v.ETag,
+		LastModified: v.LastModified,
+		UpdatedAt:    v.UpdatedAt,
+	}
+}
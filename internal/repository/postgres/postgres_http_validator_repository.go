@@ -0,0 +1,48 @@
+package postgres
+
+import (
+	"context"
+
+	"github.com/kenmobility/git-api-service/internal/domain"
+	"github.com/kenmobility/git-api-service/internal/repository"
+	"github.com/kenmobility/git-api-service/pkg/message"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type PostgresHTTPValidatorRepository struct {
+	DB *gorm.DB
+}
+
+func NewPostgresHTTPValidatorRepository(db *gorm.DB) repository.HTTPValidatorRepository {
+	return &PostgresHTTPValidatorRepository{DB: db}
+}
+
+// ValidatorByEndpoint fetches the cache validators stored for an endpoint
+func (r *PostgresHTTPValidatorRepository) ValidatorByEndpoint(ctx context.Context, endpoint string) (*domain.HTTPValidator, error) {
+	if ctx.Err() == context.Canceled {
+		return nil, message.ErrContextCancelled
+	}
+
+	var validator HTTPValidator
+	err := r.DB.WithContext(ctx).Where("endpoint = ?", endpoint).Find(&validator).Error
+
+	if validator.ID == 0 {
+		return nil, message.ErrNoRecordFound
+	}
+	return validator.ToDomain(), err
+}
+
+// SaveValidator stores the cache validators of an endpoint, replacing the ones stored before
+func (r *PostgresHTTPValidatorRepository) SaveValidator(ctx context.Context, validator domain.HTTPValidator) error {
+	if ctx.Err() == context.Canceled {
+		return message.ErrContextCancelled
+	}
+
+	dbValidator := FromDomainHTTPValidator(&validator)
+
+	return r.DB.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "endpoint"}},
+		DoUpdates: clause.AssignmentColumns([]string{"etag", "last_modified", "updated_at"}),
+	}).Create(dbValidator).Error
+}
@@ -0,0 +1,56 @@
+package postgres
+
+import (
+	"time"
+
+	"github.com/kenmobility/git-api-service/internal/domain"
+)
+
+// Job represents the Postgres model for the jobs table, the queue of the background work on the repositories.
+// A repository has at most one pending or running job of each kind.
+type Job struct {
+	ID           uint   `gorm:"primarykey"`
+	PublicID     string `gorm:"type:varchar;uniqueIndex"`
+	Kind         string `gorm:"type:varchar(20);uniqueIndex:idx_jobs_active_repository_kind,where:status <> 'done' AND status <> 'dead'"`
+	RepositoryID string `gorm:"type:varchar;uniqueIndex:idx_jobs_active_repository_kind"`
+	Status       string `gorm:"type:varchar(20);index:idx_jobs_status_run_at"`
+	Attempts     int
+	MaxAttempts  int
+	RunAt        time.Time `gorm:"index:idx_jobs_status_run_at"`
+	LockedUntil  time.Time
+	LastError    string `gorm:"type:varchar"`
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+}
+
+// ToDomain converts a Postgres Job object to domain entity Job.
+func (pj *Job) ToDomain() *domain.Job {
+	return &domain.Job{
+		PublicID:     pj.PublicID,
+		Kind:         pj.Kind,
+		RepositoryID: pj.RepositoryID,
+		Status:       pj.Status,
+		Attempts:     pj.Attempts,
+		MaxAttempts:  pj.MaxAttempts,
+		RunAt:        pj.RunAt,
+		LockedUntil:  pj.LockedUntil,
+		LastError:    pj.LastError,
+		CreatedAt:    pj.CreatedAt,
+		UpdatedAt:    pj.UpdatedAt,
+	}
+}
+
+// FromDomainJob returns a Postgres Job object from domain entity Job.
+func FromDomainJob(j *domain.Job) *Job {
+	return &Job{
+		PublicID:     j.PublicID,
+		Kind:         j.Kind,
+		RepositoryID: j.RepositoryID,
+		Status:       j.Status,
+		Attempts:     j.Attempts,
+		MaxAttempts:  j.MaxAttempts,
+		RunAt:        j.RunAt,
+		LockedUntil:  j.LockedUntil,
+		LastError:    j.LastError,
+	}
+}
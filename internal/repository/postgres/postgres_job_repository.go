@@ -0,0 +1,209 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/kenmobility/git-api-service/internal/domain"
+	"github.com/kenmobility/git-api-service/internal/repository"
+	"github.com/kenmobility/git-api-service/pkg/message"
+	"github.com/rs/zerolog/log"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type PostgresJobRepository struct {
+	DB *gorm.DB
+}
+
+func NewPostgresJobRepository(db *gorm.DB) repository.JobRepository {
+	return &PostgresJobRepository{DB: db}
+}
+
+// EnqueueJob queues the job, it is skipped when the repository already has a pending or running job of its kind
+func (r *PostgresJobRepository) EnqueueJob(ctx context.Context, job domain.Job) error {
+	if ctx.Err() == context.Canceled {
+		return message.ErrContextCancelled
+	}
+
+	return r.DB.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(FromDomainJob(&job)).Error
+}
+
+// ClaimJob claims the pending job due first, or a running job whose lock expired because its worker stopped, and
+// hides it from the other workers for the visibility timeout. Jobs locked by a concurrent claim are skipped, so
+// workers of several instances can claim from the same queue. ErrNoRecordFound is returned when no job is due
+func (r *PostgresJobRepository) ClaimJob(ctx context.Context, visibilityTimeout time.Duration) (*domain.Job, error) {
+	if ctx.Err() == context.Canceled {
+		return nil, message.ErrContextCancelled
+	}
+
+	var job Job
+	err := r.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("(status = ? AND run_at <= ?) OR (status = ? AND locked_until <= ?)", domain.JobStatusPending, now, domain.JobStatusRunning, now).
+			Order("run_at").
+			Limit(1).
+			Find(&job).Error
+		if err != nil {
+			return err
+		}
+		if job.ID == 0 {
+			return message.ErrNoRecordFound
+		}
+
+		job.Status = domain.JobStatusRunning
+		job.Attempts++
+		job.LockedUntil = now.Add(visibilityTimeout)
+
+		return tx.Model(&job).Select("status", "attempts", "locked_until", "updated_at").Updates(&job).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return job.ToDomain(), nil
+}
+
+// ExtendJobLock keeps the running job hidden from the other workers until lockedUntil
+func (r *PostgresJobRepository) ExtendJobLock(ctx context.Context, job domain.Job, lockedUntil time.Time) error {
+	return r.updateRunningJob(ctx, job, map[string]any{
+		"locked_until": lockedUntil,
+	})
+}
+
+// CompleteJob marks the running job done
+func (r *PostgresJobRepository) CompleteJob(ctx context.Context, job domain.Job) error {
+	return r.updateRunningJob(ctx, job, map[string]any{
+		"status":     domain.JobStatusDone,
+		"last_error": "",
+	})
+}
+
+// RescheduleJob queues the running job again to run at runAt, with its attempts reset, as done by recurring jobs
+func (r *PostgresJobRepository) RescheduleJob(ctx context.Context, job domain.Job, runAt time.Time) error {
+	return r.updateRunningJob(ctx, job, map[string]any{
+		"status":     domain.JobStatusPending,
+		"attempts":   0,
+		"run_at":     runAt,
+		"last_error": "",
+	})
+}
+
+// RetryJob queues the failed running job again to run at runAt, keeping its attempts
+func (r *PostgresJobRepository) RetryJob(ctx context.Context, job domain.Job, runAt time.Time, lastError string) error {
+	return r.updateRunningJob(ctx, job, map[string]any{
+		"status":     domain.JobStatusPending,
+		"run_at":     runAt,
+		"last_error": lastError,
+	})
+}
+
+// DeadLetterJob moves the running job to the dead state, where it stays until requeued
+func (r *PostgresJobRepository) DeadLetterJob(ctx context.Context, job domain.Job, lastError string) error {
+	return r.updateRunningJob(ctx, job, map[string]any{
+		"status":     domain.JobStatusDead,
+		"last_error": lastError,
+	})
+}
+
+// ReleaseJob queues the running job again to run at runAt, the attempt it was interrupted on is not counted
+func (r *PostgresJobRepository) ReleaseJob(ctx context.Context, job domain.Job, runAt time.Time) error {
+	return r.updateRunningJob(ctx, job, map[string]any{
+		"status":   domain.JobStatusPending,
+		"attempts": gorm.Expr("attempts - 1"),
+		"run_at":   runAt,
+	})
+}
+
+// updateRunningJob updates the job as long as it is still running the attempt it was claimed for, the update of a worker
+// whose lock expired and whose job was claimed again is rejected with ErrJobLockLost
+func (r *PostgresJobRepository) updateRunningJob(ctx context.Context, job domain.Job, updates map[string]any) error {
+	if ctx.Err() == context.Canceled {
+		return message.ErrContextCancelled
+	}
+
+	updates["updated_at"] = time.Now()
+
+	result := r.DB.WithContext(ctx).Model(&Job{}).
+		Where("public_id = ? AND status = ? AND attempts = ?", job.PublicID, domain.JobStatusRunning, job.Attempts).
+		Updates(updates)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return message.ErrJobLockLost
+	}
+	return nil
+}
+
+// RequeueJob queues the dead job again with its attempts reset
+func (r *PostgresJobRepository) RequeueJob(ctx context.Context, publicID string) (*domain.Job, error) {
+	if ctx.Err() == context.Canceled {
+		return nil, message.ErrContextCancelled
+	}
+
+	var job Job
+	err := r.DB.WithContext(ctx).Where("public_id = ?", publicID).Find(&job).Error
+	if err != nil {
+		return nil, err
+	}
+	if job.ID == 0 {
+		return nil, message.ErrNoRecordFound
+	}
+	if job.Status != domain.JobStatusDead {
+		return nil, message.ErrJobNotDead
+	}
+
+	job.Status = domain.JobStatusPending
+	job.Attempts = 0
+	job.RunAt = time.Now()
+
+	err = r.DB.WithContext(ctx).Model(&job).Select("status", "attempts", "run_at", "updated_at").Updates(&job).Error
+	if err != nil {
+		if strings.Contains(err.Error(), `duplicate key value violates unique constraint "idx_jobs_active_repository_kind"`) {
+			return nil, message.ErrJobAlreadyQueued
+		}
+		return nil, err
+	}
+	return job.ToDomain(), nil
+}
+
+// AllJobs fetches the jobs, narrowed to the ones in the status when it is given
+func (r *PostgresJobRepository) AllJobs(ctx context.Context, status string, query domain.APIPagingData) ([]domain.Job, *domain.PagingInfo, error) {
+	if ctx.Err() == context.Canceled {
+		return nil, nil, message.ErrContextCancelled
+	}
+
+	var dbJobs []Job
+	var count int64
+
+	queryInfo, offset := repository.GetQueryPaginationData(query)
+
+	db := r.DB.WithContext(ctx).Model(&Job{})
+	if status != "" {
+		db = db.Where("status = ?", status)
+	}
+	db.Count(&count)
+
+	db = db.Offset(offset).Limit(queryInfo.Limit).
+		Order(fmt.Sprintf("%s %s", queryInfo.Sort, queryInfo.Direction)).
+		Find(&dbJobs)
+
+	if db.Error != nil {
+		log.Info().Msgf("fetch jobs error %v", db.Error.Error())
+
+		return nil, nil, db.Error
+	}
+
+	pagingInfo := repository.PagingInfo(queryInfo, int(count))
+	pagingInfo.Count = len(dbJobs)
+
+	jobs := make([]domain.Job, 0, len(dbJobs))
+	for _, j := range dbJobs {
+		jobs = append(jobs, *j.ToDomain())
+	}
+	return jobs, &pagingInfo, nil
+}
@@ -0,0 +1,91 @@
+package postgres
+
+import (
+	"time"
+
+	"github.com/kenmobility/git-api-service/internal/domain"
+)
+
+// PullRequest represents the Postgres model for the pull_requests table, the pull requests of the repositories.
+type PullRequest struct {
+	ID             uint   `gorm:"primarykey"`
+	RepositoryHost string `gorm:"type:varchar;uniqueIndex:idx_pull_requests_repository_number"`
+	RepositoryName string `gorm:"type:varchar(100);uniqueIndex:idx_pull_requests_repository_number"`
+	Number         int    `gorm:"uniqueIndex:idx_pull_requests_repository_number"`
+	Title          string `gorm:"type:varchar"`
+	State          string `gorm:"type:varchar(20);index"`
+	Author         string `gorm:"type:varchar"`
+	URL            string `gorm:"type:varchar"`
+	BaseRef        string `gorm:"type:varchar"`
+	HeadRef        string `gorm:"type:varchar"`
+	MergeCommitSHA string `gorm:"column:merge_commit_sha;type:varchar(100)"`
+	// OpenedAt and HostUpdatedAt are when the pull request was opened and last updated on its host
+	OpenedAt      time.Time
+	HostUpdatedAt time.Time `gorm:"index"`
+	MergedAt      *time.Time
+	ClosedAt      *time.Time
+	// CommitsLinkedAt is when the commits of the merged pull request were linked to it, nil until then
+	CommitsLinkedAt *time.Time `gorm:"index"`
+	CreatedAt       time.Time
+	UpdatedAt       time.Time
+}
+
+// PullRequestCommit represents the Postgres model for the pull_request_commits table, the commits of each merged pull request.
+type PullRequestCommit struct {
+	ID             uint   `gorm:"primarykey"`
+	RepositoryHost string `gorm:"type:varchar;uniqueIndex:idx_pull_request_commits_membership"`
+	RepositoryName string `gorm:"type:varchar(100);uniqueIndex:idx_pull_request_commits_membership"`
+	Number         int    `gorm:"uniqueIndex:idx_pull_request_commits_membership"`
+	CommitID       string `gorm:"type:varchar(100);uniqueIndex:idx_pull_request_commits_membership;index"`
+	CreatedAt      time.Time
+}
+
+// ToDomain converts a Postgres PullRequest object to domain entity PullRequest.
+func (pp *PullRequest) ToDomain() *domain.PullRequest {
+	pullRequest := &domain.PullRequest{
+		Number:         pp.Number,
+		Title:          pp.Title,
+		State:          pp.State,
+		Author:         pp.Author,
+		URL:            pp.URL,
+		BaseRef:        pp.BaseRef,
+		HeadRef:        pp.HeadRef,
+		MergeCommitSHA: pp.MergeCommitSHA,
+		CreatedAt:      pp.OpenedAt,
+		UpdatedAt:      pp.HostUpdatedAt,
+	}
+	if pp.MergedAt != nil {
+		pullRequest.MergedAt = *pp.MergedAt
+	}
+	if pp.ClosedAt != nil {
+		pullRequest.ClosedAt = *pp.ClosedAt
+	}
+	return pullRequest
+}
+
+// FromDomainPullRequest returns a Postgres PullRequest object of the repository from domain entity PullRequest.
+func FromDomainPullRequest(r *domain.RepoMetadata, p *domain.PullRequest) *PullRequest {
+	pullRequest := &PullRequest{
+		RepositoryHost: r.Host,
+		RepositoryName: r.Name,
+		Number:         p.Number,
+		Title:          p.Title,
+		State:          p.State,
+		Author:         p.Author,
+		URL:            p.URL,
+		BaseRef:        p.BaseRef,
+		HeadRef:        p.HeadRef,
+		MergeCommitSHA: p.MergeCommitSHA,
+		OpenedAt:       p.CreatedAt,
+		HostUpdatedAt:  p.UpdatedAt,
+	}
+	if !p.MergedAt.IsZero() {
+		mergedAt := p.MergedAt
+		pullRequest.MergedAt = &mergedAt
+	}
+	if !p.ClosedAt.IsZero() {
+		closedAt := p.ClosedAt
+		pullRequest.ClosedAt = &closedAt
+	}
+	return pullRequest
+}
@@ -0,0 +1,194 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/kenmobility/git-api-service/internal/domain"
+	"github.com/kenmobility/git-api-service/internal/repository"
+	"github.com/kenmobility/git-api-service/pkg/message"
+	"github.com/rs/zerolog/log"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type PostgresPullRequestRepository struct {
+	DB *gorm.DB
+}
+
+func NewPostgresPullRequestRepository(db *gorm.DB) repository.PullRequestRepository {
+	return &PostgresPullRequestRepository{DB: db}
+}
+
+// SavePullRequests stores the pull requests of the repository, replacing the ones stored before
+func (r *PostgresPullRequestRepository) SavePullRequests(ctx context.Context, repo domain.RepoMetadata, pullRequests []domain.PullRequest) error {
+	if ctx.Err() == context.Canceled {
+		return message.ErrContextCancelled
+	}
+
+	if len(pullRequests) == 0 {
+		return nil
+	}
+
+	dbPullRequests := make([]PullRequest, 0, len(pullRequests))
+	for i := range pullRequests {
+		dbPullRequests = append(dbPullRequests, *FromDomainPullRequest(&repo, &pullRequests[i]))
+	}
+
+	return r.DB.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "repository_host"}, {Name: "repository_name"}, {Name: "number"}},
+		DoUpdates: clause.AssignmentColumns([]string{"title", "state", "author", "url", "base_ref", "head_ref", "merge_commit_sha",
+			"opened_at", "host_updated_at", "merged_at", "closed_at", "updated_at"}),
+	}).CreateInBatches(&dbPullRequests, syncBatchSize).Error
+}
+
+// LatestPullRequestUpdate returns when the most recently updated pull request of the repository was updated on its host,
+// zero when none is stored
+func (r *PostgresPullRequestRepository) LatestPullRequestUpdate(ctx context.Context, repo domain.RepoMetadata) (time.Time, error) {
+	if ctx.Err() == context.Canceled {
+		return time.Time{}, message.ErrContextCancelled
+	}
+
+	var latest sql.NullTime
+	err := r.DB.WithContext(ctx).Model(&PullRequest{}).
+		Select("MAX(host_updated_at)").
+		Where("repository_host = ? AND repository_name = ?", repo.Host, repo.Name).
+		Row().Scan(&latest)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return latest.Time, nil
+}
+
+// UnlinkedPullRequests fetches the merged pull requests of the repository whose commits were not linked yet, earliest merged first
+func (r *PostgresPullRequestRepository) UnlinkedPullRequests(ctx context.Context, repo domain.RepoMetadata, limit int) ([]domain.PullRequest, error) {
+	if ctx.Err() == context.Canceled {
+		return nil, message.ErrContextCancelled
+	}
+
+	var dbPullRequests []PullRequest
+	err := r.DB.WithContext(ctx).
+		Where("repository_host = ? AND repository_name = ? AND merged_at IS NOT NULL AND commits_linked_at IS NULL", repo.Host, repo.Name).
+		Order("merged_at").
+		Limit(limit).
+		Find(&dbPullRequests).Error
+	if err != nil {
+		return nil, err
+	}
+
+	pullRequests := make([]domain.PullRequest, 0, len(dbPullRequests))
+	for _, p := range dbPullRequests {
+		pullRequests = append(pullRequests, *p.ToDomain())
+	}
+	return pullRequests, nil
+}
+
+// LinkPullRequestCommits records the commits of the pull request and marks its commits linked
+func (r *PostgresPullRequestRepository) LinkPullRequestCommits(ctx context.Context, repo domain.RepoMetadata, number int, commitIDs []string) error {
+	if ctx.Err() == context.Canceled {
+		return message.ErrContextCancelled
+	}
+
+	memberships := make([]PullRequestCommit, 0, len(commitIDs))
+	for _, commitID := range commitIDs {
+		memberships = append(memberships, PullRequestCommit{
+			RepositoryHost: repo.Host,
+			RepositoryName: repo.Name,
+			Number:         number,
+			CommitID:       commitID,
+		})
+	}
+
+	return r.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if len(memberships) > 0 {
+			if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&memberships).Error; err != nil {
+				return err
+			}
+		}
+
+		return tx.Model(&PullRequest{}).
+			Where("repository_host = ? AND repository_name = ? AND number = ?", repo.Host, repo.Name, number).
+			Update("commits_linked_at", time.Now()).Error
+	})
+}
+
+// PullRequestsByRepository fetches the stored pull requests of the repository, narrowed to a state by the filter,
+// latest opened first unless sorted otherwise
+func (r *PostgresPullRequestRepository) PullRequestsByRepository(ctx context.Context, repo domain.RepoMetadata, filter domain.PullRequestFilter, query domain.APIPagingData) ([]domain.PullRequest, *domain.PagingInfo, error) {
+	if ctx.Err() == context.Canceled {
+		return nil, nil, message.ErrContextCancelled
+	}
+
+	var dbPullRequests []PullRequest
+	var count int64
+
+	if query.Sort == "" {
+		query.Sort = "number"
+	}
+	queryInfo, offset := repository.GetQueryPaginationData(query)
+
+	db := r.DB.WithContext(ctx).Model(&PullRequest{}).Where("repository_host = ? AND repository_name = ?", repo.Host, repo.Name)
+
+	if filter.State != "" {
+		db = db.Where("state = ?", filter.State)
+	}
+
+	db.Count(&count)
+
+	db = db.Offset(offset).Limit(queryInfo.Limit).
+		Order(fmt.Sprintf("%s %s", queryInfo.Sort, queryInfo.Direction)).
+		Find(&dbPullRequests)
+
+	if db.Error != nil {
+		log.Info().Msgf("fetch pull requests error %v", db.Error.Error())
+
+		return nil, nil, db.Error
+	}
+
+	pagingInfo := repository.PagingInfo(queryInfo, int(count))
+	pagingInfo.Count = len(dbPullRequests)
+
+	pullRequests := make([]domain.PullRequest, 0, len(dbPullRequests))
+	for _, p := range dbPullRequests {
+		pullRequests = append(pullRequests, *p.ToDomain())
+	}
+	return pullRequests, &pagingInfo, nil
+}
+
+// PullRequestsByCommits fetches the pull request which brought in each of the commits, keyed by commit id. A commit
+// of several pull requests, eg merged into a release branch before the default branch, was brought in by the earliest merged
+func (r *PostgresPullRequestRepository) PullRequestsByCommits(ctx context.Context, repo domain.RepoMetadata, commitIDs []string) (map[string]domain.PullRequest, error) {
+	if ctx.Err() == context.Canceled {
+		return nil, message.ErrContextCancelled
+	}
+
+	pullRequests := make(map[string]domain.PullRequest)
+	if len(commitIDs) == 0 {
+		return pullRequests, nil
+	}
+
+	var rows []struct {
+		CommitID    string
+		PullRequest `gorm:"embedded"`
+	}
+	err := r.DB.WithContext(ctx).Model(&PullRequestCommit{}).
+		Select("pull_request_commits.commit_id, pull_requests.*").
+		Joins("JOIN pull_requests ON pull_requests.repository_host = pull_request_commits.repository_host AND "+
+			"pull_requests.repository_name = pull_request_commits.repository_name AND pull_requests.number = pull_request_commits.number").
+		Where("pull_request_commits.repository_host = ? AND pull_request_commits.repository_name = ? AND pull_request_commits.commit_id IN ?",
+			repo.Host, repo.Name, commitIDs).
+		Order("pull_requests.merged_at").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	for _, row := range rows {
+		if _, ok := pullRequests[row.CommitID]; !ok {
+			pullRequests[row.CommitID] = *row.PullRequest.ToDomain()
+		}
+	}
+	return pullRequests, nil
+}
@@ -0,0 +1,91 @@
+package postgres
+
+import (
+	"time"
+
+	"github.com/kenmobility/git-api-service/internal/domain"
+)
+
+// Tag represents the Postgres model for the tags table, the tags of the repositories.
+type Tag struct {
+	ID             uint   `gorm:"primarykey"`
+	RepositoryHost string `gorm:"type:varchar;uniqueIndex:idx_tags_repository_tag"`
+	RepositoryName string `gorm:"type:varchar(100);uniqueIndex:idx_tags_repository_tag"`
+	Name           string `gorm:"type:varchar;uniqueIndex:idx_tags_repository_tag"`
+	TargetSHA      string `gorm:"column:target_sha;type:varchar(100);index"`
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+}
+
+// Release represents the Postgres model for the releases table, the releases of the repositories.
+type Release struct {
+	ID             uint   `gorm:"primarykey"`
+	RepositoryHost string `gorm:"type:varchar;uniqueIndex:idx_releases_repository_tag"`
+	RepositoryName string `gorm:"type:varchar(100);uniqueIndex:idx_releases_repository_tag"`
+	TagName        string `gorm:"type:varchar;uniqueIndex:idx_releases_repository_tag"`
+	TargetSHA      string `gorm:"column:target_sha;type:varchar(100);index"`
+	Title          string `gorm:"type:varchar"`
+	Body           string `gorm:"type:text"`
+	Draft          bool
+	Prerelease     bool
+	PublishedAt    *time.Time `gorm:"index"`
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+}
+
+// ToDomain converts a Postgres Tag object to domain entity Tag.
+func (pt *Tag) ToDomain() *domain.Tag {
+	return &domain.Tag{
+		Name:      pt.Name,
+		TargetSHA: pt.TargetSHA,
+		CreatedAt: pt.CreatedAt,
+		UpdatedAt: pt.UpdatedAt,
+	}
+}
+
+// FromDomainTag returns a Postgres Tag object of the repository from domain entity Tag.
+func FromDomainTag(r *domain.RepoMetadata, t *domain.Tag) *Tag {
+	return &Tag{
+		RepositoryHost: r.Host,
+		RepositoryName: r.Name,
+		Name:           t.Name,
+		TargetSHA:      t.TargetSHA,
+	}
+}
+
+// ToDomain converts a Postgres Release object to domain entity Release.
+func (pr *Release) ToDomain() *domain.Release {
+	release := &domain.Release{
+		TagName:    pr.TagName,
+		TargetSHA:  pr.TargetSHA,
+		Title:      pr.Title,
+		Body:       pr.Body,
+		Draft:      pr.Draft,
+		Prerelease: pr.Prerelease,
+		CreatedAt:  pr.CreatedAt,
+		UpdatedAt:  pr.UpdatedAt,
+	}
+	if pr.PublishedAt != nil {
+		release.PublishedAt = *pr.PublishedAt
+	}
+	return release
+}
+
+// FromDomainRelease returns a Postgres Release object of the repository from domain entity Release.
+func FromDomainRelease(r *domain.RepoMetadata, rl *domain.Release) *Release {
+	release := &Release{
+		RepositoryHost: r.Host,
+		RepositoryName: r.Name,
+		TagName:        rl.TagName,
+		TargetSHA:      rl.TargetSHA,
+		Title:          rl.Title,
+		Body:           rl.Body,
+		Draft:          rl.Draft,
+		Prerelease:     rl.Prerelease,
+	}
+	if !rl.PublishedAt.IsZero() {
+		publishedAt := rl.PublishedAt
+		release.PublishedAt = &publishedAt
+	}
+	return release
+}
@@ -0,0 +1,170 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/kenmobility/git-api-service/internal/domain"
+	"github.com/kenmobility/git-api-service/internal/repository"
+	"github.com/kenmobility/git-api-service/pkg/message"
+	"github.com/rs/zerolog/log"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// syncBatchSize is the number of tags or releases inserted by a statement
+const syncBatchSize = 500
+
+type PostgresReleaseRepository struct {
+	DB *gorm.DB
+}
+
+func NewPostgresReleaseRepository(db *gorm.DB) repository.ReleaseRepository {
+	return &PostgresReleaseRepository{DB: db}
+}
+
+// SyncTags replaces the stored tags of the repository with the tags, a stored tag is only updated when it
+// was moved to another commit
+func (r *PostgresReleaseRepository) SyncTags(ctx context.Context, repo domain.RepoMetadata, tags []domain.Tag) error {
+	if ctx.Err() == context.Canceled {
+		return message.ErrContextCancelled
+	}
+
+	dbTags := make([]Tag, 0, len(tags))
+	names := make([]string, 0, len(tags))
+	for i := range tags {
+		dbTags = append(dbTags, *FromDomainTag(&repo, &tags[i]))
+		names = append(names, tags[i].Name)
+	}
+
+	return r.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		stale := tx.Where("repository_host = ? AND repository_name = ?", repo.Host, repo.Name)
+		if len(names) > 0 {
+			stale = stale.Where("name NOT IN ?", names)
+		}
+		if err := stale.Delete(&Tag{}).Error; err != nil {
+			return err
+		}
+
+		if len(dbTags) == 0 {
+			return nil
+		}
+
+		return tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "repository_host"}, {Name: "repository_name"}, {Name: "name"}},
+			DoUpdates: clause.AssignmentColumns([]string{"target_sha", "updated_at"}),
+			Where: clause.Where{Exprs: []clause.Expression{
+				clause.Expr{SQL: "tags.target_sha IS DISTINCT FROM excluded.target_sha"},
+			}},
+		}).CreateInBatches(&dbTags, syncBatchSize).Error
+	})
+}
+
+// SyncReleases replaces the stored releases of the repository with the releases, a stored release is only
+// updated when it was edited
+func (r *PostgresReleaseRepository) SyncReleases(ctx context.Context, repo domain.RepoMetadata, releases []domain.Release) error {
+	if ctx.Err() == context.Canceled {
+		return message.ErrContextCancelled
+	}
+
+	dbReleases := make([]Release, 0, len(releases))
+	tagNames := make([]string, 0, len(releases))
+	for i := range releases {
+		dbReleases = append(dbReleases, *FromDomainRelease(&repo, &releases[i]))
+		tagNames = append(tagNames, releases[i].TagName)
+	}
+
+	return r.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		stale := tx.Where("repository_host = ? AND repository_name = ?", repo.Host, repo.Name)
+		if len(tagNames) > 0 {
+			stale = stale.Where("tag_name NOT IN ?", tagNames)
+		}
+		if err := stale.Delete(&Release{}).Error; err != nil {
+			return err
+		}
+
+		if len(dbReleases) == 0 {
+			return nil
+		}
+
+		return tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "repository_host"}, {Name: "repository_name"}, {Name: "tag_name"}},
+			DoUpdates: clause.AssignmentColumns([]string{"target_sha", "title", "body", "draft", "prerelease", "published_at", "updated_at"}),
+			Where: clause.Where{Exprs: []clause.Expression{
+				clause.Expr{SQL: "(releases.target_sha, releases.title, releases.body, releases.draft, releases.prerelease, releases.published_at) IS DISTINCT FROM " +
+					"(excluded.target_sha, excluded.title, excluded.body, excluded.draft, excluded.prerelease, excluded.published_at)"},
+			}},
+		}).CreateInBatches(&dbReleases, syncBatchSize).Error
+	})
+}
+
+// TagsByRepository fetches the stored tags of the repository
+func (r *PostgresReleaseRepository) TagsByRepository(ctx context.Context, repo domain.RepoMetadata, query domain.APIPagingData) ([]domain.Tag, *domain.PagingInfo, error) {
+	if ctx.Err() == context.Canceled {
+		return nil, nil, message.ErrContextCancelled
+	}
+
+	var dbTags []Tag
+	var count int64
+
+	queryInfo, offset := repository.GetQueryPaginationData(query)
+
+	db := r.DB.WithContext(ctx).Model(&Tag{}).Where("repository_host = ? AND repository_name = ?", repo.Host, repo.Name)
+	db.Count(&count)
+
+	db = db.Offset(offset).Limit(queryInfo.Limit).
+		Order(fmt.Sprintf("%s %s", queryInfo.Sort, queryInfo.Direction)).
+		Find(&dbTags)
+
+	if db.Error != nil {
+		log.Info().Msgf("fetch tags error %v", db.Error.Error())
+
+		return nil, nil, db.Error
+	}
+
+	pagingInfo := repository.PagingInfo(queryInfo, int(count))
+	pagingInfo.Count = len(dbTags)
+
+	tags := make([]domain.Tag, 0, len(dbTags))
+	for _, t := range dbTags {
+		tags = append(tags, *t.ToDomain())
+	}
+	return tags, &pagingInfo, nil
+}
+
+// ReleasesByRepository fetches the stored releases of the repository, latest published first unless sorted otherwise
+func (r *PostgresReleaseRepository) ReleasesByRepository(ctx context.Context, repo domain.RepoMetadata, query domain.APIPagingData) ([]domain.Release, *domain.PagingInfo, error) {
+	if ctx.Err() == context.Canceled {
+		return nil, nil, message.ErrContextCancelled
+	}
+
+	var dbReleases []Release
+	var count int64
+
+	if query.Sort == "" {
+		query.Sort = "published_at"
+	}
+	queryInfo, offset := repository.GetQueryPaginationData(query)
+
+	db := r.DB.WithContext(ctx).Model(&Release{}).Where("repository_host = ? AND repository_name = ?", repo.Host, repo.Name)
+	db.Count(&count)
+
+	db = db.Offset(offset).Limit(queryInfo.Limit).
+		Order(fmt.Sprintf("%s %s", queryInfo.Sort, queryInfo.Direction)).
+		Find(&dbReleases)
+
+	if db.Error != nil {
+		log.Info().Msgf("fetch releases error %v", db.Error.Error())
+
+		return nil, nil, db.Error
+	}
+
+	pagingInfo := repository.PagingInfo(queryInfo, int(count))
+	pagingInfo.Count = len(dbReleases)
+
+	releases := make([]domain.Release, 0, len(dbReleases))
+	for _, rl := range dbReleases {
+		releases = append(releases, *rl.ToDomain())
+	}
+	return releases, &pagingInfo, nil
+}
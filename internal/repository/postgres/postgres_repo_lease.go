@@ -0,0 +1,13 @@
+package postgres
+
+import "time"
+
+// RepoLease represents the Postgres model for the repo_leases table, the lease of the instance syncing a repository.
+// A lease which was not renewed before it expired can be acquired by another instance.
+type RepoLease struct {
+	RepositoryID string `gorm:"type:varchar;primarykey"`
+	Owner        string `gorm:"type:varchar"`
+	ExpiresAt    time.Time
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+}
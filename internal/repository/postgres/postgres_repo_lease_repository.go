@@ -0,0 +1,69 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"github.com/kenmobility/git-api-service/internal/repository"
+	"github.com/kenmobility/git-api-service/pkg/message"
+	"gorm.io/gorm"
+)
+
+type PostgresRepoLeaseRepository struct {
+	DB *gorm.DB
+}
+
+func NewPostgresRepoLeaseRepository(db *gorm.DB) repository.RepoLeaseRepository {
+	return &PostgresRepoLeaseRepository{DB: db}
+}
+
+// AcquireRepoLease acquires the lease of the repository for the owner when it is free or expired, it reports whether
+// the owner holds the lease. Expiry is compared on the database clock so that instances with skewed clocks agree
+func (r *PostgresRepoLeaseRepository) AcquireRepoLease(ctx context.Context, repositoryID string, owner string, ttl time.Duration) (bool, error) {
+	if ctx.Err() == context.Canceled {
+		return false, message.ErrContextCancelled
+	}
+
+	result := r.DB.WithContext(ctx).Exec(`INSERT INTO repo_leases (repository_id, owner, expires_at, created_at, updated_at)
+		VALUES (?, ?, now() + make_interval(secs => ?), now(), now())
+		ON CONFLICT (repository_id) DO UPDATE SET owner = excluded.owner, expires_at = excluded.expires_at, updated_at = now()
+		WHERE repo_leases.expires_at <= now()`, repositoryID, owner, ttl.Seconds())
+	if result.Error != nil {
+		return false, result.Error
+	}
+
+	return result.RowsAffected == 1, nil
+}
+
+// RenewRepoLease extends the lease held by the owner by ttl, ErrRepoLeaseLost is returned when the owner no longer holds it
+func (r *PostgresRepoLeaseRepository) RenewRepoLease(ctx context.Context, repositoryID string, owner string, ttl time.Duration) error {
+	if ctx.Err() == context.Canceled {
+		return message.ErrContextCancelled
+	}
+
+	result := r.DB.WithContext(ctx).Model(&RepoLease{}).
+		Where("repository_id = ? AND owner = ? AND expires_at > now()", repositoryID, owner).
+		Updates(map[string]any{
+			"expires_at": gorm.Expr("now() + make_interval(secs => ?)", ttl.Seconds()),
+			"updated_at": gorm.Expr("now()"),
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return message.ErrRepoLeaseLost
+	}
+
+	return nil
+}
+
+// ReleaseRepoLease frees the lease of the repository if the owner still holds it
+func (r *PostgresRepoLeaseRepository) ReleaseRepoLease(ctx context.Context, repositoryID string, owner string) error {
+	if ctx.Err() == context.Canceled {
+		return message.ErrContextCancelled
+	}
+
+	return r.DB.WithContext(ctx).
+		Where("repository_id = ? AND owner = ?", repositoryID, owner).
+		Delete(&RepoLease{}).Error
+}
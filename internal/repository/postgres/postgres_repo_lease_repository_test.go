@@ -0,0 +1,134 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/kenmobility/git-api-service/pkg/message"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// execRecorder is a database/sql driver recording the statements executed on it, each affecting rowsAffected rows
+type execRecorder struct {
+	mu           sync.Mutex
+	rowsAffected int64
+	statements   []recordedStatement
+}
+
+type recordedStatement struct {
+	query string
+	args  []any
+}
+
+func (r *execRecorder) Connect(context.Context) (driver.Conn, error) {
+	return &recorderConn{recorder: r}, nil
+}
+func (r *execRecorder) Driver() driver.Driver { return nil }
+
+type recorderConn struct {
+	recorder *execRecorder
+}
+
+func (c *recorderConn) Prepare(string) (driver.Stmt, error) { return nil, errors.New("not supported") }
+func (c *recorderConn) Close() error                        { return nil }
+func (c *recorderConn) Begin() (driver.Tx, error)           { return recorderTx{}, nil }
+
+func (c *recorderConn) ExecContext(_ context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	c.recorder.mu.Lock()
+	defer c.recorder.mu.Unlock()
+
+	statement := recordedStatement{query: query}
+	for _, arg := range args {
+		statement.args = append(statement.args, arg.Value)
+	}
+	c.recorder.statements = append(c.recorder.statements, statement)
+	return driver.RowsAffected(c.recorder.rowsAffected), nil
+}
+
+type recorderTx struct{}
+
+func (recorderTx) Commit() error   { return nil }
+func (recorderTx) Rollback() error { return nil }
+
+func newTestLeaseRepository(t *testing.T, rowsAffected int64) (*PostgresRepoLeaseRepository, *execRecorder) {
+	recorder := &execRecorder{rowsAffected: rowsAffected}
+	sqlDB := sql.OpenDB(recorder)
+	t.Cleanup(func() { sqlDB.Close() })
+
+	db, err := gorm.Open(postgres.New(postgres.Config{Conn: sqlDB}), &gorm.Config{
+		DisableAutomaticPing: true,
+		Logger:               logger.Discard,
+	})
+	require.NoError(t, err)
+
+	return &PostgresRepoLeaseRepository{DB: db}, recorder
+}
+
+func TestAcquireRepoLease(t *testing.T) {
+	ctx := context.Background()
+
+	leases, recorder := newTestLeaseRepository(t, 1)
+	acquired, err := leases.AcquireRepoLease(ctx, "repo-id", "instance/1", time.Minute)
+	require.NoError(t, err)
+	require.True(t, acquired)
+
+	// the lease is only taken over once it expired
+	require.Len(t, recorder.statements, 1)
+	require.Contains(t, recorder.statements[0].query, "ON CONFLICT (repository_id) DO UPDATE")
+	require.Contains(t, recorder.statements[0].query, "WHERE repo_leases.expires_at <= now()")
+	require.Equal(t, []any{"repo-id", "instance/1", float64(60)}, recorder.statements[0].args)
+
+	// no row is written while another owner holds an unexpired lease
+	leases, _ = newTestLeaseRepository(t, 0)
+	acquired, err = leases.AcquireRepoLease(ctx, "repo-id", "instance/2", time.Minute)
+	require.NoError(t, err)
+	require.False(t, acquired)
+}
+
+func TestRenewRepoLease(t *testing.T) {
+	ctx := context.Background()
+
+	leases, recorder := newTestLeaseRepository(t, 1)
+	require.NoError(t, leases.RenewRepoLease(ctx, "repo-id", "instance/1", time.Minute))
+
+	require.Len(t, recorder.statements, 1)
+	require.Contains(t, recorder.statements[0].query, "repository_id = $")
+	require.Contains(t, recorder.statements[0].query, "owner = $")
+	require.Contains(t, recorder.statements[0].args, "repo-id")
+	require.Contains(t, recorder.statements[0].args, "instance/1")
+
+	// the lease expired or was taken over by another owner
+	leases, _ = newTestLeaseRepository(t, 0)
+	require.Equal(t, message.ErrRepoLeaseLost, leases.RenewRepoLease(ctx, "repo-id", "instance/1", time.Minute))
+}
+
+func TestReleaseRepoLease(t *testing.T) {
+	leases, recorder := newTestLeaseRepository(t, 1)
+	require.NoError(t, leases.ReleaseRepoLease(context.Background(), "repo-id", "instance/1"))
+
+	// only the lease still held by the owner is deleted
+	require.Len(t, recorder.statements, 1)
+	require.Contains(t, recorder.statements[0].query, "DELETE FROM \"repo_leases\"")
+	require.Equal(t, []any{"repo-id", "instance/1"}, recorder.statements[0].args)
+}
+
+func TestRepoLeaseCancelledContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	leases, recorder := newTestLeaseRepository(t, 1)
+
+	_, err := leases.AcquireRepoLease(ctx, "repo-id", "instance/1", time.Minute)
+	require.Equal(t, message.ErrContextCancelled, err)
+	require.Equal(t, message.ErrContextCancelled, leases.RenewRepoLease(ctx, "repo-id", "instance/1", time.Minute))
+	require.Equal(t, message.ErrContextCancelled, leases.ReleaseRepoLease(ctx, "repo-id", "instance/1"))
+	require.Empty(t, recorder.statements)
+}
@@ -8,40 +8,73 @@ import (
 
 // Repository represents the Postgres model for the repositories table.
 type Repository struct {
-	ID                uint   `gorm:"primarykey"`
-	PublicID          string `gorm:"type:varchar;uniqueIndex"`
-	Name              string `gorm:"type:varchar;unique"`
-	Description       string `gorm:"type:text"`
-	URL               string `gorm:"type:varchar"`
-	Language          string `gorm:"type:varchar"`
+	ID                uint     `gorm:"primarykey"`
+	PublicID          string   `gorm:"type:varchar;uniqueIndex"`
+	Provider          string   `gorm:"type:varchar"`
+	Host              string   `gorm:"type:varchar;uniqueIndex:idx_repositories_host_name"`
+	Name              string   `gorm:"type:varchar;uniqueIndex:idx_repositories_host_name"`
+	Description       string   `gorm:"type:text"`
+	URL               string   `gorm:"type:varchar"`
+	Language          string   `gorm:"type:varchar"`
+	DefaultBranch     string   `gorm:"type:varchar"`
+	TrackedBranches   []string `gorm:"type:text;serializer:json"`
+	CredentialID      string   `gorm:"type:varchar"`
 	ForksCount        int
 	StarsCount        int
 	OpenIssuesCount   int
 	WatchersCount     int
+	Status            string `gorm:"type:varchar(20);default:active"`
+	StatusChangedAt   time.Time
 	CreatedAt         time.Time
 	UpdatedAt         time.Time
 	LastFetchedCommit string `gorm:"type:varchar"`
 	IsFetching        bool
-	LastFetchedPage   int32 `gorm:"default:1"`
+	LastFetchedPage   int32  `gorm:"default:1"`
+	LastFetchedCursor string `gorm:"type:varchar"`
+
+	ProgressPage              int32
+	ProgressTotalPages        int32
+	ProgressStartPage         int32
+	ProgressStartedAt         time.Time
+	ProgressUpdatedAt         time.Time
+	ProgressFinishedAt        time.Time
+	ProgressEstimatedTimeLeft time.Duration
 }
 
 // ToDomain converts a Postgres Repository object to domain entity RepoMetadata.
 func (pr *Repository) ToDomain() *domain.RepoMetadata {
 	return &domain.RepoMetadata{
 		PublicID:          pr.PublicID,
+		Provider:          pr.Provider,
+		Host:              pr.Host,
 		Name:              pr.Name,
 		Description:       pr.Description,
 		URL:               pr.URL,
 		Language:          pr.Language,
+		DefaultBranch:     pr.DefaultBranch,
+		TrackedBranches:   pr.TrackedBranches,
+		CredentialID:      pr.CredentialID,
 		ForksCount:        pr.ForksCount,
 		StarsCount:        pr.StarsCount,
 		OpenIssuesCount:   pr.OpenIssuesCount,
 		WatchersCount:     pr.WatchersCount,
+		Status:            pr.Status,
+		StatusChangedAt:   pr.StatusChangedAt,
 		CreatedAt:         pr.CreatedAt,
 		UpdatedAt:         pr.UpdatedAt,
 		LastFetchedCommit: pr.LastFetchedCommit,
 		IsFetching:        pr.IsFetching,
 		LastFetchedPage:   pr.LastFetchedPage,
+		LastFetchedCursor: pr.LastFetchedCursor,
+		Progress: domain.IndexingProgress{
+			Page:              pr.ProgressPage,
+			TotalPages:        pr.ProgressTotalPages,
+			StartPage:         pr.ProgressStartPage,
+			StartedAt:         pr.ProgressStartedAt,
+			UpdatedAt:         pr.ProgressUpdatedAt,
+			FinishedAt:        pr.ProgressFinishedAt,
+			EstimatedTimeLeft: pr.ProgressEstimatedTimeLeft,
+		},
 	}
 }
 
@@ -49,18 +82,34 @@ func (pr *Repository) ToDomain() *domain.RepoMetadata {
 func FromDomainRepo(r *domain.RepoMetadata) *Repository {
 	return &Repository{
 		PublicID:          r.PublicID,
+		Provider:          r.Provider,
+		Host:              r.Host,
 		Name:              r.Name,
 		Description:       r.Description,
 		URL:               r.URL,
 		Language:          r.Language,
+		DefaultBranch:     r.DefaultBranch,
+		TrackedBranches:   r.TrackedBranches,
+		CredentialID:      r.CredentialID,
 		ForksCount:        r.ForksCount,
 		StarsCount:        r.StarsCount,
 		OpenIssuesCount:   r.OpenIssuesCount,
 		WatchersCount:     r.WatchersCount,
+		Status:            r.Status,
+		StatusChangedAt:   r.StatusChangedAt,
 		CreatedAt:         r.CreatedAt,
 		UpdatedAt:         r.UpdatedAt,
 		LastFetchedCommit: r.LastFetchedCommit,
 		IsFetching:        r.IsFetching,
 		LastFetchedPage:   r.LastFetchedPage,
+		LastFetchedCursor: r.LastFetchedCursor,
+
+		ProgressPage:              r.Progress.Page,
+		ProgressTotalPages:        r.Progress.TotalPages,
+		ProgressStartPage:         r.Progress.StartPage,
+		ProgressStartedAt:         r.Progress.StartedAt,
+		ProgressUpdatedAt:         r.Progress.UpdatedAt,
+		ProgressFinishedAt:        r.Progress.FinishedAt,
+		ProgressEstimatedTimeLeft: r.Progress.EstimatedTimeLeft,
 	}
 }
@@ -0,0 +1,43 @@
+package postgres
+
+import (
+	"time"
+
+	"github.com/kenmobility/git-api-service/internal/domain"
+)
+
+// RepoSnapshot represents the Postgres model for the repo_snapshots table, the metadata of the repositories captured on each refresh.
+type RepoSnapshot struct {
+	ID              uint   `gorm:"primarykey"`
+	RepositoryHost  string `gorm:"type:varchar;index:idx_repo_snapshots_repository_captured"`
+	RepositoryName  string `gorm:"type:varchar(100);index:idx_repo_snapshots_repository_captured"`
+	StarsCount      int
+	ForksCount      int
+	WatchersCount   int
+	OpenIssuesCount int
+	CapturedAt      time.Time `gorm:"index:idx_repo_snapshots_repository_captured"`
+}
+
+// ToDomain converts a Postgres RepoSnapshot object to domain entity RepoMetadataSnapshot.
+func (ps *RepoSnapshot) ToDomain() *domain.RepoMetadataSnapshot {
+	return &domain.RepoMetadataSnapshot{
+		StarsCount:      ps.StarsCount,
+		ForksCount:      ps.ForksCount,
+		WatchersCount:   ps.WatchersCount,
+		OpenIssuesCount: ps.OpenIssuesCount,
+		CapturedAt:      ps.CapturedAt,
+	}
+}
+
+// FromDomainRepoSnapshot returns a Postgres RepoSnapshot object of the repository from domain entity RepoMetadataSnapshot.
+func FromDomainRepoSnapshot(r *domain.RepoMetadata, s *domain.RepoMetadataSnapshot) *RepoSnapshot {
+	return &RepoSnapshot{
+		RepositoryHost:  r.Host,
+		RepositoryName:  r.Name,
+		StarsCount:      s.StarsCount,
+		ForksCount:      s.ForksCount,
+		WatchersCount:   s.WatchersCount,
+		OpenIssuesCount: s.OpenIssuesCount,
+		CapturedAt:      s.CapturedAt,
+	}
+}
@@ -0,0 +1,55 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"github.com/kenmobility/git-api-service/internal/domain"
+	"github.com/kenmobility/git-api-service/internal/repository"
+	"github.com/kenmobility/git-api-service/pkg/message"
+	"gorm.io/gorm"
+)
+
+type PostgresRepoSnapshotRepository struct {
+	DB *gorm.DB
+}
+
+func NewPostgresRepoSnapshotRepository(db *gorm.DB) repository.RepoSnapshotRepository {
+	return &PostgresRepoSnapshotRepository{DB: db}
+}
+
+// SaveSnapshot stores the metadata of the repository captured on a refresh
+func (r *PostgresRepoSnapshotRepository) SaveSnapshot(ctx context.Context, repo domain.RepoMetadata, snapshot domain.RepoMetadataSnapshot) error {
+	if ctx.Err() == context.Canceled {
+		return message.ErrContextCancelled
+	}
+
+	return r.DB.WithContext(ctx).Create(FromDomainRepoSnapshot(&repo, &snapshot)).Error
+}
+
+// SnapshotsByRepository fetches the snapshots of the repository captured between since and until, oldest first,
+// a zero since or until leaves that end of the range open
+func (r *PostgresRepoSnapshotRepository) SnapshotsByRepository(ctx context.Context, repo domain.RepoMetadata, since time.Time, until time.Time) ([]domain.RepoMetadataSnapshot, error) {
+	if ctx.Err() == context.Canceled {
+		return nil, message.ErrContextCancelled
+	}
+
+	query := r.DB.WithContext(ctx).Where("repository_host = ? AND repository_name = ?", repo.Host, repo.Name)
+	if !since.IsZero() {
+		query = query.Where("captured_at >= ?", since)
+	}
+	if !until.IsZero() {
+		query = query.Where("captured_at <= ?", until)
+	}
+
+	var dbSnapshots []RepoSnapshot
+	if err := query.Order("captured_at").Find(&dbSnapshots).Error; err != nil {
+		return nil, err
+	}
+
+	snapshots := make([]domain.RepoMetadataSnapshot, 0, len(dbSnapshots))
+	for _, s := range dbSnapshots {
+		snapshots = append(snapshots, *s.ToDomain())
+	}
+	return snapshots, nil
+}
@@ -0,0 +1,13 @@
+package postgres
+
+import (
+	"time"
+)
+
+// WebhookDelivery represents the Postgres model for the webhook_deliveries table, the webhook deliveries processed so far.
+type WebhookDelivery struct {
+	ID         uint   `gorm:"primarykey"`
+	DeliveryID string `gorm:"type:varchar(100);uniqueIndex"`
+	Event      string `gorm:"type:varchar(50)"`
+	CreatedAt  time.Time
+}
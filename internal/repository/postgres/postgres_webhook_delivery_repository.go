@@ -0,0 +1,41 @@
+package postgres
+
+import (
+	"context"
+
+	"github.com/kenmobility/git-api-service/internal/repository"
+	"github.com/kenmobility/git-api-service/pkg/message"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type PostgresWebhookDeliveryRepository struct {
+	DB *gorm.DB
+}
+
+func NewPostgresWebhookDeliveryRepository(db *gorm.DB) repository.WebhookDeliveryRepository {
+	return &PostgresWebhookDeliveryRepository{DB: db}
+}
+
+// ClaimDelivery records the delivery and reports whether it was claimed, false when it was recorded before and so already processed
+func (r *PostgresWebhookDeliveryRepository) ClaimDelivery(ctx context.Context, deliveryID string, event string) (bool, error) {
+	if ctx.Err() == context.Canceled {
+		return false, message.ErrContextCancelled
+	}
+
+	tx := r.DB.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&WebhookDelivery{DeliveryID: deliveryID, Event: event})
+	if tx.Error != nil {
+		return false, tx.Error
+	}
+	return tx.RowsAffected == 1, nil
+}
+
+// ReleaseDelivery removes the record of a delivery whose processing failed, so it is processed again when redelivered
+func (r *PostgresWebhookDeliveryRepository) ReleaseDelivery(ctx context.Context, deliveryID string) error {
+	if ctx.Err() == context.Canceled {
+		return message.ErrContextCancelled
+	}
+
+	return r.DB.WithContext(ctx).Where("delivery_id = ?", deliveryID).Delete(&WebhookDelivery{}).Error
+}
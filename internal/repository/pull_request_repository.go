@@ -0,0 +1,17 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/kenmobility/git-api-service/internal/domain"
+)
+
+type PullRequestRepository interface {
+	SavePullRequests(ctx context.Context, repo domain.RepoMetadata, pullRequests []domain.PullRequest) error
+	LatestPullRequestUpdate(ctx context.Context, repo domain.RepoMetadata) (time.Time, error)
+	UnlinkedPullRequests(ctx context.Context, repo domain.RepoMetadata, limit int) ([]domain.PullRequest, error)
+	LinkPullRequestCommits(ctx context.Context, repo domain.RepoMetadata, number int, commitIDs []string) error
+	PullRequestsByRepository(ctx context.Context, repo domain.RepoMetadata, filter domain.PullRequestFilter, query domain.APIPagingData) ([]domain.PullRequest, *domain.PagingInfo, error)
+	PullRequestsByCommits(ctx context.Context, repo domain.RepoMetadata, commitIDs []string) (map[string]domain.PullRequest, error)
+}
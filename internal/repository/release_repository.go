@@ -0,0 +1,14 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/kenmobility/git-api-service/internal/domain"
+)
+
+type ReleaseRepository interface {
+	SyncTags(ctx context.Context, repo domain.RepoMetadata, tags []domain.Tag) error
+	SyncReleases(ctx context.Context, repo domain.RepoMetadata, releases []domain.Release) error
+	TagsByRepository(ctx context.Context, repo domain.RepoMetadata, query domain.APIPagingData) ([]domain.Tag, *domain.PagingInfo, error)
+	ReleasesByRepository(ctx context.Context, repo domain.RepoMetadata, query domain.APIPagingData) ([]domain.Release, *domain.PagingInfo, error)
+}
@@ -0,0 +1,12 @@
+package repository
+
+import (
+	"context"
+	"time"
+)
+
+type RepoLeaseRepository interface {
+	AcquireRepoLease(ctx context.Context, repositoryID string, owner string, ttl time.Duration) (bool, error)
+	RenewRepoLease(ctx context.Context, repositoryID string, owner string, ttl time.Duration) error
+	ReleaseRepoLease(ctx context.Context, repositoryID string, owner string) error
+}
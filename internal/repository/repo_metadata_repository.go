@@ -10,7 +10,9 @@ type RepoMetadataRepository interface {
 	SaveRepoMetadata(ctx context.Context, repository domain.RepoMetadata) (*domain.RepoMetadata, error)
 	UpdateRepoMetadata(ctx context.Context, repo domain.RepoMetadata) (*domain.RepoMetadata, error)
 	RepoMetadataByPublicId(ctx context.Context, publicId string) (*domain.RepoMetadata, error)
-	RepoMetadataByName(ctx context.Context, name string) (*domain.RepoMetadata, error)
+	RepoMetadataByName(ctx context.Context, host string, name string) (*domain.RepoMetadata, error)
 	AllRepoMetadata(ctx context.Context) ([]domain.RepoMetadata, error)
-	UpdateFetchingStateForAllRepos(ctx context.Context, isFetching bool) error
+	UpdateTrackedBranches(ctx context.Context, publicId string, branches []string) (*domain.RepoMetadata, error)
+	RefreshRepoMetadata(ctx context.Context, repo domain.RepoMetadata) error
+	RenameRepository(ctx context.Context, repo domain.RepoMetadata, name string) (*domain.RepoMetadata, error)
 }
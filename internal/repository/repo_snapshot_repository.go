@@ -0,0 +1,13 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/kenmobility/git-api-service/internal/domain"
+)
+
+type RepoSnapshotRepository interface {
+	SaveSnapshot(ctx context.Context, repo domain.RepoMetadata, snapshot domain.RepoMetadataSnapshot) error
+	SnapshotsByRepository(ctx context.Context, repo domain.RepoMetadata, since time.Time, until time.Time) ([]domain.RepoMetadataSnapshot, error)
+}
@@ -3,4 +3,13 @@ package repository
 type Repository interface {
 	CommitRepository
 	RepoMetadataRepository
+	HTTPValidatorRepository
+	BranchRepository
+	ReleaseRepository
+	PullRequestRepository
+	WebhookDeliveryRepository
+	RepoSnapshotRepository
+	CredentialRepository
+	JobRepository
+	RepoLeaseRepository
 }
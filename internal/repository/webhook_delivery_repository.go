@@ -0,0 +1,10 @@
+package repository
+
+import (
+	"context"
+)
+
+type WebhookDeliveryRepository interface {
+	ClaimDelivery(ctx context.Context, deliveryID string, event string) (bool, error)
+	ReleaseDelivery(ctx context.Context, deliveryID string) error
+}
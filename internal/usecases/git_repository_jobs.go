@@ -0,0 +1,251 @@
+package usecases
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/kenmobility/git-api-service/internal/domain"
+	"github.com/kenmobility/git-api-service/pkg/message"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	// jobRetryBaseDelay is the delay before the first retry of a failed job, doubled on each further attempt
+	jobRetryBaseDelay = 30 * time.Second
+	// jobRetryMaxDelay caps the delay between the retries of a failed job
+	jobRetryMaxDelay = 30 * time.Minute
+	// repoLeaseRetryDelay is the delay before a job postponed because its repository is synced elsewhere runs again
+	repoLeaseRetryDelay = time.Minute
+)
+
+// enqueueJob queues a job of the kind on the repository to run right away
+func (uc *gitRepoUsecase) enqueueJob(ctx context.Context, kind string, repositoryID string) error {
+	return uc.jobRepository.EnqueueJob(ctx, domain.Job{
+		PublicID:     uuid.New().String(),
+		Kind:         kind,
+		RepositoryID: repositoryID,
+		Status:       domain.JobStatusPending,
+		MaxAttempts:  uc.config.JobMaxAttempts,
+		RunAt:        time.Now(),
+	})
+}
+
+// ProcessJobs runs the configured number of workers claiming and running the queued jobs until the context is cancelled,
+// it returns once the workers released their jobs
+func (uc *gitRepoUsecase) ProcessJobs(ctx context.Context) {
+	log.Info().Msgf("Starting %d job workers", uc.config.JobWorkers)
+
+	var wg sync.WaitGroup
+	for worker := 1; worker <= uc.config.JobWorkers; worker++ {
+		wg.Add(1)
+		go func(worker int) {
+			defer wg.Done()
+			uc.runJobWorker(ctx, worker)
+		}(worker)
+	}
+	wg.Wait()
+}
+
+// runJobWorker runs the due jobs back to back, then polls the queue every poll interval while it is empty
+func (uc *gitRepoUsecase) runJobWorker(ctx context.Context, worker int) {
+	ticker := time.NewTicker(uc.config.JobPollInterval)
+	defer ticker.Stop()
+
+	for {
+		for uc.processNextJob(ctx, worker) {
+		}
+
+		select {
+		case <-ctx.Done():
+			log.Warn().Msgf("job worker %d stopped", worker)
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// processNextJob claims the next due job and runs it, it reports whether a job was claimed
+func (uc *gitRepoUsecase) processNextJob(ctx context.Context, worker int) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+
+	job, err := uc.jobRepository.ClaimJob(ctx, uc.config.JobVisibilityTimeout)
+	if err != nil {
+		if err != message.ErrNoRecordFound && err != message.ErrContextCancelled {
+			log.Err(err).Msgf("job worker %d failed to claim a job", worker)
+		}
+		return false
+	}
+
+	// the workers which claimed the job on its last attempts stopped without releasing it, eg by crashing
+	if job.Attempts > job.MaxAttempts {
+		log.Error().Msgf("%s job %s of repo %s was not released by its workers, moving it to the dead jobs", job.Kind, job.PublicID, job.RepositoryID)
+		if err := uc.jobRepository.DeadLetterJob(ctx, *job, "job lock expired on all its attempts"); err != nil {
+			log.Err(err).Msgf("error dead-lettering job %s", job.PublicID)
+		}
+		return true
+	}
+
+	// a repository is synced by a single job at a time across all the instances, the job of a repository whose lease
+	// is held elsewhere is postponed without counting the attempt
+	leaseOwner := uc.newLeaseOwner()
+	acquired, err := uc.repoLeaseRepository.AcquireRepoLease(ctx, job.RepositoryID, leaseOwner, uc.config.RepoLeaseTTL)
+	if err != nil || !acquired {
+		if err != nil && err != message.ErrContextCancelled {
+			log.Err(err).Msgf("error acquiring the lease of repo %s", job.RepositoryID)
+		} else if err == nil {
+			log.Debug().Msgf("repo %s is synced by another job, postponing %s job %s", job.RepositoryID, job.Kind, job.PublicID)
+		}
+		uc.releaseJob(*job, time.Now().Add(repoLeaseRetryDelay))
+		return ctx.Err() == nil
+	}
+
+	log.Info().Msgf("job worker %d running %s job %s of repo %s, attempt %d", worker, job.Kind, job.PublicID, job.RepositoryID, job.Attempts)
+
+	runCtx, stopRun := context.WithCancel(ctx)
+	var leaseLost atomic.Bool
+	go uc.heartbeat(runCtx, *job, leaseOwner, func() {
+		leaseLost.Store(true)
+		stopRun()
+	})
+
+	err = uc.runJob(runCtx, *job)
+	stopRun()
+
+	if err := uc.repoLeaseRepository.ReleaseRepoLease(context.Background(), job.RepositoryID, leaseOwner); err != nil {
+		log.Err(err).Msgf("error releasing the lease of repo %s", job.RepositoryID)
+	}
+
+	if ctx.Err() != nil {
+		// the service is shutting down, the job is released to resume on the next start
+		uc.releaseJob(*job, time.Now())
+		return false
+	}
+	if leaseLost.Load() {
+		// the run was stopped as the repository may already be synced by another instance
+		uc.releaseJob(*job, time.Now().Add(repoLeaseRetryDelay))
+		return true
+	}
+
+	uc.finishJob(ctx, *job, err)
+	return true
+}
+
+// newLeaseOwner returns the owner of a new repository lease, unique to the instance and the acquisition
+func (uc *gitRepoUsecase) newLeaseOwner() string {
+	return uc.instanceID + "/" + uuid.New().String()
+}
+
+// heartbeat extends the lock of the running job and the lease of its repository every third of the shortest of the
+// visibility timeout and the lease ttl until the context is cancelled, leaseLost is called once the lease is lost
+func (uc *gitRepoUsecase) heartbeat(ctx context.Context, job domain.Job, leaseOwner string, leaseLost func()) {
+	ticker := time.NewTicker(min(uc.config.JobVisibilityTimeout, uc.config.RepoLeaseTTL) / 3)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			err := uc.jobRepository.ExtendJobLock(ctx, job, time.Now().Add(uc.config.JobVisibilityTimeout))
+			if err != nil && err != message.ErrContextCancelled {
+				log.Err(err).Msgf("error extending the lock of job %s", job.PublicID)
+			}
+
+			err = uc.repoLeaseRepository.RenewRepoLease(ctx, job.RepositoryID, leaseOwner, uc.config.RepoLeaseTTL)
+			if err == message.ErrRepoLeaseLost {
+				log.Error().Msgf("lease of repo %s was lost, stopping %s job %s", job.RepositoryID, job.Kind, job.PublicID)
+				leaseLost()
+				return
+			}
+			if err != nil && err != message.ErrContextCancelled {
+				log.Err(err).Msgf("error renewing the lease of repo %s", job.RepositoryID)
+			}
+		}
+	}
+}
+
+// releaseJob queues the interrupted job again to run at runAt, it is released even when the service is shutting down
+func (uc *gitRepoUsecase) releaseJob(job domain.Job, runAt time.Time) {
+	if err := uc.jobRepository.ReleaseJob(context.Background(), job, runAt); err != nil {
+		log.Err(err).Msgf("error releasing %s job %s of repo %s", job.Kind, job.PublicID, job.RepositoryID)
+	}
+}
+
+// runJob runs the job on its repository, jobs of repositories which were removed have nothing left to do
+func (uc *gitRepoUsecase) runJob(ctx context.Context, job domain.Job) error {
+	repo, err := uc.repoMetadataRepository.RepoMetadataByPublicId(ctx, job.RepositoryID)
+	if err == message.ErrNoRecordFound {
+		log.Warn().Msgf("repo %s of %s job %s was not found, skipping it", job.RepositoryID, job.Kind, job.PublicID)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	switch job.Kind {
+	case domain.JobKindIndex:
+		if !repo.IsFetching {
+			return nil
+		}
+		return uc.indexRepository(ctx, *repo)
+	case domain.JobKindMonitor:
+		return uc.monitorRepository(ctx, repo)
+	case domain.JobKindIndexBranches:
+		gitClient, err := uc.gitClients.Client(*repo)
+		if err != nil {
+			return err
+		}
+		return uc.indexBranches(ctx, gitClient, *repo)
+	case domain.JobKindReconcileBranches:
+		if repo.IsFetching {
+			return nil
+		}
+		gitClient, err := uc.gitClients.Client(*repo)
+		if err != nil {
+			return err
+		}
+		if err := uc.reconcileDefaultBranch(ctx, gitClient, repo); err != nil {
+			return err
+		}
+		return uc.indexBranches(ctx, gitClient, *repo)
+	default:
+		return message.ErrUnknownJobKind
+	}
+}
+
+// finishJob releases the job after its run: a failed job is retried with an exponential backoff until it used all
+// its attempts and is dead-lettered, a monitoring job is rescheduled every fetch interval and other jobs are done
+func (uc *gitRepoUsecase) finishJob(ctx context.Context, job domain.Job, runErr error) {
+	var err error
+	switch {
+	case runErr != nil && job.Attempts >= job.MaxAttempts:
+		log.Err(runErr).Msgf("%s job %s of repo %s failed on all its %d attempts, moving it to the dead jobs", job.Kind, job.PublicID, job.RepositoryID, job.Attempts)
+		err = uc.jobRepository.DeadLetterJob(ctx, job, runErr.Error())
+	case runErr != nil:
+		delay := jobRetryDelay(job.Attempts)
+		log.Err(runErr).Msgf("%s job %s of repo %s failed, retrying in %s", job.Kind, job.PublicID, job.RepositoryID, delay)
+		err = uc.jobRepository.RetryJob(ctx, job, time.Now().Add(delay), runErr.Error())
+	case job.Kind == domain.JobKindMonitor:
+		err = uc.jobRepository.RescheduleJob(ctx, job, time.Now().Add(uc.config.FetchInterval))
+	default:
+		err = uc.jobRepository.CompleteJob(ctx, job)
+	}
+
+	if err != nil {
+		log.Err(err).Msgf("error releasing %s job %s of repo %s", job.Kind, job.PublicID, job.RepositoryID)
+	}
+}
+
+// jobRetryDelay is the delay before the retry of a job which failed on the attempt
+func jobRetryDelay(attempt int) time.Duration {
+	delay := jobRetryBaseDelay
+	for i := 1; i < attempt && delay < jobRetryMaxDelay; i++ {
+		delay *= 2
+	}
+	return min(delay, jobRetryMaxDelay)
+}
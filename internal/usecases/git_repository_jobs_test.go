@@ -0,0 +1,166 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/kenmobility/git-api-service/internal/domain"
+	"github.com/kenmobility/git-api-service/pkg/message"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+// newJobTest returns a usecase test whose jobs are locked and whose repositories are leased for the ttl
+func newJobTest(t *testing.T, ttl time.Duration) *usecaseTest {
+	u := newUsecaseTest(t)
+	u.uc.config.JobVisibilityTimeout = ttl
+	u.uc.config.RepoLeaseTTL = ttl
+	u.uc.config.FetchInterval = time.Hour
+	return u
+}
+
+func testJob(kind string, attempts int) *domain.Job {
+	return &domain.Job{
+		PublicID:     "job-id",
+		Kind:         kind,
+		RepositoryID: "repo-id",
+		Status:       domain.JobStatusRunning,
+		Attempts:     attempts,
+		MaxAttempts:  3,
+	}
+}
+
+func TestJobRetryDelay(t *testing.T) {
+	testCases := []struct {
+		attempt int
+		delay   time.Duration
+	}{
+		{attempt: 1, delay: 30 * time.Second},
+		{attempt: 2, delay: time.Minute},
+		{attempt: 3, delay: 2 * time.Minute},
+		{attempt: 6, delay: 16 * time.Minute},
+		// the delay doubled from the 7th attempt is capped
+		{attempt: 7, delay: 30 * time.Minute},
+		{attempt: 40, delay: 30 * time.Minute},
+	}
+
+	for _, tc := range testCases {
+		require.Equal(t, tc.delay, jobRetryDelay(tc.attempt), "attempt %d", tc.attempt)
+	}
+}
+
+func TestFinishJobRetriesFailedJob(t *testing.T) {
+	u := newJobTest(t, time.Minute)
+	job := testJob(domain.JobKindIndex, 2)
+
+	u.store.EXPECT().RetryJob(gomock.Any(), *job, gomock.Any(), "rate limit exceeded").DoAndReturn(
+		func(_ context.Context, _ domain.Job, runAt time.Time, _ string) error {
+			require.WithinDuration(t, time.Now().Add(time.Minute), runAt, time.Second)
+			return nil
+		})
+
+	u.uc.finishJob(context.Background(), *job, errors.New("rate limit exceeded"))
+}
+
+func TestFinishJobDeadLettersJobOnLastAttempt(t *testing.T) {
+	u := newJobTest(t, time.Minute)
+	job := testJob(domain.JobKindMonitor, 3)
+
+	u.store.EXPECT().DeadLetterJob(gomock.Any(), *job, "rate limit exceeded").Return(nil)
+
+	u.uc.finishJob(context.Background(), *job, errors.New("rate limit exceeded"))
+}
+
+func TestFinishJobReschedulesMonitoring(t *testing.T) {
+	u := newJobTest(t, time.Minute)
+	job := testJob(domain.JobKindMonitor, 1)
+
+	u.store.EXPECT().RescheduleJob(gomock.Any(), *job, gomock.Any()).DoAndReturn(
+		func(_ context.Context, _ domain.Job, runAt time.Time) error {
+			require.WithinDuration(t, time.Now().Add(time.Hour), runAt, time.Second)
+			return nil
+		})
+
+	u.uc.finishJob(context.Background(), *job, nil)
+}
+
+func TestFinishJobCompletesJob(t *testing.T) {
+	u := newJobTest(t, time.Minute)
+	job := testJob(domain.JobKindIndexBranches, 1)
+
+	u.store.EXPECT().CompleteJob(gomock.Any(), *job).Return(nil)
+
+	u.uc.finishJob(context.Background(), *job, nil)
+}
+
+func TestProcessNextJobRunsJobUnderLease(t *testing.T) {
+	u := newJobTest(t, time.Minute)
+	job := testJob(domain.JobKindIndex, 1)
+
+	gomock.InOrder(
+		u.store.EXPECT().ClaimJob(gomock.Any(), time.Minute).Return(job, nil),
+		u.store.EXPECT().AcquireRepoLease(gomock.Any(), "repo-id", gomock.Any(), time.Minute).Return(true, nil),
+		// the repository was indexed already, its indexing job has nothing left to do
+		u.store.EXPECT().RepoMetadataByPublicId(gomock.Any(), "repo-id").Return(&u.repo, nil),
+		u.store.EXPECT().ReleaseRepoLease(gomock.Any(), "repo-id", gomock.Any()).Return(nil),
+		u.store.EXPECT().CompleteJob(gomock.Any(), *job).Return(nil),
+	)
+
+	require.True(t, u.uc.processNextJob(context.Background(), 1))
+}
+
+func TestProcessNextJobDeadLettersUnreleasedJob(t *testing.T) {
+	u := newJobTest(t, time.Minute)
+
+	// the job was claimed again after its workers stopped on all its attempts, it is not run again
+	job := testJob(domain.JobKindMonitor, 4)
+	u.store.EXPECT().ClaimJob(gomock.Any(), time.Minute).Return(job, nil)
+	u.store.EXPECT().DeadLetterJob(gomock.Any(), *job, "job lock expired on all its attempts").Return(nil)
+
+	require.True(t, u.uc.processNextJob(context.Background(), 1))
+}
+
+func TestProcessNextJobPostponesJobOfLeasedRepository(t *testing.T) {
+	u := newJobTest(t, time.Minute)
+	job := testJob(domain.JobKindMonitor, 1)
+
+	// the repository is synced by another instance, the job is released without running
+	u.store.EXPECT().ClaimJob(gomock.Any(), time.Minute).Return(job, nil)
+	u.store.EXPECT().AcquireRepoLease(gomock.Any(), "repo-id", gomock.Any(), time.Minute).Return(false, nil)
+	u.store.EXPECT().ReleaseJob(gomock.Any(), *job, gomock.Any()).DoAndReturn(
+		func(_ context.Context, _ domain.Job, runAt time.Time) error {
+			require.WithinDuration(t, time.Now().Add(repoLeaseRetryDelay), runAt, time.Second)
+			return nil
+		})
+
+	require.True(t, u.uc.processNextJob(context.Background(), 1))
+}
+
+func TestProcessNextJobReleasesJobOnLostLease(t *testing.T) {
+	u := newJobTest(t, 30*time.Millisecond)
+	job := testJob(domain.JobKindMonitor, 1)
+
+	u.store.EXPECT().ClaimJob(gomock.Any(), gomock.Any()).Return(job, nil)
+	u.store.EXPECT().AcquireRepoLease(gomock.Any(), "repo-id", gomock.Any(), gomock.Any()).Return(true, nil)
+
+	// the run lasts until it is stopped, the lease is lost on the first heartbeat
+	u.store.EXPECT().RepoMetadataByPublicId(gomock.Any(), "repo-id").DoAndReturn(
+		func(ctx context.Context, _ string) (*domain.RepoMetadata, error) {
+			<-ctx.Done()
+			return nil, message.ErrContextCancelled
+		})
+	u.store.EXPECT().ExtendJobLock(gomock.Any(), *job, gomock.Any()).Return(nil)
+	u.store.EXPECT().RenewRepoLease(gomock.Any(), "repo-id", gomock.Any(), gomock.Any()).Return(message.ErrRepoLeaseLost)
+
+	// the stopped run is neither retried nor counted as failed, the job is postponed as another instance may sync the repository
+	u.store.EXPECT().ReleaseRepoLease(gomock.Any(), "repo-id", gomock.Any()).Return(nil)
+	u.store.EXPECT().ReleaseJob(gomock.Any(), *job, gomock.Any()).DoAndReturn(
+		func(_ context.Context, _ domain.Job, runAt time.Time) error {
+			require.WithinDuration(t, time.Now().Add(repoLeaseRetryDelay), runAt, time.Second)
+			return nil
+		})
+
+	require.True(t, u.uc.processNextJob(context.Background(), 1))
+}
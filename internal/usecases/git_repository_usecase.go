@@ -2,6 +2,10 @@ package usecases
 
 import (
 	"context"
+	"errors"
+	"os"
+	"path"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -15,26 +19,68 @@ import (
 )
 
 type GitRepositoryUsecase interface {
-	StartIndexing(ctx context.Context, repositoryName string) (*domain.RepoMetadata, error)
+	StartIndexing(ctx context.Context, repositoryName string, trackedBranches []string, credentialName string, token string) (*domain.RepoMetadata, error)
 	GetById(ctx context.Context, repoId string) (*domain.RepoMetadata, error)
+	UpdateTrackedBranches(ctx context.Context, repoId string, trackedBranches []string) (*domain.RepoMetadata, error)
+	GetBranches(ctx context.Context, repoId string) ([]domain.Branch, error)
 	GetAll(ctx context.Context) ([]domain.RepoMetadata, error)
 	ResumeFetching(ctx context.Context) error
+	ProcessJobs(ctx context.Context)
+	RateLimits(ctx context.Context) []domain.RateLimitStatus
+	EnrichCommits(ctx context.Context)
+	RefreshMetadata(ctx context.Context)
+	GetMetadataHistory(ctx context.Context, repoId string, since time.Time, until time.Time) (*domain.RepoMetadata, []domain.RepoMetadataSnapshot, error)
+	GetHistoryRewrites(ctx context.Context, repoId string) (*domain.RepoMetadata, []domain.HistoryRewrite, error)
 }
 
+const (
+	// enrichmentBatchSize is the number of commits of a repository enriched on each round
+	enrichmentBatchSize = 100
+	// pullRequestLinkBatchSize is the number of merged pull requests of a repository whose commits are linked on each round
+	pullRequestLinkBatchSize = 100
+	// enrichmentBudgetReserve is the share of a host's rate limit left to indexing, enrichment pauses below it
+	enrichmentBudgetReserve = 0.2
+)
+
 type gitRepoUsecase struct {
 	repoMetadataRepository repository.RepoMetadataRepository
 	commitRepository       repository.CommitRepository
-	gitClient              git.GitManagerClient
+	branchRepository       repository.BranchRepository
+	releaseRepository      repository.ReleaseRepository
+	pullRequestRepository  repository.PullRequestRepository
+	repoSnapshotRepository repository.RepoSnapshotRepository
+	credentialRepository   repository.CredentialRepository
+	jobRepository          repository.JobRepository
+	repoLeaseRepository    repository.RepoLeaseRepository
+	gitClients             *git.Registry
 	config                 config.Config
+	// instanceID identifies the instance of the service in the leases of the repositories it syncs
+	instanceID string
 }
 
 func NewGitRepositoryUsecase(repoMetadataRepo repository.RepoMetadataRepository, commitRepo repository.CommitRepository,
-	gitClient git.GitManagerClient, config config.Config) GitRepositoryUsecase {
+	branchRepo repository.BranchRepository, releaseRepo repository.ReleaseRepository,
+	pullRequestRepo repository.PullRequestRepository, repoSnapshotRepo repository.RepoSnapshotRepository,
+	credentialRepo repository.CredentialRepository, jobRepo repository.JobRepository,
+	repoLeaseRepo repository.RepoLeaseRepository, gitClients *git.Registry, config config.Config) GitRepositoryUsecase {
+	instanceID, err := os.Hostname()
+	if err != nil {
+		instanceID = uuid.New().String()
+	}
+
 	return &gitRepoUsecase{
 		repoMetadataRepository: repoMetadataRepo,
 		commitRepository:       commitRepo,
-		gitClient:              gitClient,
+		branchRepository:       branchRepo,
+		releaseRepository:      releaseRepo,
+		pullRequestRepository:  pullRequestRepo,
+		repoSnapshotRepository: repoSnapshotRepo,
+		credentialRepository:   credentialRepo,
+		jobRepository:          jobRepo,
+		repoLeaseRepository:    repoLeaseRepo,
+		gitClients:             gitClients,
 		config:                 config,
+		instanceID:             instanceID,
 	}
 }
 
@@ -51,14 +97,96 @@ func (uc *gitRepoUsecase) GetAll(ctx context.Context) ([]domain.RepoMetadata, er
 	return uc.repoMetadataRepository.AllRepoMetadata(ctx)
 }
 
-func (uc *gitRepoUsecase) StartIndexing(ctx context.Context, repositoryName string) (*domain.RepoMetadata, error) {
+// UpdateTrackedBranches replaces the branches indexed besides the default branch, the branches newly
+// matched are indexed in the background
+func (uc *gitRepoUsecase) UpdateTrackedBranches(ctx context.Context, repoId string, trackedBranches []string) (*domain.RepoMetadata, error) {
+	if !validBranchPatterns(trackedBranches) {
+		return nil, message.ErrInvalidBranchPattern
+	}
+
+	repo, err := uc.repoMetadataRepository.UpdateTrackedBranches(ctx, repoId, trackedBranches)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := uc.gitClients.Client(*repo); err != nil {
+		return nil, err
+	}
+
+	if err := uc.enqueueJob(ctx, domain.JobKindIndexBranches, repo.PublicID); err != nil {
+		log.Err(err).Msgf("error queueing the branch indexing of repo %s", repo.Name)
+	}
+
+	return repo, nil
+}
+
+// GetBranches returns the indexing state of the tracked branches of the repository
+func (uc *gitRepoUsecase) GetBranches(ctx context.Context, repoId string) ([]domain.Branch, error) {
+	repo, err := uc.repoMetadataRepository.RepoMetadataByPublicId(ctx, repoId)
+	if err != nil {
+		return nil, err
+	}
+
+	return uc.branchRepository.BranchesByRepository(ctx, *repo)
+}
+
+// GetMetadataHistory returns the repository and its metadata snapshots captured between since and until
+func (uc *gitRepoUsecase) GetMetadataHistory(ctx context.Context, repoId string, since time.Time, until time.Time) (*domain.RepoMetadata, []domain.RepoMetadataSnapshot, error) {
+	if !since.IsZero() && !until.IsZero() && since.After(until) {
+		return nil, nil, message.ErrInvalidTimeRange
+	}
+
+	repo, err := uc.repoMetadataRepository.RepoMetadataByPublicId(ctx, repoId)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	snapshots, err := uc.repoSnapshotRepository.SnapshotsByRepository(ctx, *repo, since, until)
+	if err != nil {
+		return nil, nil, err
+	}
+	return repo, snapshots, nil
+}
+
+// GetHistoryRewrites returns the repository and the rewrites found in the history of its branches
+func (uc *gitRepoUsecase) GetHistoryRewrites(ctx context.Context, repoId string) (*domain.RepoMetadata, []domain.HistoryRewrite, error) {
+	repo, err := uc.repoMetadataRepository.RepoMetadataByPublicId(ctx, repoId)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	rewrites, err := uc.branchRepository.HistoryRewritesByRepository(ctx, *repo)
+	if err != nil {
+		return nil, nil, err
+	}
+	return repo, rewrites, nil
+}
+
+// RateLimits returns the rate limit budget left on the git hosts reporting one
+func (uc *gitRepoUsecase) RateLimits(ctx context.Context) []domain.RateLimitStatus {
+	return uc.gitClients.RateLimits()
+}
+
+// StartIndexing adds the repository and indexes it in the background, its requests are authenticated with the stored
+// credential named credentialName or with token, which is stored encrypted along with the repository
+func (uc *gitRepoUsecase) StartIndexing(ctx context.Context, repositoryName string, trackedBranches []string, credentialName string, token string) (*domain.RepoMetadata, error) {
 	//validate repository name to ensure it has owner and repo name
 	if !helpers.IsRepositoryNameValid(repositoryName) {
 		return nil, message.ErrInvalidRepositoryName
 	}
 
+	if !validBranchPatterns(trackedBranches) {
+		return nil, message.ErrInvalidBranchPattern
+	}
+
+	// resolve the repository host and the git client serving it
+	ref, gitClient, err := uc.gitClients.Resolve(repositoryName)
+	if err != nil {
+		return nil, err
+	}
+
 	// ensure repo does not exist on the db
-	repo, err := uc.repoMetadataRepository.RepoMetadataByName(ctx, repositoryName)
+	repo, err := uc.repoMetadataRepository.RepoMetadataByName(ctx, ref.Host, ref.Name)
 	if err != nil && err != message.ErrNoRecordFound {
 		return nil, err
 	}
@@ -67,41 +195,113 @@ func (uc *gitRepoUsecase) StartIndexing(ctx context.Context, repositoryName stri
 		return nil, message.ErrRepoAlreadyAdded
 	}
 
-	repoMetadata, err := uc.gitClient.FetchRepoMetadata(ctx, repositoryName)
+	credential, gitClient, err := uc.resolveCredential(ctx, ref.Host, gitClient, credentialName, token)
 	if err != nil {
 		return nil, err
 	}
 
+	repoMetadata, err := gitClient.FetchRepoMetadata(ctx, ref.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	if credential != nil && credential.PublicID == "" {
+		// the token given inline is only stored once it granted access to the repository
+		credential.PublicID = uuid.New().String()
+		credential.CreatedAt = time.Now()
+
+		credential, err = uc.credentialRepository.SaveCredential(ctx, *credential)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if credential != nil {
+		repoMetadata.CredentialID = credential.PublicID
+	}
+
 	// update other repository metadata
+	repoMetadata.Provider = ref.Provider
+	repoMetadata.Host = ref.Host
 	repoMetadata.PublicID = uuid.New().String()
 	repoMetadata.CreatedAt = time.Now()
 	repoMetadata.UpdatedAt = time.Now()
 	repoMetadata.IsFetching = true
+	repoMetadata.TrackedBranches = trackedBranches
 
 	sRepoMetadata, err := uc.repoMetadataRepository.SaveRepoMetadata(ctx, *repoMetadata)
 	if err != nil {
 		return nil, err
 	}
 
-	// Start fetching commits for the new added repository in a Goroutine
-	go uc.startRepoIndexing(ctx, *sRepoMetadata)
+	if err := uc.repoSnapshotRepository.SaveSnapshot(ctx, *sRepoMetadata, metadataSnapshot(*sRepoMetadata, sRepoMetadata.CreatedAt)); err != nil {
+		log.Err(err).Msgf("error saving metadata snapshot of repo %s", sRepoMetadata.Name)
+	}
+
+	// queue the indexing of the new added repository and its monitoring, which starts once indexing finished
+	for _, kind := range []string{domain.JobKindIndex, domain.JobKindMonitor} {
+		if err := uc.enqueueJob(ctx, kind, sRepoMetadata.PublicID); err != nil {
+			log.Err(err).Msgf("error queueing %s job of repo %s", kind, sRepoMetadata.Name)
+		}
+	}
 
 	return sRepoMetadata, nil
 }
 
-func (uc *gitRepoUsecase) startRepoIndexing(ctx context.Context, repo domain.RepoMetadata) {
+// resolveCredential returns the credential a repository is added with, if any, along with the client of the host
+// authenticating with it. A token given inline is returned as an unnamed credential which is not stored yet
+func (uc *gitRepoUsecase) resolveCredential(ctx context.Context, host string, gitClient git.GitManagerClient, credentialName string, token string) (*domain.Credential, git.GitManagerClient, error) {
+	var credential *domain.Credential
+
+	switch {
+	case credentialName != "" && token != "":
+		return nil, nil, message.ErrAmbiguousCredential
+	case credentialName != "":
+		c, err := uc.credentialRepository.CredentialByName(ctx, credentialName)
+		if err != nil {
+			return nil, nil, err
+		}
+		credential = c
+	case token != "":
+		if len(uc.config.CredentialsEncryptionKey) == 0 {
+			return nil, nil, message.ErrCredentialsNotConfigured
+		}
+		credential = &domain.Credential{Token: token}
+	default:
+		return nil, gitClient, nil
+	}
+
+	credentialClient, err := uc.gitClients.ClientWithToken(host, credential.Token)
+	if err != nil {
+		return nil, nil, err
+	}
+	return credential, credentialClient, nil
+}
+
+// indexRepository indexes the history of the default branch of the repository from the last fetched page, then its
+// tracked branches, tags, releases and pull requests. It stops on the first failure, which is retried from the last page
+func (uc *gitRepoUsecase) indexRepository(ctx context.Context, repo domain.RepoMetadata) error {
+	gitClient, err := uc.gitClients.Client(repo)
+	if err != nil {
+		return err
+	}
+
 	page := repo.LastFetchedPage
 	lastFetchedCommit := ""
+	var validator *domain.HTTPValidator
+	repo.Progress = domain.IndexingProgress{Page: page, StartPage: page, StartedAt: time.Now()}
 	log.Info().Msgf("fetching commits for repo: %s, starting from page-%d", repo.Name, page)
 	for {
-		commits, morePages, err := uc.gitClient.FetchCommits(ctx, repo, uc.config.DefaultStartDate, uc.config.DefaultEndDate, "", int(page), uc.config.GitCommitFetchPerPage)
+		commitPage, err := uc.fetchCommits(ctx, gitClient, &repo, uc.config.DefaultStartDate, uc.config.DefaultEndDate, "", page)
 		if err != nil {
 			log.Err(err).Msgf("Failed to fetch commits for repository %s: %v", repo.Name, err)
-			continue
+			return err
+		}
+		if commitPage.Validator != nil {
+			validator = commitPage.Validator
 		}
 
 		// loop through commits and persist each
-		for _, commit := range commits {
+		for _, commit := range commitPage.Commits {
 			_, err := uc.commitRepository.SaveCommit(ctx, commit)
 			if err != nil {
 				log.Err(err).Msgf("error saving commit-id:%s for repo %s", commit.CommitID, repo.Name)
@@ -109,29 +309,40 @@ func (uc *gitRepoUsecase) startRepoIndexing(ctx context.Context, repo domain.Rep
 			}
 			lastFetchedCommit = commit.CommitID
 		}
+		uc.addToDefaultBranch(ctx, repo, commitPage.Commits)
 
 		// Update the repository's last fetched commit in the database
 		repo.LastFetchedCommit = lastFetchedCommit
 		repo.LastFetchedPage = page
+		recordProgress(&repo.Progress, page, commitPage, time.Now())
 		_, err = uc.repoMetadataRepository.UpdateRepoMetadata(ctx, repo)
 		if err != nil {
 			log.Debug().Msgf("Error updating repository %s: %v", repo.Name, err)
-			continue
+			return err
 		}
 
-		if !morePages {
+		if !commitPage.MorePages {
 			// update isFetching to false as flag for start of monitoring
 			repo.IsFetching = false
 			_, err = uc.repoMetadataRepository.UpdateRepoMetadata(ctx, repo)
 			if err != nil {
 				log.Err(err).Msgf("Error updating isFetching column of repository %s: %v", repo.Name, err)
+				return err
 			}
-			break
+			saveValidator(ctx, gitClient, validator)
+
+			// the history is indexed, a failed sync of the rest is retried by the monitoring of the repository
+			uc.indexBranches(ctx, gitClient, repo)
+			uc.syncTagsAndReleases(ctx, gitClient, repo)
+			uc.syncPullRequests(ctx, gitClient, repo)
+			return nil
 		}
 		page++
 	}
 }
 
+// ResumeFetching queues the monitoring of the stored repositories, and the indexing of the ones whose indexing did not
+// finish, the jobs a repository already has queued are kept
 func (uc *gitRepoUsecase) ResumeFetching(ctx context.Context) error {
 	log.Info().Msg("Resume fetching started ")
 	repos, err := uc.repoMetadataRepository.AllRepoMetadata(ctx)
@@ -141,63 +352,111 @@ func (uc *gitRepoUsecase) ResumeFetching(ctx context.Context) error {
 	}
 	log.Info().Msgf("Saved repos %v", repos)
 	for _, repo := range repos {
-		go uc.startPeriodicFetching(ctx, repo)
+		if repo.IsFetching {
+			if err := uc.enqueueJob(ctx, domain.JobKindIndex, repo.PublicID); err != nil {
+				log.Err(err).Msgf("error queueing the indexing of repo %s", repo.Name)
+			}
+		}
+		if err := uc.enqueueJob(ctx, domain.JobKindMonitor, repo.PublicID); err != nil {
+			log.Err(err).Msgf("error queueing the monitoring of repo %s", repo.Name)
+		}
 	}
 	return nil
 }
 
-func (uc *gitRepoUsecase) startPeriodicFetching(ctx context.Context, repo domain.RepoMetadata) error {
-	ticker := time.NewTicker(uc.config.FetchInterval)
-	defer ticker.Stop()
+// monitorRepository runs a monitoring round of the repository, which is checked on its git host before its new commits,
+// tracked branches, tags, releases and pull requests are fetched. Repositories still being indexed are skipped, a round
+// stops at the first stage failing and its error is returned so the job is retried
+func (uc *gitRepoUsecase) monitorRepository(ctx context.Context, r *domain.RepoMetadata) error {
+	if r.IsFetching {
+		return nil
+	}
 
-	for {
-		select {
-		case <-ctx.Done():
-			log.Warn().Msgf("Git repository [%s] commits monitoring service stopped", repo.Name)
-			return ctx.Err()
-		case <-ticker.C:
-			r, err := uc.repoMetadataRepository.RepoMetadataByPublicId(ctx, repo.PublicID)
-			if err != nil {
-				log.Debug().Msgf("error getting repo metadata for monitoring: %v", err)
-				return err
-			}
-			if !r.IsFetching {
-				log.Info().Msgf("Commits periodic fetching started for repo %v", repo.Name)
-				uc.fetchAndReconcileCommits(ctx, *r)
-			}
-		}
+	gitClient, err := uc.gitClients.Client(*r)
+	if err != nil {
+		return err
 	}
+
+	upstream, err := uc.syncUpstream(ctx, gitClient, *r)
+	if err != nil {
+		log.Err(err).Msgf("error checking repo %s on its git host: %v", r.Name, err)
+		return err
+	}
+	r = &upstream
+
+	// repositories gone from their host are only checked again, so monitoring resumes once restored
+	if r.IsGone() {
+		log.Warn().Msgf("repo %s is %s on its git host, skipping its monitoring", r.Name, r.Status)
+		return nil
+	}
+
+	log.Info().Msgf("Commits periodic fetching started for repo %v", r.Name)
+	if err := uc.reconcileDefaultBranch(ctx, gitClient, r); err != nil {
+		return err
+	}
+	if err := uc.fetchAndReconcileCommits(ctx, *r); err != nil {
+		return err
+	}
+	if err := uc.indexBranches(ctx, gitClient, *r); err != nil {
+		return err
+	}
+	if err := uc.syncTagsAndReleases(ctx, gitClient, *r); err != nil {
+		return err
+	}
+	return uc.syncPullRequests(ctx, gitClient, *r)
 }
 
-func (uc *gitRepoUsecase) fetchAndReconcileCommits(ctx context.Context, repo domain.RepoMetadata) {
+func (uc *gitRepoUsecase) fetchAndReconcileCommits(ctx context.Context, repo domain.RepoMetadata) error {
 	log.Info().Msgf("Resume fetching and reconciling commits for repo: %s", repo.Name)
+	gitClient, err := uc.gitClients.Client(repo)
+	if err != nil {
+		log.Err(err).Msgf("no git client for repository %s on host %s", repo.Name, repo.Host)
+		return err
+	}
+
 	page := repo.LastFetchedPage
 
 	lastFetchedCommit := repo.LastFetchedCommit
 
+	// cursor clients page the history from the head of the default branch
+	_, pagesFromHead := gitClient.(git.CursorCommitFetcher)
+	var validator *domain.HTTPValidator
+
 	until := uc.config.DefaultEndDate
 
 	for {
 		select {
 		case <-ctx.Done():
 			log.Warn().Msgf("Git repository [%s] fetchAndReconcileCommits service stopped", repo.Name)
-			return
+			return message.ErrContextCancelled
 		default:
-			commits, morePages, err := uc.gitClient.FetchCommits(ctx, repo, uc.config.DefaultStartDate, until, lastFetchedCommit, int(page), uc.config.GitCommitFetchPerPage)
+			commitPage, err := uc.fetchCommits(ctx, gitClient, &repo, uc.config.DefaultStartDate, until, lastFetchedCommit, page)
 			if err != nil {
 				log.Error().Msgf("Error fetching commits for repo %s: %v", repo.Name, err)
-				return
+				return err
+			}
+			if commitPage.Validator != nil {
+				validator = commitPage.Validator
 			}
 
-			if len(commits) == 0 {
+			if len(commitPage.Commits) == 0 {
+				if page == 1 && lastFetchedCommit == "" && repo.LastFetchedCursor == "" {
+					log.Info().Msgf("No commits to reconcile for repo %s", repo.Name)
+					return nil
+				}
 				log.Warn().Msgf("No new commits for repo %s, resetting page to 1", repo.Name)
-				page = 1               //reset the page
-				lastFetchedCommit = "" //don't use sha endpoint
+				page = 1                    //reset the page
+				lastFetchedCommit = ""      //don't use sha endpoint
+				repo.LastFetchedCursor = "" //restart cursor paging from the branch head
 				continue
 			}
 
-			for _, commit := range commits {
-				_, err = uc.commitRepository.GetByCommitID(ctx, commit.CommitID)
+			stored := 0
+			for _, commit := range commitPage.Commits {
+				_, err = uc.commitRepository.GetByCommitID(ctx, repo, commit.CommitID)
+				if err == nil {
+					stored++
+				}
 				if err != nil && err != message.ErrNoRecordFound && err != message.ErrContextCancelled {
 					log.Err(err).Msgf("error getting commit by commit-id:%s", commit.CommitID)
 				}
@@ -210,18 +469,32 @@ func (uc *gitRepoUsecase) fetchAndReconcileCommits(ctx context.Context, repo dom
 					lastFetchedCommit = commit.CommitID
 				}
 			}
+			uc.addToDefaultBranch(ctx, repo, commitPage.Commits)
+
+			// the rest of the history was stored before, the next round pages again from the head
+			upToDate := pagesFromHead && stored == len(commitPage.Commits)
+			if upToDate {
+				repo.LastFetchedCursor = ""
+			}
 
 			repo.LastFetchedCommit = lastFetchedCommit
 			repo.LastFetchedPage = page
 			_, err = uc.repoMetadataRepository.UpdateRepoMetadata(ctx, repo)
-			if err != nil && err != message.ErrContextCancelled {
+			if err != nil {
 				log.Debug().Msgf("Error updating repository %s: %v", repo.Name, err)
-				return
+				return err
+			}
+
+			if upToDate {
+				log.Info().Msgf("reached commits already stored for repo: %s", repo.Name)
+				saveValidator(ctx, gitClient, validator)
+				return nil
 			}
 
-			if !morePages {
+			if !commitPage.MorePages {
 				log.Info().Msgf("no more page to fech for repo: %s", repo.Name)
-				break
+				saveValidator(ctx, gitClient, validator)
+				return nil
 			}
 
 			page++
@@ -230,3 +503,694 @@ func (uc *gitRepoUsecase) fetchAndReconcileCommits(ctx context.Context, repo dom
 		}
 	}
 }
+
+// reconcileDefaultBranch indexes the default branch of the repository from its head when it moved since the last round,
+// once its history was rewritten the commit paging of the repository restarts from the new head
+func (uc *gitRepoUsecase) reconcileDefaultBranch(ctx context.Context, gitClient git.GitManagerClient, repo *domain.RepoMetadata) error {
+	branchClient, ok := gitClient.(git.BranchCommitFetcher)
+	if !ok || repo.DefaultBranch == "" {
+		return nil
+	}
+
+	headPage, err := branchClient.FetchBranchCommits(ctx, *repo, repo.DefaultBranch, uc.config.DefaultStartDate, time.Now(), "", 1)
+	if err != nil {
+		log.Err(err).Msgf("error fetching head of branch %s of repo %s", repo.DefaultBranch, repo.Name)
+		return err
+	}
+	if len(headPage.Commits) == 0 {
+		return nil
+	}
+	branch := domain.Branch{Name: repo.DefaultBranch, HeadSHA: headPage.Commits[0].CommitID}
+
+	indexedBranches, err := uc.branchRepository.BranchesByRepository(ctx, *repo)
+	if err != nil {
+		log.Err(err).Msgf("error getting indexed branches of repo %s", repo.Name)
+		return err
+	}
+
+	previousHead := ""
+	for _, b := range indexedBranches {
+		if b.Name == branch.Name {
+			previousHead = b.HeadSHA
+		}
+	}
+
+	switch previousHead {
+	case branch.HeadSHA:
+	case "":
+		// the history of the default branch is indexed by the main indexing loop, its head is only recorded the first time
+		branch.IndexedAt = time.Now()
+		if err := uc.branchRepository.SaveBranch(ctx, *repo, branch); err != nil {
+			log.Err(err).Msgf("error saving branch %s of repo %s", branch.Name, repo.Name)
+			return err
+		}
+	default:
+		rewritten, err := uc.indexBranch(ctx, branchClient, *repo, branch, previousHead)
+		if err != nil {
+			log.Err(err).Msgf("error reconciling branch %s of repo %s", branch.Name, repo.Name)
+			return err
+		}
+
+		if rewritten {
+			repo.LastFetchedPage = 1
+			repo.LastFetchedCommit = ""
+			repo.LastFetchedCursor = ""
+			if _, err := uc.repoMetadataRepository.UpdateRepoMetadata(ctx, *repo); err != nil {
+				log.Err(err).Msgf("error resetting commit paging of repo %s", repo.Name)
+				return err
+			}
+		}
+	}
+
+	// the head is recorded, an unchanged head is answered with a 304 from now on
+	saveValidator(ctx, gitClient, headPage.Validator)
+	return nil
+}
+
+// addToDefaultBranch records that the default branch contains the commits, which are listed from its history
+func (uc *gitRepoUsecase) addToDefaultBranch(ctx context.Context, repo domain.RepoMetadata, commits []domain.Commit) {
+	if repo.DefaultBranch == "" || len(commits) == 0 {
+		return
+	}
+
+	if err := uc.branchRepository.AddCommitsToBranch(ctx, repo, repo.DefaultBranch, commitIDs(commits)); err != nil {
+		log.Err(err).Msgf("error recording commits of branch %s of repo %s", repo.DefaultBranch, repo.Name)
+	}
+}
+
+// indexBranches indexes the branches of the repository matching its tracked branches whose head moved
+// since they were last indexed, the default branch is indexed by the main indexing loop. The other branches are
+// indexed when one fails, the first error is returned
+func (uc *gitRepoUsecase) indexBranches(ctx context.Context, gitClient git.GitManagerClient, repo domain.RepoMetadata) error {
+	if len(repo.TrackedBranches) == 0 {
+		return nil
+	}
+
+	branchClient, ok := gitClient.(git.BranchCommitFetcher)
+	if !ok {
+		log.Warn().Msgf("branch tracking is not supported on host %s, only the default branch of repo %s is indexed", repo.Host, repo.Name)
+		return nil
+	}
+
+	hostBranches, err := branchClient.FetchBranches(ctx, repo)
+	if err != nil {
+		log.Err(err).Msgf("error fetching branches of repo %s", repo.Name)
+		return err
+	}
+
+	indexedBranches, err := uc.branchRepository.BranchesByRepository(ctx, repo)
+	if err != nil {
+		log.Err(err).Msgf("error getting indexed branches of repo %s", repo.Name)
+		return err
+	}
+
+	indexedHeads := make(map[string]string, len(indexedBranches))
+	for _, b := range indexedBranches {
+		indexedHeads[b.Name] = b.HeadSHA
+	}
+
+	var failed error
+	for _, branch := range hostBranches {
+		if branch.Name == repo.DefaultBranch || !matchesBranchPatterns(branch.Name, repo.TrackedBranches) {
+			continue
+		}
+
+		if indexedHeads[branch.Name] == branch.HeadSHA {
+			continue
+		}
+
+		if _, err := uc.indexBranch(ctx, branchClient, repo, branch, indexedHeads[branch.Name]); err != nil {
+			log.Err(err).Msgf("error indexing branch %s of repo %s", branch.Name, repo.Name)
+			if errors.Is(err, message.ErrRateLimitExceeded) || err == message.ErrContextCancelled {
+				return err
+			}
+			if failed == nil {
+				failed = err
+			}
+		}
+	}
+	return failed
+}
+
+// syncTagsAndReleases replaces the stored tags and releases of the repository with the ones listed by its host,
+// the commit of a release the host does not serve is resolved from the tag of the release
+func (uc *gitRepoUsecase) syncTagsAndReleases(ctx context.Context, gitClient git.GitManagerClient, repo domain.RepoMetadata) error {
+	tags, err := gitClient.FetchTags(ctx, repo)
+	if err != nil {
+		log.Err(err).Msgf("error fetching tags of repo %s", repo.Name)
+		return err
+	}
+
+	if err := uc.releaseRepository.SyncTags(ctx, repo, tags); err != nil {
+		log.Err(err).Msgf("error saving tags of repo %s", repo.Name)
+		return err
+	}
+
+	releases, err := gitClient.FetchReleases(ctx, repo)
+	if err != nil {
+		log.Err(err).Msgf("error fetching releases of repo %s", repo.Name)
+		return err
+	}
+
+	tagTargets := make(map[string]string, len(tags))
+	for _, t := range tags {
+		tagTargets[t.Name] = t.TargetSHA
+	}
+	for i := range releases {
+		if releases[i].TargetSHA == "" {
+			releases[i].TargetSHA = tagTargets[releases[i].TagName]
+		}
+	}
+
+	if err := uc.releaseRepository.SyncReleases(ctx, repo, releases); err != nil {
+		log.Err(err).Msgf("error saving releases of repo %s", repo.Name)
+		return err
+	}
+	return nil
+}
+
+// syncPullRequests stores the pull requests of the repository updated since the last sync, then links the commits
+// of the merged pull requests not linked yet, a batch per round, while the client has budget left above the share reserved to indexing.
+// The other pull requests are linked when one fails, the first error is returned
+func (uc *gitRepoUsecase) syncPullRequests(ctx context.Context, gitClient git.GitManagerClient, repo domain.RepoMetadata) error {
+	pullRequestClient, ok := gitClient.(git.PullRequestFetcher)
+	if !ok {
+		log.Debug().Msgf("pull requests are not supported on host %s, skipping repo %s", repo.Host, repo.Name)
+		return nil
+	}
+
+	since, err := uc.pullRequestRepository.LatestPullRequestUpdate(ctx, repo)
+	if err != nil {
+		log.Err(err).Msgf("error getting last pull request update of repo %s", repo.Name)
+		return err
+	}
+
+	pullRequests, err := pullRequestClient.FetchPullRequests(ctx, repo, since)
+	if err != nil {
+		log.Err(err).Msgf("error fetching pull requests of repo %s", repo.Name)
+		return err
+	}
+
+	if err := uc.pullRequestRepository.SavePullRequests(ctx, repo, pullRequests); err != nil {
+		log.Err(err).Msgf("error saving pull requests of repo %s", repo.Name)
+		return err
+	}
+
+	unlinked, err := uc.pullRequestRepository.UnlinkedPullRequests(ctx, repo, pullRequestLinkBatchSize)
+	if err != nil {
+		log.Err(err).Msgf("error getting unlinked pull requests of repo %s", repo.Name)
+		return err
+	}
+
+	var failed error
+	for _, pr := range unlinked {
+		if !hasEnrichmentBudget(gitClient) {
+			log.Info().Msgf("rate limit budget of host %s is reserved to indexing, pausing pull request linking", repo.Host)
+			return failed
+		}
+
+		ids, err := pullRequestClient.FetchPullRequestCommits(ctx, repo, pr.Number)
+		if err != nil {
+			log.Err(err).Msgf("error fetching commits of pull request #%d of repo %s", pr.Number, repo.Name)
+			if errors.Is(err, message.ErrRateLimitExceeded) || err == message.ErrContextCancelled {
+				return err
+			}
+			if failed == nil {
+				failed = err
+			}
+			continue
+		}
+
+		// a squashed pull request is brought in by its merge commit alone
+		if pr.MergeCommitSHA != "" {
+			ids = append(ids, pr.MergeCommitSHA)
+		}
+
+		if err := uc.pullRequestRepository.LinkPullRequestCommits(ctx, repo, pr.Number, ids); err != nil {
+			log.Err(err).Msgf("error linking commits of pull request #%d of repo %s", pr.Number, repo.Name)
+			if failed == nil {
+				failed = err
+			}
+		}
+	}
+	return failed
+}
+
+// indexBranch pages through the history of the branch from its head, saving the commits not indexed yet and
+// recording the branch contains them, until it reaches a page whose commits were all recorded in the branch before.
+// Paging goes past such pages until it reaches the previous head of the branch, when the whole history was paged
+// through without reaching it the history was rewritten and the commits it dropped are removed from the branch
+func (uc *gitRepoUsecase) indexBranch(ctx context.Context, branchClient git.BranchCommitFetcher, repo domain.RepoMetadata, branch domain.Branch, previousHead string) (bool, error) {
+	log.Info().Msgf("indexing branch %s of repo %s at %s", branch.Name, repo.Name, branch.HeadSHA)
+
+	cursor := ""
+	reachedPreviousHead := previousHead == ""
+	reachable := make(map[string]struct{})
+	var validator *domain.HTTPValidator
+	for {
+		commitPage, err := branchClient.FetchBranchCommits(ctx, repo, branch.Name, uc.config.DefaultStartDate, time.Now(), cursor, uc.config.GitCommitFetchPerPage)
+		if err != nil {
+			return false, err
+		}
+
+		// a first page not modified since it was last fetched is served empty, its validators are only saved once
+		// the branch was walked so the history it lists was reconciled then
+		if cursor == "" && len(commitPage.Commits) == 0 {
+			reachedPreviousHead = true
+			break
+		}
+		if cursor == "" {
+			validator = commitPage.Validator
+		}
+
+		ids := commitIDs(commitPage.Commits)
+		for _, id := range ids {
+			reachable[id] = struct{}{}
+			if id == previousHead {
+				reachedPreviousHead = true
+			}
+		}
+
+		recorded, err := uc.branchRepository.CountCommitsInBranch(ctx, repo, branch.Name, ids)
+		if err != nil {
+			return false, err
+		}
+
+		for _, commit := range commitPage.Commits {
+			_, err := uc.commitRepository.GetByCommitID(ctx, repo, commit.CommitID)
+			if err != message.ErrNoRecordFound {
+				continue
+			}
+			if _, err := uc.commitRepository.SaveCommit(ctx, commit); err != nil {
+				log.Err(err).Msgf("error saving commit-id:%s for repo %s", commit.CommitID, repo.Name)
+			}
+		}
+
+		if err := uc.branchRepository.AddCommitsToBranch(ctx, repo, branch.Name, ids); err != nil {
+			return false, err
+		}
+
+		if !commitPage.MorePages || (reachedPreviousHead && len(ids) > 0 && recorded == int64(len(ids))) {
+			break
+		}
+		cursor = commitPage.Cursor
+	}
+
+	rewritten := false
+	if !reachedPreviousHead {
+		var err error
+		rewritten, err = uc.removeUnreachableCommits(ctx, repo, branch, previousHead, reachable)
+		if err != nil {
+			return false, err
+		}
+	}
+
+	branch.IndexedAt = time.Now()
+	if err := uc.branchRepository.SaveBranch(ctx, repo, branch); err != nil {
+		return false, err
+	}
+
+	saveValidator(ctx, branchClient, validator)
+	return rewritten, nil
+}
+
+// removeUnreachableCommits removes the commits recorded in the branch which are not reachable from its head, marking
+// the ones left in no branch orphaned, and records the rewrite of the history of the branch. Nothing is removed when
+// the previous head was not recorded in the branch, eg because it is older than the indexed history
+func (uc *gitRepoUsecase) removeUnreachableCommits(ctx context.Context, repo domain.RepoMetadata, branch domain.Branch, previousHead string, reachable map[string]struct{}) (bool, error) {
+	recordedIDs, err := uc.branchRepository.CommitIDsInBranch(ctx, repo, branch.Name)
+	if err != nil {
+		return false, err
+	}
+
+	var dropped []string
+	droppedPreviousHead := false
+	for _, id := range recordedIDs {
+		if _, ok := reachable[id]; ok {
+			continue
+		}
+		dropped = append(dropped, id)
+		if id == previousHead {
+			droppedPreviousHead = true
+		}
+	}
+
+	if !droppedPreviousHead {
+		return false, nil
+	}
+
+	detectedAt := time.Now()
+	orphaned, err := uc.branchRepository.RemoveCommitsFromBranch(ctx, repo, branch.Name, dropped, detectedAt)
+	if err != nil {
+		return false, err
+	}
+
+	log.Warn().Msgf("history of branch %s of repo %s was rewritten from %s to %s, %d commits were dropped and %d orphaned",
+		branch.Name, repo.Name, previousHead, branch.HeadSHA, len(dropped), orphaned)
+
+	err = uc.branchRepository.SaveHistoryRewrite(ctx, repo, domain.HistoryRewrite{
+		Branch:          branch.Name,
+		PreviousHeadSHA: previousHead,
+		HeadSHA:         branch.HeadSHA,
+		OrphanedCommits: orphaned,
+		DetectedAt:      detectedAt,
+	})
+	return true, err
+}
+
+// validBranchPatterns reports whether the tracked branches are valid glob patterns
+func validBranchPatterns(patterns []string) bool {
+	for _, pattern := range patterns {
+		if strings.TrimSpace(pattern) == "" {
+			return false
+		}
+		if _, err := path.Match(pattern, ""); err != nil {
+			return false
+		}
+	}
+	return true
+}
+
+// matchesBranchPatterns reports whether the branch is one of the tracked branches or matches one of their patterns
+func matchesBranchPatterns(branch string, patterns []string) bool {
+	for _, pattern := range patterns {
+		if matched, _ := path.Match(pattern, branch); matched {
+			return true
+		}
+	}
+	return false
+}
+
+func commitIDs(commits []domain.Commit) []string {
+	ids := make([]string, 0, len(commits))
+	for _, commit := range commits {
+		ids = append(ids, commit.CommitID)
+	}
+	return ids
+}
+
+// EnrichCommits fetches the change statistics and files of the indexed commits on every enrichment interval until
+// ctx is done. It runs apart from indexing and only spends the rate limit budget above enrichmentBudgetReserve
+func (uc *gitRepoUsecase) EnrichCommits(ctx context.Context) {
+	ticker := time.NewTicker(uc.config.EnrichmentInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			log.Warn().Msg("commits enrichment service stopped")
+			return
+		case <-ticker.C:
+			repos, err := uc.repoMetadataRepository.AllRepoMetadata(ctx)
+			if err != nil {
+				log.Err(err).Msgf("error fetching repositories for enrichment: %v", err)
+				continue
+			}
+
+			for _, repo := range repos {
+				uc.enrichRepoCommits(ctx, repo)
+			}
+		}
+	}
+}
+
+func (uc *gitRepoUsecase) enrichRepoCommits(ctx context.Context, repo domain.RepoMetadata) {
+	if repo.IsGone() {
+		// the commits of a repository gone from its host can not be fetched anymore
+		return
+	}
+
+	gitClient, err := uc.gitClients.Client(repo)
+	if err != nil {
+		log.Err(err).Msgf("no git client for repository %s on host %s", repo.Name, repo.Host)
+		return
+	}
+
+	detailFetcher, ok := gitClient.(git.CommitDetailFetcher)
+	if !ok {
+		return
+	}
+
+	commits, err := uc.commitRepository.UnenrichedCommits(ctx, repo, enrichmentBatchSize)
+	if err != nil {
+		log.Err(err).Msgf("error getting commits to enrich of repo %s", repo.Name)
+		return
+	}
+
+	for _, commit := range commits {
+		if !hasEnrichmentBudget(gitClient) {
+			log.Info().Msgf("rate limit budget reserved for indexing, pausing enrichment of repo %s", repo.Name)
+			return
+		}
+
+		detail, err := detailFetcher.FetchCommit(ctx, repo, commit.CommitID)
+		if err == message.ErrCommitNotFetched {
+			// the commit is gone from the host, eg after a force push, it is not retried
+			detail = &domain.Commit{CommitID: commit.CommitID, EnrichedAt: time.Now()}
+		} else if err != nil {
+			log.Err(err).Msgf("error enriching commit-id:%s of repo %s", commit.CommitID, repo.Name)
+			return
+		}
+		// the changes are saved to the commit of this repository, the same commit may be stored for its mirrors too
+		detail.RepositoryName, detail.RepositoryHost = repo.Name, repo.Host
+
+		if err := uc.commitRepository.SaveCommitChanges(ctx, *detail); err != nil {
+			log.Err(err).Msgf("error saving changes of commit-id:%s for repo %s", commit.CommitID, repo.Name)
+			return
+		}
+	}
+}
+
+// RefreshMetadata fetches the metadata of the repositories again every MetadataRefreshInterval,
+// keeping a snapshot of their stars, forks, watchers and open issues on each refresh
+func (uc *gitRepoUsecase) RefreshMetadata(ctx context.Context) {
+	ticker := time.NewTicker(uc.config.MetadataRefreshInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			log.Warn().Msg("repository metadata refresh service stopped")
+			return
+		case <-ticker.C:
+			repos, err := uc.repoMetadataRepository.AllRepoMetadata(ctx)
+			if err != nil {
+				log.Err(err).Msgf("error fetching repositories for metadata refresh: %v", err)
+				continue
+			}
+
+			for _, repo := range repos {
+				uc.refreshRepoMetadata(ctx, repo)
+			}
+		}
+	}
+}
+
+func (uc *gitRepoUsecase) refreshRepoMetadata(ctx context.Context, repo domain.RepoMetadata) {
+	gitClient, err := uc.gitClients.Client(repo)
+	if err != nil {
+		log.Err(err).Msgf("no git client for repository %s on host %s", repo.Name, repo.Host)
+		return
+	}
+
+	if !hasEnrichmentBudget(gitClient) {
+		log.Info().Msgf("rate limit budget reserved for indexing, skipping metadata refresh of repo %s", repo.Name)
+		return
+	}
+
+	// the refresh may rename the repository, so it takes the lease of the repository like its jobs do and is skipped
+	// while one of them syncs it, the repository is loaded again under the lease as a job may have renamed it
+	leaseOwner := uc.newLeaseOwner()
+	acquired, err := uc.repoLeaseRepository.AcquireRepoLease(ctx, repo.PublicID, leaseOwner, uc.config.RepoLeaseTTL)
+	if err != nil {
+		log.Err(err).Msgf("error acquiring the lease of repo %s", repo.Name)
+		return
+	}
+	if !acquired {
+		log.Debug().Msgf("repo %s is synced by a job, skipping its metadata refresh", repo.Name)
+		return
+	}
+	defer func() {
+		if err := uc.repoLeaseRepository.ReleaseRepoLease(context.Background(), repo.PublicID, leaseOwner); err != nil {
+			log.Err(err).Msgf("error releasing the lease of repo %s", repo.Name)
+		}
+	}()
+
+	current, err := uc.repoMetadataRepository.RepoMetadataByPublicId(ctx, repo.PublicID)
+	if err == message.ErrNoRecordFound {
+		return
+	}
+	if err != nil {
+		log.Err(err).Msgf("error loading repo %s for metadata refresh", repo.Name)
+		return
+	}
+
+	repo, err = uc.syncUpstream(ctx, gitClient, *current)
+	if err != nil {
+		log.Err(err).Msgf("error refreshing metadata of repo %s", repo.Name)
+		return
+	}
+
+	if repo.IsGone() {
+		// the metadata of a repository gone from its host is not known anymore
+		return
+	}
+
+	if err := uc.repoSnapshotRepository.SaveSnapshot(ctx, repo, metadataSnapshot(repo, time.Now())); err != nil {
+		log.Err(err).Msgf("error saving metadata snapshot of repo %s", repo.Name)
+	}
+}
+
+// syncUpstream fetches the metadata of the repository from its git host and stores it, following the renames
+// and transfers of the repository and recording whether it was archived, deleted or made inaccessible
+func (uc *gitRepoUsecase) syncUpstream(ctx context.Context, gitClient git.GitManagerClient, repo domain.RepoMetadata) (domain.RepoMetadata, error) {
+	fetched, err := gitClient.FetchRepoMetadata(ctx, repo.Name)
+	switch {
+	case errors.Is(err, message.ErrRepositoryNotFound):
+		setRepoStatus(&repo, domain.RepoStatusDeleted)
+	case errors.Is(err, message.ErrRepositoryInaccessible):
+		setRepoStatus(&repo, domain.RepoStatusInaccessible)
+	case err != nil:
+		return repo, err
+	default:
+		if fetched.Name != "" && fetched.Name != repo.Name {
+			uc.renameRepository(ctx, &repo, fetched.Name)
+		}
+
+		repo.Description = fetched.Description
+		repo.URL = fetched.URL
+		repo.Language = fetched.Language
+		repo.DefaultBranch = fetched.DefaultBranch
+		repo.ForksCount = fetched.ForksCount
+		repo.StarsCount = fetched.StarsCount
+		repo.OpenIssuesCount = fetched.OpenIssuesCount
+		repo.WatchersCount = fetched.WatchersCount
+		setRepoStatus(&repo, fetched.Status)
+	}
+
+	if err := uc.repoMetadataRepository.RefreshRepoMetadata(ctx, repo); err != nil {
+		return repo, err
+	}
+	return repo, nil
+}
+
+// renameRepository moves the repository and what was indexed of it to the name it was renamed or transferred to,
+// repositories being indexed are renamed on a later check as their indexing keeps writing under the old name
+func (uc *gitRepoUsecase) renameRepository(ctx context.Context, repo *domain.RepoMetadata, name string) {
+	if repo.IsFetching {
+		log.Info().Msgf("repo %s was renamed to %s, it is renamed once its indexing finished", repo.Name, name)
+		return
+	}
+
+	renamed, err := uc.repoMetadataRepository.RenameRepository(ctx, *repo, name)
+	if err != nil {
+		log.Err(err).Msgf("error renaming repo %s to %s", repo.Name, name)
+		return
+	}
+
+	log.Info().Msgf("repo %s was renamed or transferred to %s", repo.Name, renamed.Name)
+	repo.Name = renamed.Name
+}
+
+// setRepoStatus sets the status of the repository on its git host, recording when it changed
+func setRepoStatus(repo *domain.RepoMetadata, status string) {
+	if status == "" {
+		status = domain.RepoStatusActive
+	}
+	if repo.Status == status {
+		return
+	}
+
+	log.Info().Msgf("repo %s is now %s on its git host", repo.Name, status)
+	repo.Status = status
+	repo.StatusChangedAt = time.Now()
+}
+
+// metadataSnapshot returns the snapshot of the metadata of the repository captured at capturedAt
+func metadataSnapshot(repo domain.RepoMetadata, capturedAt time.Time) domain.RepoMetadataSnapshot {
+	return domain.RepoMetadataSnapshot{
+		StarsCount:      repo.StarsCount,
+		ForksCount:      repo.ForksCount,
+		WatchersCount:   repo.WatchersCount,
+		OpenIssuesCount: repo.OpenIssuesCount,
+		CapturedAt:      capturedAt,
+	}
+}
+
+// hasEnrichmentBudget reports whether the client has budget left above the share reserved to indexing,
+// clients not reporting a rate limit always have
+func hasEnrichmentBudget(gitClient git.GitManagerClient) bool {
+	reporter, ok := gitClient.(git.RateLimitReporter)
+	if !ok {
+		return true
+	}
+
+	status := reporter.RateLimitStatus()
+	if status.Limit == 0 {
+		// the budget is unknown until the host served a request
+		return true
+	}
+	return float64(status.Remaining) > float64(status.Limit)*enrichmentBudgetReserve
+}
+
+// fetchCommits fetches a page of commits. Clients paging with cursors resume from repo.LastFetchedCursor and
+// advance it, clearing it once history is exhausted; they page the window from the branch head, so
+// lastFetchedCommit only applies to clients paging with page numbers
+func (uc *gitRepoUsecase) fetchCommits(ctx context.Context, gitClient git.GitManagerClient, repo *domain.RepoMetadata, since time.Time, until time.Time, lastFetchedCommit string, page int32) (*git.CommitPage, error) {
+	cursorClient, ok := gitClient.(git.CursorCommitFetcher)
+	if !ok {
+		commits, morePages, err := gitClient.FetchCommits(ctx, *repo, since, until, lastFetchedCommit, int(page), uc.config.GitCommitFetchPerPage)
+		if err != nil {
+			return nil, err
+		}
+		return &git.CommitPage{Commits: commits, MorePages: morePages}, nil
+	}
+
+	commitPage, err := cursorClient.FetchCommitsAfter(ctx, *repo, since, until, "", repo.LastFetchedCursor, uc.config.GitCommitFetchPerPage)
+	if err != nil {
+		return nil, err
+	}
+
+	repo.LastFetchedCursor = commitPage.Cursor
+	if !commitPage.MorePages {
+		repo.LastFetchedCursor = ""
+	}
+	return commitPage, nil
+}
+
+// saveValidator saves the cache validators of the first page of a listing once the listing was walked, for clients
+// sending conditional requests
+func saveValidator(ctx context.Context, client any, validator *domain.HTTPValidator) {
+	saver, ok := client.(git.ValidatorSaver)
+	if !ok || validator == nil {
+		return
+	}
+
+	if err := saver.SaveValidator(ctx, *validator); err != nil {
+		log.Err(err).Msgf("error saving cache validators of %s", validator.Endpoint)
+	}
+}
+
+// recordProgress records the fetch of the page, the time left is estimated from the rate pages were fetched at
+// since the indexing started and is unknown until the client reported the number of pages
+func recordProgress(progress *domain.IndexingProgress, page int32, commitPage *git.CommitPage, now time.Time) {
+	progress.Page = page
+	progress.UpdatedAt = now
+	if commitPage.TotalPages > 0 {
+		progress.TotalPages = int32(commitPage.TotalPages)
+	}
+
+	if !commitPage.MorePages {
+		progress.TotalPages = page
+		progress.FinishedAt = now
+		progress.EstimatedTimeLeft = 0
+		return
+	}
+
+	progress.EstimatedTimeLeft = 0
+	if progress.TotalPages > page {
+		fetched := page - progress.StartPage + 1
+		perPage := now.Sub(progress.StartedAt) / time.Duration(fetched)
+		progress.EstimatedTimeLeft = perPage * time.Duration(progress.TotalPages-page)
+	}
+}
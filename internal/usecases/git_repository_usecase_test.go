@@ -0,0 +1,302 @@
+package usecases
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/kenmobility/git-api-service/infra/config"
+	"github.com/kenmobility/git-api-service/infra/git"
+	git_mocks "github.com/kenmobility/git-api-service/infra/git/mocks"
+	"github.com/kenmobility/git-api-service/internal/domain"
+	repo_mocks "github.com/kenmobility/git-api-service/internal/repository/mocks"
+	"github.com/kenmobility/git-api-service/pkg/message"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+// cursorTestClient is a GitHub like client paging commits with cursors and tracking branches
+type cursorTestClient struct {
+	*git_mocks.MockGitManagerClient
+	*git_mocks.MockCursorCommitFetcher
+	*git_mocks.MockBranchCommitFetcher
+	*git_mocks.MockValidatorSaver
+}
+
+type usecaseTest struct {
+	uc         *gitRepoUsecase
+	store      *repo_mocks.MockRepository
+	git        *git_mocks.MockGitManagerClient
+	cursors    *git_mocks.MockCursorCommitFetcher
+	branches   *git_mocks.MockBranchCommitFetcher
+	validators *git_mocks.MockValidatorSaver
+	repo       domain.RepoMetadata
+}
+
+func newUsecaseTest(t *testing.T) *usecaseTest {
+	ctrl := gomock.NewController(t)
+	store := repo_mocks.NewMockRepository(ctrl)
+
+	client := cursorTestClient{
+		MockGitManagerClient:    git_mocks.NewMockGitManagerClient(ctrl),
+		MockCursorCommitFetcher: git_mocks.NewMockCursorCommitFetcher(ctrl),
+		MockBranchCommitFetcher: git_mocks.NewMockBranchCommitFetcher(ctrl),
+		MockValidatorSaver:      git_mocks.NewMockValidatorSaver(ctrl),
+	}
+
+	registry := git.NewRegistry("github.com")
+	registry.Register("github", "github.com", client)
+
+	cfg := config.Config{
+		GitCommitFetchPerPage: 3,
+		DefaultStartDate:      time.Now().AddDate(-1, 0, 0),
+		DefaultEndDate:        time.Now(),
+	}
+
+	uc := NewGitRepositoryUsecase(store, store, store, store, store, store, store, store, store, registry, cfg).(*gitRepoUsecase)
+
+	return &usecaseTest{
+		uc:         uc,
+		store:      store,
+		git:        client.MockGitManagerClient,
+		cursors:    client.MockCursorCommitFetcher,
+		branches:   client.MockBranchCommitFetcher,
+		validators: client.MockValidatorSaver,
+		repo:       domain.RepoMetadata{PublicID: "repo-id", Name: "sample/repo", Host: "github.com", DefaultBranch: "main"},
+	}
+}
+
+func testCommits(ids ...string) []domain.Commit {
+	commits := make([]domain.Commit, 0, len(ids))
+	for _, id := range ids {
+		commits = append(commits, domain.Commit{CommitID: id, RepositoryName: "sample/repo", RepositoryHost: "github.com"})
+	}
+	return commits
+}
+
+// expectStored makes the commit repository report the commits in stored as saved before, the others are saved
+func (u *usecaseTest) expectStored(ids []string, stored ...string) {
+	known := make(map[string]bool, len(stored))
+	for _, id := range stored {
+		known[id] = true
+	}
+
+	for _, id := range ids {
+		if known[id] {
+			u.store.EXPECT().GetByCommitID(gomock.Any(), gomock.Any(), id).Return(&domain.Commit{CommitID: id}, nil)
+			continue
+		}
+		u.store.EXPECT().GetByCommitID(gomock.Any(), gomock.Any(), id).Return(nil, message.ErrNoRecordFound)
+		u.store.EXPECT().SaveCommit(gomock.Any(), gomock.Any()).Return(&domain.Commit{CommitID: id}, nil)
+	}
+}
+
+// expectDefaultBranchHead serves head as the head of the default branch, which was indexed at previousHead
+func (u *usecaseTest) expectDefaultBranchHead(head, previousHead string) {
+	u.branches.EXPECT().FetchBranchCommits(gomock.Any(), gomock.Any(), "main", gomock.Any(), gomock.Any(), "", 1).
+		Return(&git.CommitPage{Commits: testCommits(head), MorePages: true}, nil)
+	u.store.EXPECT().BranchesByRepository(gomock.Any(), gomock.Any()).
+		Return([]domain.Branch{{Name: "main", HeadSHA: previousHead}}, nil)
+}
+
+func TestReconcileDefaultBranchFastForward(t *testing.T) {
+	u := newUsecaseTest(t)
+	ctx := context.Background()
+
+	u.expectDefaultBranchHead("c4", "c2")
+
+	history := []string{"c4", "c3", "c2"}
+	u.branches.EXPECT().FetchBranchCommits(gomock.Any(), gomock.Any(), "main", gomock.Any(), gomock.Any(), "", 3).
+		Return(&git.CommitPage{Commits: testCommits(history...), MorePages: true, Cursor: "cursor-1"}, nil)
+	u.store.EXPECT().CountCommitsInBranch(gomock.Any(), gomock.Any(), "main", history).Return(int64(1), nil)
+	u.expectStored(history, "c2")
+	u.store.EXPECT().AddCommitsToBranch(gomock.Any(), gomock.Any(), "main", history).Return(nil)
+
+	// the previous head was reached but the page was not recorded in the branch before, so paging goes on
+	u.branches.EXPECT().FetchBranchCommits(gomock.Any(), gomock.Any(), "main", gomock.Any(), gomock.Any(), "cursor-1", 3).
+		Return(&git.CommitPage{Commits: testCommits("c1"), MorePages: true, Cursor: "cursor-2"}, nil)
+	u.store.EXPECT().CountCommitsInBranch(gomock.Any(), gomock.Any(), "main", []string{"c1"}).Return(int64(1), nil)
+	u.expectStored([]string{"c1"}, "c1")
+	u.store.EXPECT().AddCommitsToBranch(gomock.Any(), gomock.Any(), "main", []string{"c1"}).Return(nil)
+
+	u.store.EXPECT().SaveBranch(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, _ domain.RepoMetadata, branch domain.Branch) error {
+			require.Equal(t, "c4", branch.HeadSHA)
+			return nil
+		})
+
+	repo := u.repo
+	u.uc.reconcileDefaultBranch(ctx, u.client(t, repo), &repo)
+
+	// nothing is removed from the branch and the commit paging of the repository is kept
+	require.Equal(t, u.repo, repo)
+}
+
+func TestReconcileDefaultBranchForcePush(t *testing.T) {
+	u := newUsecaseTest(t)
+	ctx := context.Background()
+
+	u.repo.LastFetchedPage = 4
+	u.repo.LastFetchedCursor = "cursor-4"
+	u.expectDefaultBranchHead("c3-amended", "c3")
+
+	history := []string{"c3-amended", "c2", "c1"}
+	u.branches.EXPECT().FetchBranchCommits(gomock.Any(), gomock.Any(), "main", gomock.Any(), gomock.Any(), "", 3).
+		Return(&git.CommitPage{Commits: testCommits(history...), MorePages: false}, nil)
+	u.store.EXPECT().CountCommitsInBranch(gomock.Any(), gomock.Any(), "main", history).Return(int64(2), nil)
+	u.expectStored(history, "c2", "c1")
+	u.store.EXPECT().AddCommitsToBranch(gomock.Any(), gomock.Any(), "main", history).Return(nil)
+
+	// the previous head is no longer reachable from the head, it is dropped from the branch
+	u.store.EXPECT().CommitIDsInBranch(gomock.Any(), gomock.Any(), "main").Return([]string{"c3", "c2", "c1"}, nil)
+	u.store.EXPECT().RemoveCommitsFromBranch(gomock.Any(), gomock.Any(), "main", []string{"c3"}, gomock.Any()).Return(int64(1), nil)
+	u.store.EXPECT().SaveHistoryRewrite(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, _ domain.RepoMetadata, rewrite domain.HistoryRewrite) error {
+			require.Equal(t, "main", rewrite.Branch)
+			require.Equal(t, "c3", rewrite.PreviousHeadSHA)
+			require.Equal(t, "c3-amended", rewrite.HeadSHA)
+			require.Equal(t, int64(1), rewrite.OrphanedCommits)
+			return nil
+		})
+	u.store.EXPECT().SaveBranch(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
+
+	// the commit paging restarts from the new head
+	u.store.EXPECT().UpdateRepoMetadata(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, repo domain.RepoMetadata) (*domain.RepoMetadata, error) {
+			require.Equal(t, int32(1), repo.LastFetchedPage)
+			require.Empty(t, repo.LastFetchedCursor)
+			return &repo, nil
+		})
+
+	repo := u.repo
+	u.uc.reconcileDefaultBranch(ctx, u.client(t, repo), &repo)
+	require.Equal(t, int32(1), repo.LastFetchedPage)
+}
+
+func TestReconcileDefaultBranchPreviousHeadOutsideIndexedHistory(t *testing.T) {
+	u := newUsecaseTest(t)
+	ctx := context.Background()
+
+	// the branch was recorded at a head older than the start date, which was never indexed
+	u.expectDefaultBranchHead("c2", "c0")
+
+	history := []string{"c2", "c1"}
+	u.branches.EXPECT().FetchBranchCommits(gomock.Any(), gomock.Any(), "main", gomock.Any(), gomock.Any(), "", 3).
+		Return(&git.CommitPage{Commits: testCommits(history...), MorePages: false}, nil)
+	u.store.EXPECT().CountCommitsInBranch(gomock.Any(), gomock.Any(), "main", history).Return(int64(1), nil)
+	u.expectStored(history, "c1")
+	u.store.EXPECT().AddCommitsToBranch(gomock.Any(), gomock.Any(), "main", history).Return(nil)
+
+	// the listing never reaches the previous head, which was not recorded in the branch so nothing is removed
+	u.store.EXPECT().CommitIDsInBranch(gomock.Any(), gomock.Any(), "main").Return([]string{"c1"}, nil)
+	u.store.EXPECT().SaveBranch(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
+
+	repo := u.repo
+	u.uc.reconcileDefaultBranch(ctx, u.client(t, repo), &repo)
+	require.Equal(t, u.repo, repo)
+}
+
+func TestIndexBranchUnmodifiedFirstPage(t *testing.T) {
+	u := newUsecaseTest(t)
+	ctx := context.Background()
+
+	// a conditional request of the first page answered not modified
+	u.branches.EXPECT().FetchBranchCommits(gomock.Any(), gomock.Any(), "release", gomock.Any(), gomock.Any(), "", 3).
+		Return(&git.CommitPage{}, nil)
+	u.store.EXPECT().SaveBranch(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
+
+	rewritten, err := u.uc.indexBranch(ctx, u.branches, u.repo, domain.Branch{Name: "release", HeadSHA: "c2"}, "c1")
+	require.NoError(t, err)
+	require.False(t, rewritten)
+}
+
+func TestIndexBranchSavesValidatorsOnceWalked(t *testing.T) {
+	u := newUsecaseTest(t)
+	ctx := context.Background()
+	branchClient := u.client(t, u.repo).(git.BranchCommitFetcher)
+
+	validator := &domain.HTTPValidator{Endpoint: "release-listing", ETag: `"abc"`}
+	firstPage := func() {
+		u.branches.EXPECT().FetchBranchCommits(gomock.Any(), gomock.Any(), "release", gomock.Any(), gomock.Any(), "", 3).
+			Return(&git.CommitPage{Commits: testCommits("c3"), MorePages: true, Cursor: "cursor-1", Validator: validator}, nil)
+		u.store.EXPECT().CountCommitsInBranch(gomock.Any(), gomock.Any(), "release", []string{"c3"}).Return(int64(0), nil)
+		u.expectStored([]string{"c3"})
+		u.store.EXPECT().AddCommitsToBranch(gomock.Any(), gomock.Any(), "release", []string{"c3"}).Return(nil)
+	}
+
+	// the walk fails after the first page, whose validators are not saved so the retry fetches it in full
+	firstPage()
+	u.branches.EXPECT().FetchBranchCommits(gomock.Any(), gomock.Any(), "release", gomock.Any(), gomock.Any(), "cursor-1", 3).
+		Return(nil, message.ErrRateLimitExceeded)
+
+	_, err := u.uc.indexBranch(ctx, branchClient, u.repo, domain.Branch{Name: "release", HeadSHA: "c3"}, "c1")
+	require.ErrorIs(t, err, message.ErrRateLimitExceeded)
+
+	// the retry walks the branch to its previous head, then saves the validators
+	firstPage()
+	u.branches.EXPECT().FetchBranchCommits(gomock.Any(), gomock.Any(), "release", gomock.Any(), gomock.Any(), "cursor-1", 3).
+		Return(&git.CommitPage{Commits: testCommits("c2", "c1"), MorePages: false}, nil)
+	u.store.EXPECT().CountCommitsInBranch(gomock.Any(), gomock.Any(), "release", []string{"c2", "c1"}).Return(int64(1), nil)
+	u.expectStored([]string{"c2", "c1"}, "c1")
+	u.store.EXPECT().AddCommitsToBranch(gomock.Any(), gomock.Any(), "release", []string{"c2", "c1"}).Return(nil)
+	u.store.EXPECT().SaveBranch(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
+	u.validators.EXPECT().SaveValidator(gomock.Any(), *validator).Return(nil)
+
+	rewritten, err := u.uc.indexBranch(ctx, branchClient, u.repo, domain.Branch{Name: "release", HeadSHA: "c3"}, "c1")
+	require.NoError(t, err)
+	require.False(t, rewritten)
+}
+
+func TestFetchAndReconcileCommitsStopsAtStoredPage(t *testing.T) {
+	u := newUsecaseTest(t)
+	ctx := context.Background()
+
+	u.repo.LastFetchedPage = 1
+	u.repo.LastFetchedCommit = "c3"
+
+	u.cursors.EXPECT().FetchCommitsAfter(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "", "", 3).
+		Return(&git.CommitPage{Commits: testCommits("c5", "c4", "c3"), MorePages: true, Cursor: "cursor-1"}, nil)
+	u.expectStored([]string{"c5", "c4", "c3"}, "c3")
+	u.store.EXPECT().AddCommitsToBranch(gomock.Any(), gomock.Any(), "main", gomock.Any()).Return(nil).Times(2)
+	u.store.EXPECT().UpdateRepoMetadata(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, repo domain.RepoMetadata) (*domain.RepoMetadata, error) {
+			require.Equal(t, "cursor-1", repo.LastFetchedCursor)
+			return &repo, nil
+		})
+
+	// every commit of the second page was stored before, the older pages are not fetched
+	u.cursors.EXPECT().FetchCommitsAfter(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "", "cursor-1", 3).
+		Return(&git.CommitPage{Commits: testCommits("c2", "c1"), MorePages: true, Cursor: "cursor-2"}, nil)
+	u.expectStored([]string{"c2", "c1"}, "c2", "c1")
+	u.store.EXPECT().UpdateRepoMetadata(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, repo domain.RepoMetadata) (*domain.RepoMetadata, error) {
+			require.Empty(t, repo.LastFetchedCursor)
+			require.Equal(t, "c4", repo.LastFetchedCommit)
+			return &repo, nil
+		})
+
+	u.uc.fetchAndReconcileCommits(ctx, u.repo)
+}
+
+// client returns the client registered for the repository
+func (u *usecaseTest) client(t *testing.T, repo domain.RepoMetadata) git.GitManagerClient {
+	gitClient, err := u.uc.gitClients.Client(repo)
+	require.NoError(t, err)
+	return gitClient
+}
+
+func TestMonitorRepositoryReturnsFetchErrors(t *testing.T) {
+	u := newUsecaseTest(t)
+	ctx := context.Background()
+
+	u.git.EXPECT().FetchRepoMetadata(gomock.Any(), "sample/repo").Return(&domain.RepoMetadata{Name: "sample/repo", DefaultBranch: "main"}, nil)
+	u.store.EXPECT().RefreshRepoMetadata(gomock.Any(), gomock.Any()).Return(nil)
+
+	// the round stops at the failed stage so the job is retried with a backoff
+	u.branches.EXPECT().FetchBranchCommits(gomock.Any(), gomock.Any(), "main", gomock.Any(), gomock.Any(), "", 1).
+		Return(nil, message.ErrRateLimitExceeded)
+
+	repo := u.repo
+	err := u.uc.monitorRepository(ctx, &repo)
+	require.ErrorIs(t, err, message.ErrRateLimitExceeded)
+}
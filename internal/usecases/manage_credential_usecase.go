@@ -0,0 +1,49 @@
+package usecases
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/kenmobility/git-api-service/internal/domain"
+	"github.com/kenmobility/git-api-service/internal/repository"
+	"github.com/kenmobility/git-api-service/pkg/message"
+)
+
+type ManageCredentialUsecase interface {
+	AddCredential(ctx context.Context, name string, token string) (*domain.Credential, error)
+	GetAll(ctx context.Context) ([]domain.Credential, error)
+}
+
+type manageCredentialUsecase struct {
+	credentialRepository repository.CredentialRepository
+}
+
+func NewManageCredentialUsecase(credentialRepo repository.CredentialRepository) ManageCredentialUsecase {
+	return &manageCredentialUsecase{
+		credentialRepository: credentialRepo,
+	}
+}
+
+// AddCredential stores a named credential, which repositories are then added with by its name
+func (uc *manageCredentialUsecase) AddCredential(ctx context.Context, name string, token string) (*domain.Credential, error) {
+	existing, err := uc.credentialRepository.CredentialByName(ctx, name)
+	if err != nil && err != message.ErrCredentialNotFound {
+		return nil, err
+	}
+	if existing != nil {
+		return nil, message.ErrCredentialAlreadyAdded
+	}
+
+	return uc.credentialRepository.SaveCredential(ctx, domain.Credential{
+		PublicID:  uuid.New().String(),
+		Name:      name,
+		Token:     token,
+		CreatedAt: time.Now(),
+	})
+}
+
+// GetAll returns the named credentials, without their tokens
+func (uc *manageCredentialUsecase) GetAll(ctx context.Context) ([]domain.Credential, error) {
+	return uc.credentialRepository.AllCredentials(ctx)
+}
@@ -5,36 +5,45 @@ import (
 
 	"github.com/kenmobility/git-api-service/internal/domain"
 	"github.com/kenmobility/git-api-service/internal/repository"
+	"github.com/kenmobility/git-api-service/pkg/message"
 )
 
 type ManageGitCommitUsecase interface {
-	GetAllCommitsByRepository(ctx context.Context, repoId string, query domain.APIPagingData) (*string, []domain.Commit, *domain.PagingInfo, error)
+	GetAllCommitsByRepository(ctx context.Context, repoId string, filter domain.CommitFilter, query domain.APIPagingData) (*string, []domain.Commit, *domain.PagingInfo, error)
 	GetTopRepositoryCommitAuthors(ctx context.Context, repoId string, limit int) (*string, []domain.AuthorCommitCount, error)
+	GetCommit(ctx context.Context, repoId string, commitId string) (*domain.Commit, error)
 }
 
 type manageGitCommitUsecase struct {
 	commitRepository       repository.CommitRepository
 	repoMetadataRepository repository.RepoMetadataRepository
+	pullRequestRepository  repository.PullRequestRepository
 }
 
-func NewManageGitCommitUsecase(commitRepo repository.CommitRepository, repoMetadataRepository repository.RepoMetadataRepository) ManageGitCommitUsecase {
+func NewManageGitCommitUsecase(commitRepo repository.CommitRepository, repoMetadataRepository repository.RepoMetadataRepository,
+	pullRequestRepo repository.PullRequestRepository) ManageGitCommitUsecase {
 	return &manageGitCommitUsecase{
 		commitRepository:       commitRepo,
 		repoMetadataRepository: repoMetadataRepository,
+		pullRequestRepository:  pullRequestRepo,
 	}
 }
 
-func (uc *manageGitCommitUsecase) GetAllCommitsByRepository(ctx context.Context, repoId string, query domain.APIPagingData) (*string, []domain.Commit, *domain.PagingInfo, error) {
+func (uc *manageGitCommitUsecase) GetAllCommitsByRepository(ctx context.Context, repoId string, filter domain.CommitFilter, query domain.APIPagingData) (*string, []domain.Commit, *domain.PagingInfo, error) {
 	repoMetaData, err := uc.repoMetadataRepository.RepoMetadataByPublicId(ctx, repoId)
 	if err != nil {
 		return nil, nil, nil, err
 	}
 
-	commits, pagingInfo, err := uc.commitRepository.AllCommitsByRepository(ctx, *repoMetaData, query)
+	commits, pagingInfo, err := uc.commitRepository.AllCommitsByRepository(ctx, *repoMetaData, filter, query)
 	if err != nil {
 		return nil, nil, nil, err
 	}
 
+	if err := uc.addPullRequests(ctx, *repoMetaData, commits); err != nil {
+		return nil, nil, nil, err
+	}
+
 	return &repoMetaData.Name, commits, pagingInfo, nil
 }
 
@@ -51,3 +60,41 @@ func (uc *manageGitCommitUsecase) GetTopRepositoryCommitAuthors(ctx context.Cont
 
 	return &repoMetaData.Name, authors, nil
 }
+
+// GetCommit fetches a commit of the repository with its change statistics and the files it changed
+func (uc *manageGitCommitUsecase) GetCommit(ctx context.Context, repoId string, commitId string) (*domain.Commit, error) {
+	repoMetaData, err := uc.repoMetadataRepository.RepoMetadataByPublicId(ctx, repoId)
+	if err != nil {
+		return nil, err
+	}
+
+	commit, err := uc.commitRepository.CommitWithFiles(ctx, *repoMetaData, commitId)
+	if err != nil {
+		if err == message.ErrNoRecordFound {
+			return nil, message.ErrInvalidCommitId
+		}
+		return nil, err
+	}
+
+	commits := []domain.Commit{*commit}
+	if err := uc.addPullRequests(ctx, *repoMetaData, commits); err != nil {
+		return nil, err
+	}
+
+	return &commits[0], nil
+}
+
+// addPullRequests sets the pull request which brought in each of the commits
+func (uc *manageGitCommitUsecase) addPullRequests(ctx context.Context, repo domain.RepoMetadata, commits []domain.Commit) error {
+	pullRequests, err := uc.pullRequestRepository.PullRequestsByCommits(ctx, repo, commitIDs(commits))
+	if err != nil {
+		return err
+	}
+
+	for i := range commits {
+		if pr, ok := pullRequests[commits[i].CommitID]; ok {
+			commits[i].PullRequest = &pr
+		}
+	}
+	return nil
+}
@@ -0,0 +1,40 @@
+package usecases
+
+import (
+	"context"
+
+	"github.com/kenmobility/git-api-service/internal/domain"
+	"github.com/kenmobility/git-api-service/internal/repository"
+	"github.com/kenmobility/git-api-service/pkg/message"
+)
+
+type ManageJobUsecase interface {
+	GetAllJobs(ctx context.Context, status string, query domain.APIPagingData) ([]domain.Job, *domain.PagingInfo, error)
+	RequeueJob(ctx context.Context, jobId string) (*domain.Job, error)
+}
+
+type manageJobUsecase struct {
+	jobRepository repository.JobRepository
+}
+
+func NewManageJobUsecase(jobRepo repository.JobRepository) ManageJobUsecase {
+	return &manageJobUsecase{
+		jobRepository: jobRepo,
+	}
+}
+
+// GetAllJobs returns the queued jobs, narrowed to the ones in the status when it is given
+func (uc *manageJobUsecase) GetAllJobs(ctx context.Context, status string, query domain.APIPagingData) ([]domain.Job, *domain.PagingInfo, error) {
+	switch status {
+	case "", domain.JobStatusPending, domain.JobStatusRunning, domain.JobStatusDone, domain.JobStatusDead:
+	default:
+		return nil, nil, message.ErrInvalidJobStatus
+	}
+
+	return uc.jobRepository.AllJobs(ctx, status, query)
+}
+
+// RequeueJob queues a dead job again, to run right away with all its attempts
+func (uc *manageJobUsecase) RequeueJob(ctx context.Context, jobId string) (*domain.Job, error) {
+	return uc.jobRepository.RequeueJob(ctx, jobId)
+}
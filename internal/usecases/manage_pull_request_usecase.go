@@ -0,0 +1,45 @@
+package usecases
+
+import (
+	"context"
+
+	"github.com/kenmobility/git-api-service/internal/domain"
+	"github.com/kenmobility/git-api-service/internal/repository"
+	"github.com/kenmobility/git-api-service/pkg/message"
+)
+
+type ManagePullRequestUsecase interface {
+	GetPullRequestsByRepository(ctx context.Context, repoId string, filter domain.PullRequestFilter, query domain.APIPagingData) (*string, []domain.PullRequest, *domain.PagingInfo, error)
+}
+
+type managePullRequestUsecase struct {
+	pullRequestRepository  repository.PullRequestRepository
+	repoMetadataRepository repository.RepoMetadataRepository
+}
+
+func NewManagePullRequestUsecase(pullRequestRepo repository.PullRequestRepository, repoMetadataRepository repository.RepoMetadataRepository) ManagePullRequestUsecase {
+	return &managePullRequestUsecase{
+		pullRequestRepository:  pullRequestRepo,
+		repoMetadataRepository: repoMetadataRepository,
+	}
+}
+
+func (uc *managePullRequestUsecase) GetPullRequestsByRepository(ctx context.Context, repoId string, filter domain.PullRequestFilter, query domain.APIPagingData) (*string, []domain.PullRequest, *domain.PagingInfo, error) {
+	switch filter.State {
+	case "", domain.PullRequestOpen, domain.PullRequestClosed, domain.PullRequestMerged:
+	default:
+		return nil, nil, nil, message.ErrInvalidPullRequestState
+	}
+
+	repoMetaData, err := uc.repoMetadataRepository.RepoMetadataByPublicId(ctx, repoId)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	pullRequests, pagingInfo, err := uc.pullRequestRepository.PullRequestsByRepository(ctx, *repoMetaData, filter, query)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	return &repoMetaData.Name, pullRequests, pagingInfo, nil
+}
@@ -0,0 +1,53 @@
+package usecases
+
+import (
+	"context"
+
+	"github.com/kenmobility/git-api-service/internal/domain"
+	"github.com/kenmobility/git-api-service/internal/repository"
+)
+
+type ManageReleaseUsecase interface {
+	GetTagsByRepository(ctx context.Context, repoId string, query domain.APIPagingData) (*string, []domain.Tag, *domain.PagingInfo, error)
+	GetReleasesByRepository(ctx context.Context, repoId string, query domain.APIPagingData) (*string, []domain.Release, *domain.PagingInfo, error)
+}
+
+type manageReleaseUsecase struct {
+	releaseRepository      repository.ReleaseRepository
+	repoMetadataRepository repository.RepoMetadataRepository
+}
+
+func NewManageReleaseUsecase(releaseRepo repository.ReleaseRepository, repoMetadataRepository repository.RepoMetadataRepository) ManageReleaseUsecase {
+	return &manageReleaseUsecase{
+		releaseRepository:      releaseRepo,
+		repoMetadataRepository: repoMetadataRepository,
+	}
+}
+
+func (uc *manageReleaseUsecase) GetTagsByRepository(ctx context.Context, repoId string, query domain.APIPagingData) (*string, []domain.Tag, *domain.PagingInfo, error) {
+	repoMetaData, err := uc.repoMetadataRepository.RepoMetadataByPublicId(ctx, repoId)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	tags, pagingInfo, err := uc.releaseRepository.TagsByRepository(ctx, *repoMetaData, query)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	return &repoMetaData.Name, tags, pagingInfo, nil
+}
+
+func (uc *manageReleaseUsecase) GetReleasesByRepository(ctx context.Context, repoId string, query domain.APIPagingData) (*string, []domain.Release, *domain.PagingInfo, error) {
+	repoMetaData, err := uc.repoMetadataRepository.RepoMetadataByPublicId(ctx, repoId)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	releases, pagingInfo, err := uc.releaseRepository.ReleasesByRepository(ctx, *repoMetaData, query)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	return &repoMetaData.Name, releases, pagingInfo, nil
+}
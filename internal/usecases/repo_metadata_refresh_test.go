@@ -0,0 +1,116 @@
+package usecases
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/kenmobility/git-api-service/internal/domain"
+	"github.com/kenmobility/git-api-service/pkg/message"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+// expectRefreshLease makes the refresh of the repository acquire its lease, the repository is loaded again under the
+// lease and the lease is released once refreshed
+func (u *usecaseTest) expectRefreshLease() {
+	u.store.EXPECT().AcquireRepoLease(gomock.Any(), "repo-id", gomock.Any(), gomock.Any()).Return(true, nil)
+	u.store.EXPECT().RepoMetadataByPublicId(gomock.Any(), "repo-id").Return(&u.repo, nil)
+	u.store.EXPECT().ReleaseRepoLease(gomock.Any(), "repo-id", gomock.Any()).Return(nil)
+}
+
+func TestRefreshRepoMetadataSavesSnapshot(t *testing.T) {
+	u := newUsecaseTest(t)
+	ctx := context.Background()
+
+	u.expectRefreshLease()
+	u.git.EXPECT().FetchRepoMetadata(gomock.Any(), "sample/repo").Return(&domain.RepoMetadata{
+		Name: "sample/repo", DefaultBranch: "main", StarsCount: 12, ForksCount: 3, WatchersCount: 5, OpenIssuesCount: 2,
+	}, nil)
+	u.store.EXPECT().RefreshRepoMetadata(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, repo domain.RepoMetadata) error {
+			require.Equal(t, 12, repo.StarsCount)
+			require.Equal(t, domain.RepoStatusActive, repo.Status)
+			return nil
+		})
+	u.store.EXPECT().SaveSnapshot(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, _ domain.RepoMetadata, snapshot domain.RepoMetadataSnapshot) error {
+			require.Equal(t, 12, snapshot.StarsCount)
+			require.Equal(t, 3, snapshot.ForksCount)
+			require.Equal(t, 5, snapshot.WatchersCount)
+			require.Equal(t, 2, snapshot.OpenIssuesCount)
+			require.False(t, snapshot.CapturedAt.IsZero())
+			return nil
+		})
+
+	u.uc.refreshRepoMetadata(ctx, u.repo)
+}
+
+func TestRefreshRepoMetadataDeletedRepository(t *testing.T) {
+	u := newUsecaseTest(t)
+	ctx := context.Background()
+
+	u.repo.StarsCount = 12
+	u.expectRefreshLease()
+	u.git.EXPECT().FetchRepoMetadata(gomock.Any(), "sample/repo").Return(nil, message.ErrRepositoryNotFound)
+
+	// the status is recorded, the last known metadata is kept and no snapshot is captured
+	u.store.EXPECT().RefreshRepoMetadata(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, repo domain.RepoMetadata) error {
+			require.Equal(t, domain.RepoStatusDeleted, repo.Status)
+			require.Equal(t, 12, repo.StarsCount)
+			return nil
+		})
+
+	u.uc.refreshRepoMetadata(ctx, u.repo)
+}
+
+func TestRefreshRepoMetadataSkipsLeasedRepository(t *testing.T) {
+	u := newUsecaseTest(t)
+	ctx := context.Background()
+
+	// a job syncs the repository, it is neither fetched nor renamed by the refresh
+	u.store.EXPECT().AcquireRepoLease(gomock.Any(), "repo-id", gomock.Any(), gomock.Any()).Return(false, nil)
+
+	u.uc.refreshRepoMetadata(ctx, u.repo)
+}
+
+func TestRefreshRepoMetadataReloadsRepository(t *testing.T) {
+	u := newUsecaseTest(t)
+	ctx := context.Background()
+
+	// a job renamed the repository after it was listed for the refresh, which fetches it under its new name
+	listed := u.repo
+	u.repo.Name = "sample/renamed"
+	u.expectRefreshLease()
+	u.git.EXPECT().FetchRepoMetadata(gomock.Any(), "sample/renamed").Return(&domain.RepoMetadata{
+		Name: "sample/renamed", DefaultBranch: "main", StarsCount: 12,
+	}, nil)
+	u.store.EXPECT().RefreshRepoMetadata(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, repo domain.RepoMetadata) error {
+			require.Equal(t, "sample/renamed", repo.Name)
+			return nil
+		})
+	u.store.EXPECT().SaveSnapshot(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
+
+	u.uc.refreshRepoMetadata(ctx, listed)
+}
+
+func TestGetMetadataHistory(t *testing.T) {
+	u := newUsecaseTest(t)
+	ctx := context.Background()
+
+	since, until := time.Now().AddDate(0, 0, -7), time.Now()
+
+	_, _, err := u.uc.GetMetadataHistory(ctx, "repo-id", until, since)
+	require.Equal(t, message.ErrInvalidTimeRange, err)
+
+	snapshots := []domain.RepoMetadataSnapshot{{StarsCount: 10, CapturedAt: since}, {StarsCount: 12, CapturedAt: until}}
+	u.store.EXPECT().RepoMetadataByPublicId(gomock.Any(), "repo-id").Return(&u.repo, nil)
+	u.store.EXPECT().SnapshotsByRepository(gomock.Any(), u.repo, since, until).Return(snapshots, nil)
+
+	repo, history, err := u.uc.GetMetadataHistory(ctx, "repo-id", since, until)
+	require.NoError(t, err)
+	require.Equal(t, u.repo.Name, repo.Name)
+	require.Equal(t, snapshots, history)
+}
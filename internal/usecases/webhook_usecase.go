@@ -0,0 +1,156 @@
+package usecases
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/kenmobility/git-api-service/infra/config"
+	"github.com/kenmobility/git-api-service/infra/git"
+	"github.com/kenmobility/git-api-service/internal/domain"
+	"github.com/kenmobility/git-api-service/internal/repository"
+	"github.com/kenmobility/git-api-service/pkg/message"
+	"github.com/rs/zerolog/log"
+)
+
+type WebhookUsecase interface {
+	HandleGitHubEvent(ctx context.Context, event string, deliveryID string, signature string, body []byte) (int, error)
+}
+
+type webhookUsecase struct {
+	repoMetadataRepository    repository.RepoMetadataRepository
+	commitRepository          repository.CommitRepository
+	branchRepository          repository.BranchRepository
+	webhookDeliveryRepository repository.WebhookDeliveryRepository
+	jobRepository             repository.JobRepository
+	config                    config.Config
+}
+
+func NewWebhookUsecase(repoMetadataRepo repository.RepoMetadataRepository, commitRepo repository.CommitRepository,
+	branchRepo repository.BranchRepository, webhookDeliveryRepo repository.WebhookDeliveryRepository,
+	jobRepo repository.JobRepository, config config.Config) WebhookUsecase {
+	return &webhookUsecase{
+		repoMetadataRepository:    repoMetadataRepo,
+		commitRepository:          commitRepo,
+		branchRepository:          branchRepo,
+		webhookDeliveryRepository: webhookDeliveryRepo,
+		jobRepository:             jobRepo,
+		config:                    config,
+	}
+}
+
+// HandleGitHubEvent verifies a GitHub webhook delivery and saves the commits pushed to the default branch or a tracked
+// branch of the repository, returning how many were saved. A delivery is processed once, a failed one is processed
+// again when redelivered
+func (uc *webhookUsecase) HandleGitHubEvent(ctx context.Context, event string, deliveryID string, signature string, body []byte) (int, error) {
+	if uc.config.GitHubWebhookSecret == "" {
+		return 0, message.ErrWebhookNotConfigured
+	}
+
+	if !git.VerifyGitHubSignature(uc.config.GitHubWebhookSecret, signature, body) {
+		return 0, message.ErrInvalidWebhookSignature
+	}
+
+	switch event {
+	case git.GitHubPingEvent:
+		return 0, nil
+	case git.GitHubPushEvent:
+	default:
+		return 0, message.ErrUnsupportedWebhookEvent
+	}
+
+	push, err := git.ParseGitHubPushEvent(body, uc.config.GitHubHost)
+	if err != nil {
+		log.Err(err).Msgf("error parsing push event of delivery %s", deliveryID)
+		return 0, message.ErrInvalidInput
+	}
+
+	repo, err := uc.repoMetadataRepository.RepoMetadataByName(ctx, uc.config.GitHubHost, push.RepositoryName)
+	if err != nil {
+		if err == message.ErrNoRecordFound {
+			return 0, message.ErrRepositoryNotTracked
+		}
+		return 0, err
+	}
+
+	claimed, err := uc.webhookDeliveryRepository.ClaimDelivery(ctx, deliveryID, event)
+	if err != nil {
+		return 0, err
+	}
+	if !claimed {
+		return 0, message.ErrDuplicateWebhookDelivery
+	}
+
+	saved, err := uc.savePushedCommits(ctx, *repo, push)
+	if err != nil {
+		if releaseErr := uc.webhookDeliveryRepository.ReleaseDelivery(context.Background(), deliveryID); releaseErr != nil {
+			log.Err(releaseErr).Msgf("error releasing delivery %s", deliveryID)
+		}
+		return 0, err
+	}
+
+	return saved, nil
+}
+
+// savePushedCommits saves the pushed commits not indexed yet and records the branch contains them, pushes to
+// branches that are not indexed are skipped. The branches of the repository are reconciled with its host when the
+// push rewrote the history, listed only part of its commits or does not follow the indexed history
+func (uc *webhookUsecase) savePushedCommits(ctx context.Context, repo domain.RepoMetadata, push *domain.PushEvent) (int, error) {
+	if push.Deleted || push.Branch == "" {
+		return 0, nil
+	}
+
+	if push.Branch != repo.DefaultBranch && !matchesBranchPatterns(push.Branch, repo.TrackedBranches) {
+		log.Info().Msgf("skipping push to branch %s of repo %s, the branch is not tracked", push.Branch, repo.Name)
+		return 0, nil
+	}
+
+	_, err := uc.commitRepository.GetByCommitID(ctx, repo, push.Before)
+	if err != nil && err != message.ErrNoRecordFound {
+		return 0, err
+	}
+	reconcile := push.Forced || push.Truncated || err == message.ErrNoRecordFound
+
+	saved := 0
+	for _, commit := range push.Commits {
+		_, err := uc.commitRepository.GetByCommitID(ctx, repo, commit.CommitID)
+		if err == nil {
+			continue
+		}
+		if err != message.ErrNoRecordFound {
+			return saved, err
+		}
+
+		if _, err := uc.commitRepository.SaveCommit(ctx, commit); err != nil {
+			return saved, err
+		}
+		saved++
+	}
+
+	if err := uc.branchRepository.AddCommitsToBranch(ctx, repo, push.Branch, commitIDs(push.Commits)); err != nil {
+		return saved, err
+	}
+
+	log.Info().Msgf("saved %d commits pushed to branch %s of repo %s", saved, push.Branch, repo.Name)
+
+	if reconcile {
+		log.Info().Msgf("push to branch %s of repo %s was not fully delivered, queueing the reconcile of its branches", push.Branch, repo.Name)
+		if err := uc.enqueueBranchReconcile(ctx, repo); err != nil {
+			return saved, err
+		}
+	}
+	return saved, nil
+}
+
+// enqueueBranchReconcile queues the reconcile of the branches of the repository, unless one is queued already
+func (uc *webhookUsecase) enqueueBranchReconcile(ctx context.Context, repo domain.RepoMetadata) error {
+	return uc.jobRepository.EnqueueJob(ctx, domain.Job{
+		PublicID:     uuid.New().String(),
+		Kind:         domain.JobKindReconcileBranches,
+		RepositoryID: repo.PublicID,
+		Status:       domain.JobStatusPending,
+		MaxAttempts:  uc.config.JobMaxAttempts,
+		RunAt:        time.Now(),
+	})
+}
@@ -0,0 +1,69 @@
+package usecases
+
+import (
+	"context"
+	"testing"
+
+	"github.com/kenmobility/git-api-service/infra/config"
+	"github.com/kenmobility/git-api-service/internal/domain"
+	repo_mocks "github.com/kenmobility/git-api-service/internal/repository/mocks"
+	"github.com/kenmobility/git-api-service/pkg/message"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func newWebhookTest(t *testing.T) (*webhookUsecase, *repo_mocks.MockRepository) {
+	ctrl := gomock.NewController(t)
+	store := repo_mocks.NewMockRepository(ctrl)
+
+	uc := NewWebhookUsecase(store, store, store, store, store, config.Config{JobMaxAttempts: 5}).(*webhookUsecase)
+	return uc, store
+}
+
+func TestSavePushedCommits(t *testing.T) {
+	repo := domain.RepoMetadata{PublicID: "repo-id", Name: "sample/repo", Host: "github.com", DefaultBranch: "main"}
+
+	tests := []struct {
+		name          string
+		push          domain.PushEvent
+		beforeStored  bool
+		wantReconcile bool
+	}{
+		{name: "fast forward", push: domain.PushEvent{Before: "c1"}, beforeStored: true},
+		{name: "force push", push: domain.PushEvent{Before: "c1", Forced: true}, beforeStored: true, wantReconcile: true},
+		{name: "truncated push", push: domain.PushEvent{Before: "c1", Truncated: true}, beforeStored: true, wantReconcile: true},
+		{name: "previous head not indexed", push: domain.PushEvent{Before: "c1"}, wantReconcile: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uc, store := newWebhookTest(t)
+			ctx := context.Background()
+
+			push := tt.push
+			push.RepositoryName, push.Branch = repo.Name, "main"
+			push.Commits = testCommits("c2")
+
+			if tt.beforeStored {
+				store.EXPECT().GetByCommitID(gomock.Any(), repo, "c1").Return(&domain.Commit{CommitID: "c1"}, nil)
+			} else {
+				store.EXPECT().GetByCommitID(gomock.Any(), repo, "c1").Return(nil, message.ErrNoRecordFound)
+			}
+			store.EXPECT().GetByCommitID(gomock.Any(), repo, "c2").Return(nil, message.ErrNoRecordFound)
+			store.EXPECT().SaveCommit(gomock.Any(), gomock.Any()).Return(&push.Commits[0], nil)
+			store.EXPECT().AddCommitsToBranch(gomock.Any(), repo, "main", []string{"c2"}).Return(nil)
+
+			if tt.wantReconcile {
+				store.EXPECT().EnqueueJob(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, job domain.Job) error {
+					require.Equal(t, domain.JobKindReconcileBranches, job.Kind)
+					require.Equal(t, repo.PublicID, job.RepositoryID)
+					return nil
+				})
+			}
+
+			saved, err := uc.savePushedCommits(ctx, repo, &push)
+			require.NoError(t, err)
+			require.Equal(t, 1, saved)
+		})
+	}
+}
@@ -3,6 +3,7 @@ package client
 import (
 	"bytes"
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
@@ -21,15 +22,55 @@ type Method string
 
 // Supported HTTP verbs.
 const (
-	Get Method = "GET"
+	Get    Method = "GET"
+	Post   Method = "POST"
+	Put    Method = "PUT"
+	Patch  Method = "PATCH"
+	Delete Method = "DELETE"
 )
 
 // Client is an enhanced http.Client.
 // Can plug in cache, etc
-type RestClient struct{}
+type RestClient struct {
+	httpClient  *http.Client
+	retryPolicy RetryPolicy
+}
+
+// Option configures a RestClient
+type Option func(*RestClient)
+
+// WithTimeout sets the timeout of each attempt of the requests of the client
+func WithTimeout(timeout time.Duration) Option {
+	return func(r *RestClient) {
+		r.httpClient = &http.Client{Timeout: timeout}
+	}
+}
 
-func NewRestClient() *RestClient {
-	return &RestClient{}
+// WithHTTPClient sends the requests of the client with httpClient
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(r *RestClient) {
+		r.httpClient = httpClient
+	}
+}
+
+// WithRetryPolicy retries the failed requests of the client with the policy
+func WithRetryPolicy(retryPolicy RetryPolicy) Option {
+	return func(r *RestClient) {
+		r.retryPolicy = retryPolicy
+	}
+}
+
+// NewRestClient returns a client sending requests with DefaultHTTPClient and retrying them with
+// DefaultRetryPolicy, unless configured otherwise by the options
+func NewRestClient(opts ...Option) *RestClient {
+	r := &RestClient{
+		httpClient:  DefaultHTTPClient,
+		retryPolicy: DefaultRetryPolicy,
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r
 }
 
 // Request holds the request to an API Call.
@@ -41,16 +82,78 @@ type Request struct {
 	Body        []byte
 }
 
+// RequestOption sets a part of a request
+type RequestOption func(*Request) error
+
+// WithQuery adds the query parameters to the request
+func WithQuery(queryParams map[string]string) RequestOption {
+	return func(r *Request) error {
+		for key, value := range queryParams {
+			r.QueryParams[key] = value
+		}
+		return nil
+	}
+}
+
+// WithHeaders adds the headers to the request
+func WithHeaders(headers map[string]string) RequestOption {
+	return func(r *Request) error {
+		for key, value := range headers {
+			r.Headers[key] = value
+		}
+		return nil
+	}
+}
+
+// WithHeader adds a header to the request
+func WithHeader(key, value string) RequestOption {
+	return func(r *Request) error {
+		r.Headers[key] = value
+		return nil
+	}
+}
+
+// WithBody sets the body of the request
+func WithBody(body []byte) RequestOption {
+	return func(r *Request) error {
+		r.Body = body
+		return nil
+	}
+}
+
+// WithJSONBody sets the body of the request to the JSON encoding of v
+func WithJSONBody(v any) RequestOption {
+	return func(r *Request) error {
+		body, err := json.Marshal(v)
+		if err != nil {
+			return fmt.Errorf("could not marshal request body: %w", err)
+		}
+		r.Body = body
+		r.Headers["Content-Type"] = "application/json"
+		return nil
+	}
+}
+
 type Response struct {
 	StatusCode int
 	Body       string
 	Headers    map[string][]string
 }
 
+// StatusError is returned by DoJSON for responses without a 2xx status code
+type StatusError struct {
+	StatusCode int
+	Body       string
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("unexpected status code: %v, body: %v", e.StatusCode, e.Body)
+}
+
 var ErrNoRequest = errors.New("nil http.Request received")
 
-// MakeRequest uses the DefaultClient to send the request and returns the response.
-func makeRequest(req *http.Request) (*http.Response, error) {
+// MakeRequest uses the http client to send the request and returns the response.
+func makeRequest(httpClient *http.Client, req *http.Request) (*http.Response, error) {
 	if req == nil {
 		return nil, ErrNoRequest
 	}
@@ -63,7 +166,7 @@ func makeRequest(req *http.Request) (*http.Response, error) {
 
 	start := time.Now()
 
-	resp, err = DefaultHTTPClient.Do(req)
+	resp, err = httpClient.Do(req)
 	if err != nil {
 		log.Info().Msgf("Failed to do request; url: %s method: %s, err: %v", url, req.Method, err)
 
@@ -90,29 +193,102 @@ func makeRequest(req *http.Request) (*http.Response, error) {
 	return resp, err
 }
 
-func (r *RestClient) Get(path string, args ...any) (*Response, error) {
-	queryParams := make(map[string]string)
-	headers := make(map[string]string)
-	if len(args) > 0 {
-		queryParams = args[0].(map[string]string)
-	}
+func (r *RestClient) Get(ctx context.Context, path string, opts ...RequestOption) (*Response, error) {
+	return r.Do(ctx, Get, path, opts...)
+}
 
-	if len(args) > 1 {
-		headers = args[1].(map[string]string)
-	}
+func (r *RestClient) Post(ctx context.Context, path string, opts ...RequestOption) (*Response, error) {
+	return r.Do(ctx, Post, path, opts...)
+}
+
+func (r *RestClient) Put(ctx context.Context, path string, opts ...RequestOption) (*Response, error) {
+	return r.Do(ctx, Put, path, opts...)
+}
+
+func (r *RestClient) Patch(ctx context.Context, path string, opts ...RequestOption) (*Response, error) {
+	return r.Do(ctx, Patch, path, opts...)
+}
+
+func (r *RestClient) Delete(ctx context.Context, path string, opts ...RequestOption) (*Response, error) {
+	return r.Do(ctx, Delete, path, opts...)
+}
 
+// Do sends the request, retrying it with the retry policy of the client until ctx is done. Once the request
+// failed on every attempt a RetryError with the last cause is returned, along with the last response if there was one
+func (r *RestClient) Do(ctx context.Context, method Method, path string, opts ...RequestOption) (*Response, error) {
 	request := Request{
-		Method:      Get,
+		Method:      method,
 		BaseURL:     path,
-		Headers:     headers,
-		QueryParams: queryParams,
+		Headers:     make(map[string]string),
+		QueryParams: make(map[string]string),
+	}
+	for _, opt := range opts {
+		if err := opt(&request); err != nil {
+			return nil, err
+		}
+	}
+
+	for retry := 0; ; retry++ {
+		// the request is built for each attempt as sending it consumes its body
+		req, err := BuildRequestObject(ctx, request)
+		if err != nil {
+			return nil, err
+		}
+
+		response, err := r.attempt(req)
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+
+		delay, retryable, cause := r.retryPolicy.retryDelay(retry, response, err)
+		if cause == nil {
+			return response, nil
+		}
+
+		if !retryable || retry >= r.retryPolicy.MaxRetries {
+			retryErr := &RetryError{Attempts: retry + 1, Cause: cause}
+			if response != nil {
+				retryErr.StatusCode = response.StatusCode
+			}
+			log.Error().Msgf("giving up request; url: %s method: %s, %v", request.BaseURL, request.Method, retryErr)
+			return response, retryErr
+		}
+
+		log.Info().Msgf("retrying request in %v; url: %s method: %s, retry: %d/%d, cause: %v", delay, request.BaseURL, request.Method, retry+1, r.retryPolicy.MaxRetries, cause)
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
 	}
-	req, err := BuildRequestObject(request)
+}
+
+// DoJSON sends the request and decodes the JSON body of a 2xx response into a T, the response is returned
+// along with a StatusError for other status codes
+func DoJSON[T any](ctx context.Context, r *RestClient, method Method, path string, opts ...RequestOption) (T, *Response, error) {
+	var result T
+
+	resp, err := r.Do(ctx, method, path, append([]RequestOption{WithHeader("Accept", "application/json")}, opts...)...)
 	if err != nil {
-		return nil, err
+		return result, resp, err
 	}
 
-	resp, err := makeRequest(req)
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return result, resp, &StatusError{StatusCode: resp.StatusCode, Body: resp.Body}
+	}
+
+	if err := json.Unmarshal([]byte(resp.Body), &result); err != nil {
+		return result, resp, fmt.Errorf("could not unmarshal response: %w", err)
+	}
+	return result, resp, nil
+}
+
+// attempt sends the request once
+func (r *RestClient) attempt(req *http.Request) (*Response, error) {
+	resp, err := makeRequest(r.httpClient, req)
 
 	if err != nil {
 		return nil, err
@@ -123,12 +299,12 @@ func (r *RestClient) Get(path string, args ...any) (*Response, error) {
 }
 
 // BuildRequestObject creates the HTTP request object.
-func BuildRequestObject(request Request) (*http.Request, error) {
+func BuildRequestObject(ctx context.Context, request Request) (*http.Request, error) {
 	// Add any query parameters to the URL.
 	if len(request.QueryParams) != 0 {
 		request.BaseURL = AddQueryParameters(request.BaseURL, request.QueryParams)
 	}
-	req, err := http.NewRequest(string(request.Method), request.BaseURL, bytes.NewBuffer(request.Body))
+	req, err := http.NewRequestWithContext(ctx, string(request.Method), request.BaseURL, bytes.NewBuffer(request.Body))
 	if err != nil {
 		return req, err
 	}
@@ -0,0 +1,129 @@
+package client_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/kenmobility/git-api-service/pkg/client"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRestClientVerbs(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		w.Header().Set("X-Method", r.Method)
+		w.Header().Set("X-Query", r.URL.Query().Get("q"))
+		w.Header().Set("X-Token", r.Header.Get("X-Token"))
+		w.Write(body)
+	}))
+	defer server.Close()
+
+	restClient := client.NewRestClient()
+	ctx := context.Background()
+
+	testCases := []struct {
+		method string
+		send   func(opts ...client.RequestOption) (*client.Response, error)
+	}{
+		{http.MethodGet, func(opts ...client.RequestOption) (*client.Response, error) {
+			return restClient.Get(ctx, server.URL, opts...)
+		}},
+		{http.MethodPost, func(opts ...client.RequestOption) (*client.Response, error) {
+			return restClient.Post(ctx, server.URL, opts...)
+		}},
+		{http.MethodPut, func(opts ...client.RequestOption) (*client.Response, error) {
+			return restClient.Put(ctx, server.URL, opts...)
+		}},
+		{http.MethodPatch, func(opts ...client.RequestOption) (*client.Response, error) {
+			return restClient.Patch(ctx, server.URL, opts...)
+		}},
+		{http.MethodDelete, func(opts ...client.RequestOption) (*client.Response, error) {
+			return restClient.Delete(ctx, server.URL, opts...)
+		}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.method, func(t *testing.T) {
+			resp, err := tc.send(client.WithQuery(map[string]string{"q": "search"}), client.WithHeader("X-Token", "secret"), client.WithBody([]byte(`{"a":1}`)))
+			require.NoError(t, err)
+			require.Equal(t, tc.method, resp.Headers["X-Method"][0])
+			require.Equal(t, "search", resp.Headers["X-Query"][0])
+			require.Equal(t, "secret", resp.Headers["X-Token"][0])
+			require.Equal(t, `{"a":1}`, resp.Body)
+		})
+	}
+}
+
+func TestRestClientContextCancellation(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-time.After(5 * time.Second):
+		}
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	_, err := client.NewRestClient().Get(ctx, server.URL)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	require.Less(t, time.Since(start), time.Second)
+}
+
+func TestRestClientTimeout(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-time.After(5 * time.Second):
+		}
+	}))
+	defer server.Close()
+
+	restClient := client.NewRestClient(client.WithTimeout(20*time.Millisecond), client.WithRetryPolicy(client.RetryPolicy{}))
+
+	start := time.Now()
+	_, err := restClient.Get(context.Background(), server.URL)
+	require.Error(t, err)
+	require.Less(t, time.Since(start), time.Second)
+}
+
+func TestDoJSON(t *testing.T) {
+	type item struct {
+		Name  string `json:"name"`
+		Count int    `json:"count"`
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/missing" {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"message": "Not Found"}`))
+			return
+		}
+
+		require.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		body, _ := io.ReadAll(r.Body)
+		w.WriteHeader(http.StatusCreated)
+		w.Write(body)
+	}))
+	defer server.Close()
+
+	restClient := client.NewRestClient()
+
+	created, resp, err := client.DoJSON[item](context.Background(), restClient, client.Post, server.URL+"/items", client.WithJSONBody(item{Name: "repo", Count: 2}))
+	require.NoError(t, err)
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+	require.Equal(t, item{Name: "repo", Count: 2}, created)
+
+	_, resp, err = client.DoJSON[item](context.Background(), restClient, client.Get, server.URL+"/missing")
+	var statusErr *client.StatusError
+	require.True(t, errors.As(err, &statusErr))
+	require.Equal(t, http.StatusNotFound, statusErr.StatusCode)
+	require.Equal(t, http.StatusNotFound, resp.StatusCode)
+}
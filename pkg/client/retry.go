@@ -0,0 +1,147 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrSecondaryRateLimit is the cause of requests rejected by a rate limit other than the primary budget of
+// the token, such as GitHub's secondary (abuse) rate limits, they are retried after Retry-After
+var ErrSecondaryRateLimit = errors.New("secondary rate limit exceeded")
+
+// ErrServerError is the cause of requests failing with a 5xx status code
+var ErrServerError = errors.New("server error")
+
+// secondary rate limits without Retry-After are retried after at least a minute, as GitHub recommends
+const secondaryRateLimitMinDelay = time.Minute
+
+// RetryPolicy configures how failed requests are retried, 5xx responses, network errors and secondary rate
+// limits are retried with jittered exponential backoff, or after Retry-After when the response has one
+type RetryPolicy struct {
+	// MaxRetries is the number of retries after the first attempt
+	MaxRetries int
+	BaseDelay  time.Duration
+	MaxDelay   time.Duration
+	// MaxRetryAfter is the longest Retry-After honoured, requests asked to wait longer are not retried
+	MaxRetryAfter time.Duration
+}
+
+// DefaultRetryPolicy is the retry policy of the clients returned by NewRestClient
+var DefaultRetryPolicy = RetryPolicy{
+	MaxRetries:    3,
+	BaseDelay:     500 * time.Millisecond,
+	MaxDelay:      30 * time.Second,
+	MaxRetryAfter: 2 * time.Minute,
+}
+
+// RetryError is returned once a request failed on every attempt
+type RetryError struct {
+	Attempts int
+	// StatusCode is the status code of the last response, 0 when the last attempt failed without one
+	StatusCode int
+	Cause      error
+}
+
+func (e *RetryError) Error() string {
+	if e.StatusCode != 0 {
+		return fmt.Sprintf("request failed after %d attempts; status code: %d: %v", e.Attempts, e.StatusCode, e.Cause)
+	}
+	return fmt.Sprintf("request failed after %d attempts: %v", e.Attempts, e.Cause)
+}
+
+func (e *RetryError) Unwrap() error {
+	return e.Cause
+}
+
+// IsPrimaryRateLimit reports whether the request was rejected because its token has no budget left,
+// such requests are not retried as the budget is only restored at X-Ratelimit-Reset
+func IsPrimaryRateLimit(resp *Response) bool {
+	if resp.StatusCode != http.StatusForbidden && resp.StatusCode != http.StatusTooManyRequests {
+		return false
+	}
+	remaining := resp.Headers["X-Ratelimit-Remaining"]
+	return len(remaining) > 0 && remaining[0] == "0"
+}
+
+// IsSecondaryRateLimit reports whether the request was rejected by a rate limit other than the primary
+// budget, a 403 without Retry-After nor a secondary rate limit message is a permission error
+func IsSecondaryRateLimit(resp *Response) bool {
+	if IsPrimaryRateLimit(resp) {
+		return false
+	}
+
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests:
+		return true
+	case http.StatusForbidden:
+		return len(resp.Headers["Retry-After"]) > 0 || strings.Contains(strings.ToLower(resp.Body), "secondary rate limit")
+	}
+	return false
+}
+
+// retryAfter returns the delay asked by the Retry-After header, given in seconds or as an http date
+func retryAfter(resp *Response) (time.Duration, bool) {
+	values := resp.Headers["Retry-After"]
+	if len(values) == 0 {
+		return 0, false
+	}
+
+	if seconds, err := strconv.Atoi(strings.TrimSpace(values[0])); err == nil {
+		return time.Duration(seconds) * time.Second, true
+	}
+
+	if date, err := http.ParseTime(values[0]); err == nil {
+		return time.Until(date), true
+	}
+	return 0, false
+}
+
+// backoff returns a jittered exponential delay for the retry
+func (p RetryPolicy) backoff(retry int) time.Duration {
+	delay := p.BaseDelay << retry
+	if delay <= 0 || delay > p.MaxDelay {
+		delay = p.MaxDelay
+	}
+	if delay <= 0 {
+		return 0
+	}
+
+	// full jitter spreads the retries of concurrent requests
+	return time.Duration(rand.Int64N(int64(delay))) + 1
+}
+
+// retryDelay returns how long to wait before retrying the attempt, and false with its cause when it should not
+// be retried. retry is the number of retries made so far
+func (p RetryPolicy) retryDelay(retry int, resp *Response, err error) (time.Duration, bool, error) {
+	if err != nil {
+		return p.backoff(retry), true, err
+	}
+
+	switch {
+	case IsSecondaryRateLimit(resp):
+		if delay, ok := retryAfter(resp); ok {
+			if delay > p.MaxRetryAfter {
+				return 0, false, ErrSecondaryRateLimit
+			}
+			return delay, true, ErrSecondaryRateLimit
+		}
+		if resp.StatusCode == http.StatusForbidden {
+			return max(p.backoff(retry), secondaryRateLimitMinDelay), true, ErrSecondaryRateLimit
+		}
+		return p.backoff(retry), true, ErrSecondaryRateLimit
+
+	case resp.StatusCode >= http.StatusInternalServerError:
+		cause := fmt.Errorf("%w: %s", ErrServerError, http.StatusText(resp.StatusCode))
+		if delay, ok := retryAfter(resp); ok && delay <= p.MaxRetryAfter {
+			return delay, true, cause
+		}
+		return p.backoff(retry), true, cause
+	}
+
+	return 0, false, nil
+}
@@ -0,0 +1,137 @@
+package client_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/kenmobility/git-api-service/pkg/client"
+	"github.com/stretchr/testify/require"
+)
+
+var testRetryPolicy = client.RetryPolicy{
+	MaxRetries:    2,
+	BaseDelay:     time.Millisecond,
+	MaxDelay:      10 * time.Millisecond,
+	MaxRetryAfter: 2 * time.Second,
+}
+
+// newRetryTestServer serves the responses in turn, repeating the last one
+func newRetryTestServer(attempts *atomic.Int32, responses ...func(w http.ResponseWriter)) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		i := int(attempts.Add(1)) - 1
+		if i >= len(responses) {
+			i = len(responses) - 1
+		}
+		responses[i](w)
+	}))
+}
+
+func status(code int, headers ...string) func(w http.ResponseWriter) {
+	return func(w http.ResponseWriter) {
+		for i := 0; i+1 < len(headers); i += 2 {
+			w.Header().Set(headers[i], headers[i+1])
+		}
+		w.WriteHeader(code)
+	}
+}
+
+func TestRetryServerErrors(t *testing.T) {
+	var attempts atomic.Int32
+	server := newRetryTestServer(&attempts, status(http.StatusBadGateway), status(http.StatusServiceUnavailable), status(http.StatusOK))
+	defer server.Close()
+
+	resp, err := client.NewRestClient(client.WithRetryPolicy(testRetryPolicy)).Get(context.Background(), server.URL)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.Equal(t, int32(3), attempts.Load())
+}
+
+func TestRetryGivesUp(t *testing.T) {
+	var attempts atomic.Int32
+	server := newRetryTestServer(&attempts, status(http.StatusBadGateway))
+	defer server.Close()
+
+	_, err := client.NewRestClient(client.WithRetryPolicy(testRetryPolicy)).Get(context.Background(), server.URL)
+	require.ErrorIs(t, err, client.ErrServerError)
+
+	var retryErr *client.RetryError
+	require.True(t, errors.As(err, &retryErr))
+	require.Equal(t, 3, retryErr.Attempts)
+	require.Equal(t, http.StatusBadGateway, retryErr.StatusCode)
+	require.Equal(t, int32(3), attempts.Load())
+}
+
+func TestRetryNetworkErrors(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	url := server.URL
+	server.Close()
+
+	_, err := client.NewRestClient(client.WithRetryPolicy(testRetryPolicy)).Get(context.Background(), url)
+
+	var retryErr *client.RetryError
+	require.True(t, errors.As(err, &retryErr))
+	require.Equal(t, 3, retryErr.Attempts)
+	require.Zero(t, retryErr.StatusCode)
+}
+
+func TestRetryHonoursRetryAfter(t *testing.T) {
+	var attempts atomic.Int32
+	server := newRetryTestServer(&attempts, status(http.StatusTooManyRequests, "Retry-After", "1"), status(http.StatusOK))
+	defer server.Close()
+
+	start := time.Now()
+	resp, err := client.NewRestClient(client.WithRetryPolicy(testRetryPolicy)).Get(context.Background(), server.URL)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.GreaterOrEqual(t, time.Since(start), time.Second)
+}
+
+func TestRetryAfterTooLong(t *testing.T) {
+	var attempts atomic.Int32
+	server := newRetryTestServer(&attempts, status(http.StatusForbidden, "Retry-After", "3600"))
+	defer server.Close()
+
+	_, err := client.NewRestClient(client.WithRetryPolicy(testRetryPolicy)).Get(context.Background(), server.URL)
+	require.ErrorIs(t, err, client.ErrSecondaryRateLimit)
+	require.Equal(t, int32(1), attempts.Load())
+}
+
+func TestRetryRateLimitsAndPermissionErrors(t *testing.T) {
+	secondary := func(w http.ResponseWriter) {
+		w.Header().Set("Retry-After", "0")
+		w.WriteHeader(http.StatusForbidden)
+		w.Write([]byte(`{"message": "You have exceeded a secondary rate limit."}`))
+	}
+
+	testCases := []struct {
+		name      string
+		responses []func(w http.ResponseWriter)
+		attempts  int32
+		primary   bool
+		secondary bool
+	}{
+		// the primary budget is only restored at its reset so it is not retried
+		{"primary", []func(w http.ResponseWriter){status(http.StatusForbidden, "X-Ratelimit-Remaining", "0")}, 1, true, false},
+		{"permission", []func(w http.ResponseWriter){status(http.StatusForbidden, "X-Ratelimit-Remaining", "4000")}, 1, false, false},
+		{"secondary", []func(w http.ResponseWriter){secondary, status(http.StatusOK)}, 2, false, false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var attempts atomic.Int32
+			server := newRetryTestServer(&attempts, tc.responses...)
+			defer server.Close()
+
+			resp, err := client.NewRestClient(client.WithRetryPolicy(testRetryPolicy)).Get(context.Background(), server.URL)
+			require.NoError(t, err)
+			require.Equal(t, tc.attempts, attempts.Load())
+			require.Equal(t, tc.primary, client.IsPrimaryRateLimit(resp))
+			require.Equal(t, tc.secondary, client.IsSecondaryRateLimit(resp))
+		})
+	}
+}
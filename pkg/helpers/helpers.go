@@ -35,9 +35,33 @@ func Getenv(variable string, defaultValue ...string) string {
 	return env
 }
 
-// IsRepositoryNameValid validates the repository name
+// IsRepositoryNameValid validates the repository name, which can be owner/repo, a host-qualified
+// name eg gitlab.example.com/group/repo, or a clone url eg https://github.com/owner/repo.git
 func IsRepositoryNameValid(repoName string) bool {
-	return strings.Contains(repoName, "/")
+	name := strings.TrimSpace(repoName)
+
+	if strings.HasPrefix(name, "file://") {
+		return len(name) > len("file://")
+	}
+
+	if _, rest, ok := strings.Cut(name, "://"); ok {
+		name = rest
+	} else if _, rest, ok := strings.Cut(name, "@"); ok {
+		// scp-like clone url, eg git@github.com:owner/repo.git
+		name = strings.Replace(rest, ":", "/", 1)
+	}
+
+	segments := strings.Split(strings.Trim(strings.TrimSuffix(name, ".git"), "/"), "/")
+	if len(segments) < 2 {
+		return false
+	}
+
+	for _, s := range segments {
+		if s == "" || strings.ContainsAny(s, " \t") {
+			return false
+		}
+	}
+	return true
 }
 
 // ValidateInput validates structs fields with tags
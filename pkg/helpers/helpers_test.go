@@ -49,7 +49,14 @@ func TestGetenv(t *testing.T) {
 // Test IsRepositoryNameValid function
 func TestIsRepositoryNameValid(t *testing.T) {
 	assert.True(t, helpers.IsRepositoryNameValid("owner/repo"))
+	assert.True(t, helpers.IsRepositoryNameValid("gitlab.example.com/group/subgroup/repo"))
+	assert.True(t, helpers.IsRepositoryNameValid("https://github.com/owner/repo.git"))
+	assert.True(t, helpers.IsRepositoryNameValid("git@github.com:owner/repo.git"))
+	assert.True(t, helpers.IsRepositoryNameValid("file:///srv/mirrors/foo.git"))
 	assert.False(t, helpers.IsRepositoryNameValid("invalid_repo_name"))
+	assert.False(t, helpers.IsRepositoryNameValid("owner/"))
+	assert.False(t, helpers.IsRepositoryNameValid("https://github.com/"))
+	assert.False(t, helpers.IsRepositoryNameValid("owner/my repo"))
 }
 
 // Test ValidateInput function
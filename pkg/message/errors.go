@@ -10,8 +10,39 @@ var (
 	ErrDefaultRepoAlreadySeeded = errors.New("default repo already seeded")
 	ErrRepoAlreadyAdded         = errors.New("repository is already added")
 
-	ErrRepoMetaDataNotFetched = errors.New("repository metadata not fetched, ensure repository is valid and public")
-	ErrInvalidRepositoryName  = errors.New("invalid repository name, eg format is {owner/repositoryName}")
+	ErrRepoMetaDataNotFetched  = errors.New("repository metadata not fetched, ensure repository is valid and public")
+	ErrRepositoryNotFound      = errors.New("repository not found on the git host, it was deleted or made private")
+	ErrRepositoryInaccessible  = errors.New("repository is not accessible on the git host, access to it is blocked")
+	ErrInvalidRepositoryName   = errors.New("invalid repository name, eg format is {owner/repositoryName}")
+	ErrUnsupportedGitHost      = errors.New("unsupported git host, no git provider is configured for the repository host")
+	ErrGitHubAppNotInstalled   = errors.New("the github app is not installed on the repository")
+	ErrInvalidCommitId         = errors.New("invalid commit ID")
+	ErrInvalidBranchPattern    = errors.New("invalid branch, branches are names or glob patterns eg release/*")
+	ErrCommitNotFetched        = errors.New("commit not fetched, it was not found on the git host")
+	ErrInvalidPullRequestState = errors.New("invalid state, pull request states are open, closed or merged")
+	ErrInvalidTimeRange        = errors.New("invalid time range, since and until are RFC3339 dates eg 2024-01-02T15:04:05Z and since is not after until")
+
+	ErrWebhookNotConfigured     = errors.New("webhooks are not configured, set GITHUB_WEBHOOK_SECRET to receive them")
+	ErrInvalidWebhookSignature  = errors.New("invalid webhook signature")
+	ErrUnsupportedWebhookEvent  = errors.New("unsupported webhook event, only push events are processed")
+	ErrRepositoryNotTracked     = errors.New("repository is not tracked")
+	ErrDuplicateWebhookDelivery = errors.New("webhook delivery was already processed")
+
+	ErrCredentialsNotConfigured = errors.New("credentials are not configured, set CREDENTIALS_ENCRYPTION_KEY to store them")
+	ErrCredentialNotFound       = errors.New("credential not found")
+	ErrCredentialAlreadyAdded   = errors.New("a credential with this name is already added")
+	ErrAmbiguousCredential      = errors.New("give either the name of a stored credential or a token, not both")
+	ErrCredentialsNotSupported  = errors.New("credentials are not supported by the git host of the repository")
+
+	ErrJobLockLost      = errors.New("job lock lost, the job was claimed again by another worker")
+	ErrJobNotDead       = errors.New("only dead jobs can be requeued")
+	ErrJobAlreadyQueued = errors.New("the repository already has a pending or running job of this kind")
+	ErrInvalidJobStatus = errors.New("invalid status, job states are pending, running, done or dead")
+	ErrUnknownJobKind   = errors.New("unknown job kind")
+
+	ErrRepoLeaseLost = errors.New("repository lease lost, it expired and was acquired by another instance")
+
+	ErrLocalRepositoryOutsideRoot = errors.New("local repository path is outside of LOCAL_REPOSITORIES_ROOT")
 
 	ErrRateLimitExceeded = errors.New("rate limit exceeded")
 	ErrContextCancelled  = errors.New("context cancelled")
@@ -0,0 +1,47 @@
+package secrets_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/kenmobility/git-api-service/pkg/secrets"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCipherRoundTrip(t *testing.T) {
+	c, err := secrets.NewCipher(bytes.Repeat([]byte{1}, secrets.KeySize))
+	require.NoError(t, err)
+
+	encrypted, err := c.Encrypt("ghp_secret")
+	require.NoError(t, err)
+	assert.NotContains(t, encrypted, "ghp_secret")
+
+	// each encryption uses its own nonce
+	again, err := c.Encrypt("ghp_secret")
+	require.NoError(t, err)
+	assert.NotEqual(t, encrypted, again)
+
+	decrypted, err := c.Decrypt(encrypted)
+	require.NoError(t, err)
+	assert.Equal(t, "ghp_secret", decrypted)
+}
+
+func TestCipherRejectsOtherKeys(t *testing.T) {
+	_, err := secrets.NewCipher([]byte("short"))
+	assert.Error(t, err)
+
+	c, err := secrets.NewCipher(bytes.Repeat([]byte{1}, secrets.KeySize))
+	require.NoError(t, err)
+	other, err := secrets.NewCipher(bytes.Repeat([]byte{2}, secrets.KeySize))
+	require.NoError(t, err)
+
+	encrypted, err := c.Encrypt("ghp_secret")
+	require.NoError(t, err)
+
+	_, err = other.Decrypt(encrypted)
+	assert.Error(t, err)
+
+	_, err = c.Decrypt("not base64!")
+	assert.Equal(t, secrets.ErrMalformedCiphertext, err)
+}